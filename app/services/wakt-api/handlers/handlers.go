@@ -11,6 +11,7 @@ import (
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/debug/checkgrp"
 	v1 "github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1"
 	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
 	"github.com/AhmedShaef/wakt/business/web/v1/mid"
 	"github.com/AhmedShaef/wakt/foundation/web"
 	"github.com/jmoiron/sqlx"
@@ -35,6 +36,7 @@ type APIMuxConfig struct {
 	Log      *zap.SugaredLogger
 	Auth     *auth.Auth
 	DB       *sqlx.DB
+	OIDC     oidc.Provider
 }
 
 // APIMux constructs a fiber.Handler with all application routes defined.
@@ -82,6 +84,7 @@ func APIMux(cfg APIMuxConfig, options ...func(opts *Options)) *web.App {
 		Log:  cfg.Log,
 		Auth: cfg.Auth,
 		DB:   cfg.DB,
+		OIDC: cfg.OIDC,
 	})
 
 	return app
@@ -0,0 +1,247 @@
+// Package accountcodegrp maintains the group of handlers for account code access.
+package accountcodegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AhmedShaef/wakt/business/core/accountcode"
+	"github.com/AhmedShaef/wakt/business/core/client"
+	"github.com/AhmedShaef/wakt/business/core/project"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of account code endpoints.
+type Handlers struct {
+	AccountCode   accountcode.Core
+	Project       project.Core
+	Client        client.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// Create adds a new account code to the system.
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nac accountcode.NewAccountCode
+	if err := web.Decode(r, &nac); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.authorizeAdmin(ctx, nac.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.checkTarget(ctx, nac); err != nil {
+		return err
+	}
+
+	accountCode, err := h.AccountCode.Create(ctx, nac, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, accountcode.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, accountcode.ErrInvalidTarget):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, accountcode.ErrUniqueTarget):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("accountCode[%+v]: %w", &accountCode, err)
+		}
+	}
+
+	return web.Respond(ctx, w, accountCode, http.StatusCreated)
+}
+
+// Update updates a account code in the system.
+func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var uac accountcode.UpdateAccountCode
+	if err := web.Decode(r, &uac); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	accountCodeID := web.Param(r, "id")
+
+	accountCode, err := h.AccountCode.QueryByID(ctx, accountCodeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, accountcode.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, accountcode.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying accountCode[%s]: %w", accountCodeID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, accountCode.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.AccountCode.Update(ctx, accountCodeID, uac, v.Now); err != nil {
+		switch {
+		case errors.Is(err, accountcode.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, accountcode.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] AccountCode[%+v]: %w", accountCodeID, &uac, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Delete removes a account code from the system.
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	accountCodeID := web.Param(r, "id")
+
+	accountCode, err := h.AccountCode.QueryByID(ctx, accountCodeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, accountcode.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, accountcode.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying accountCode[%s]: %w", accountCodeID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, accountCode.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.AccountCode.Delete(ctx, accountCodeID); err != nil {
+		switch {
+		case errors.Is(err, accountcode.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", accountCodeID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryWorkspaceAccountCodes returns a list of workspace account codes with paging.
+func (h Handlers) QueryWorkspaceAccountCodes(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+	page := web.Param(r, "page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid page format, page[%s]", page), http.StatusBadRequest)
+	}
+	rows := web.Param(r, "rows")
+	rowsPerPage, err := strconv.Atoi(rows)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
+	}
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	accountCodes, err := h.AccountCode.QueryWorkspaceAccountCodes(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		return fmt.Errorf("unable to query for account codes: %w", err)
+	}
+
+	return web.Respond(ctx, w, accountCodes, http.StatusOK)
+}
+
+// checkTarget checks that the project or the client of a new account code is
+// part of the workspace of the account code.
+func (h Handlers) checkTarget(ctx context.Context, nac accountcode.NewAccountCode) error {
+	if nac.PID != "" {
+		projects, err := h.Project.QueryByID(ctx, nac.PID)
+		if err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return fmt.Errorf("querying project[%s]: %w", nac.PID, err)
+			}
+		}
+		if projects.WID != nac.WID {
+			return v1Web.NewRequestError(project.ErrNotFound, http.StatusNotFound)
+		}
+	}
+
+	if nac.CID != "" {
+		clients, err := h.Client.QueryByID(ctx, nac.CID)
+		if err != nil {
+			switch {
+			case errors.Is(err, client.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, client.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return fmt.Errorf("querying client[%s]: %w", nac.CID, err)
+			}
+		}
+		if clients.WID != nac.WID {
+			return v1Web.NewRequestError(client.ErrNotFound, http.StatusNotFound)
+		}
+	}
+
+	return nil
+}
+
+// authorizeAdmin checks that the user is an admin of the workspace. Account
+// codes belong to the bookkeeping of the workspace and only admins manage them.
+func (h Handlers) authorizeAdmin(ctx context.Context, workspaceID, userID string) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
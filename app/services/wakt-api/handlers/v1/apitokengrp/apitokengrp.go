@@ -0,0 +1,99 @@
+// Package apitokengrp maintains the group of handlers for personal API token access.
+package apitokengrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AhmedShaef/wakt/business/core/apitoken"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of personal API token endpoints.
+type Handlers struct {
+	APIToken apitoken.Core
+}
+
+// Create adds a new personal API token for the authenticated user.
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nt apitoken.NewToken
+	if err := web.Decode(r, &nt); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	token, err := h.APIToken.Create(ctx, claims.Subject, nt, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, apitoken.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, apitoken.ErrInvalidExpiry):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("token[%+v]: %w", &nt, err)
+		}
+	}
+
+	return web.Respond(ctx, w, token, http.StatusCreated)
+}
+
+// Delete revokes a personal API token of the authenticated user.
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	tokenID := web.Param(r, "id")
+
+	token, err := h.APIToken.QueryByID(ctx, tokenID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apitoken.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, apitoken.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s]: %w", tokenID, err)
+		}
+	}
+
+	// Users can only revoke their own tokens.
+	if token.UID != claims.Subject {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.APIToken.Delete(ctx, tokenID); err != nil {
+		return fmt.Errorf("ID[%s]: %w", tokenID, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryUserTokens returns the personal API tokens of the authenticated user.
+func (h Handlers) QueryUserTokens(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	tokens, err := h.APIToken.QueryUserTokens(ctx, claims.Subject)
+	if err != nil {
+		return fmt.Errorf("unable to query for tokens: %w", err)
+	}
+
+	return web.Respond(ctx, w, tokens, http.StatusOK)
+}
@@ -154,6 +154,8 @@ func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Req
 		switch {
 		case errors.Is(err, client.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, client.ErrInUse):
+			return v1Web.NewRequestError(err, http.StatusConflict)
 		default:
 			return fmt.Errorf("ID[%s]: %w", clientID, err)
 		}
@@ -253,10 +255,220 @@ func (h Handlers) QueryClientProjects(ctx context.Context, w http.ResponseWriter
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
-	work, err := h.Project.QueryClientProjects(ctx, clientID, pageNumber, rowsPerPage)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	work, err := h.Project.QueryClientProjects(ctx, clientID, claims.Subject, includeArchived, pageNumber, rowsPerPage)
 	if err != nil {
 		return fmt.Errorf("unable to query for client project: %w", err)
 	}
 
 	return web.Respond(ctx, w, work, http.StatusOK)
 }
+
+// Archive hides a client and its projects from the lists.
+func (h Handlers) Archive(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.archive(ctx, w, r, true)
+}
+
+// Unarchive brings back a client and its projects to the lists.
+func (h Handlers) Unarchive(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.archive(ctx, w, r, false)
+}
+
+// CreateContact adds a new contact to a client.
+func (h Handlers) CreateContact(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nc client.NewContact
+	if err := web.Decode(r, &nc); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	clientID := web.Param(r, "id")
+
+	if err := h.authorizeOwner(ctx, clientID, claims.Subject); err != nil {
+		return err
+	}
+
+	contact, err := h.Client.CreateContact(ctx, clientID, nc, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("contact[%+v]: %w", &nc, err)
+		}
+	}
+
+	return web.Respond(ctx, w, contact, http.StatusCreated)
+}
+
+// UpdateContact updates a contact of a client.
+func (h Handlers) UpdateContact(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var upd client.UpdateContact
+	if err := web.Decode(r, &upd); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	clientID := web.Param(r, "id")
+	contactID := web.Param(r, "contact_id")
+
+	if err := h.authorizeContact(ctx, clientID, contactID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Client.UpdateContact(ctx, contactID, upd, v.Now); err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, client.ErrContactNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] Contact[%+v]: %w", contactID, &upd, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// DeleteContact removes a contact of a client.
+func (h Handlers) DeleteContact(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	clientID := web.Param(r, "id")
+	contactID := web.Param(r, "contact_id")
+
+	if err := h.authorizeContact(ctx, clientID, contactID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Client.DeleteContact(ctx, contactID); err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", contactID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryClientContacts returns the contacts of a client.
+func (h Handlers) QueryClientContacts(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	clientID := web.Param(r, "id")
+
+	if err := h.authorizeOwner(ctx, clientID, claims.Subject); err != nil {
+		return err
+	}
+
+	contacts, err := h.Client.QueryClientContacts(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("unable to query for client contacts: %w", err)
+	}
+
+	return web.Respond(ctx, w, contacts, http.StatusOK)
+}
+
+// archive sets the archive state of a client and its projects.
+func (h Handlers) archive(ctx context.Context, w http.ResponseWriter, r *http.Request, archived bool) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	clientID := web.Param(r, "id")
+
+	if err := h.authorizeOwner(ctx, clientID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Client.Archive(ctx, clientID, archived, v.Now); err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, client.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s]: %w", clientID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// authorizeOwner checks that the client exists and is owned by the user.
+func (h Handlers) authorizeOwner(ctx context.Context, clientID, userID string) error {
+	clients, err := h.Client.QueryByID(ctx, clientID)
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, client.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying client[%s]: %w", clientID, err)
+		}
+	}
+
+	if userID != clients.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// authorizeContact checks that the contact belongs to a client owned by the user.
+func (h Handlers) authorizeContact(ctx context.Context, clientID, contactID, userID string) error {
+	if err := h.authorizeOwner(ctx, clientID, userID); err != nil {
+		return err
+	}
+
+	contact, err := h.Client.QueryContactByID(ctx, contactID)
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, client.ErrContactNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying contact[%s]: %w", contactID, err)
+		}
+	}
+
+	if contact.CID != clientID {
+		return v1Web.NewRequestError(client.ErrContactNotFound, http.StatusNotFound)
+	}
+
+	return nil
+}
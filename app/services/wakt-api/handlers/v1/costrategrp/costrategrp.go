@@ -0,0 +1,206 @@
+// Package costrategrp maintains the group of handlers for cost rate access.
+package costrategrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AhmedShaef/wakt/business/core/costrate"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of cost rate endpoints.
+type Handlers struct {
+	CostRate      costrate.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// Create adds a new cost rate to the system.
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var ncr costrate.NewCostRate
+	if err := web.Decode(r, &ncr); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.authorizeAdmin(ctx, ncr.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if _, err := h.WorkspaceUser.QueryByuIDwID(ctx, ncr.WID, ncr.UID); err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", ncr.UID, err)
+		}
+	}
+
+	costRate, err := h.CostRate.Create(ctx, ncr, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, costrate.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("costRate[%+v]: %w", &costRate, err)
+		}
+	}
+
+	return web.Respond(ctx, w, costRate, http.StatusCreated)
+}
+
+// Update updates a cost rate in the system.
+func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var ucr costrate.UpdateCostRate
+	if err := web.Decode(r, &ucr); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	costRateID := web.Param(r, "id")
+
+	costRate, err := h.CostRate.QueryByID(ctx, costRateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, costrate.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, costrate.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying costRate[%s]: %w", costRateID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, costRate.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.CostRate.Update(ctx, costRateID, ucr, v.Now); err != nil {
+		switch {
+		case errors.Is(err, costrate.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, costrate.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] CostRate[%+v]: %w", costRateID, &ucr, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Delete removes a cost rate from the system.
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	costRateID := web.Param(r, "id")
+
+	costRate, err := h.CostRate.QueryByID(ctx, costRateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, costrate.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, costrate.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying costRate[%s]: %w", costRateID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, costRate.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.CostRate.Delete(ctx, costRateID); err != nil {
+		switch {
+		case errors.Is(err, costrate.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", costRateID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryWorkspaceCostRates returns a list of workspace cost rates with paging.
+func (h Handlers) QueryWorkspaceCostRates(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+	page := web.Param(r, "page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid page format, page[%s]", page), http.StatusBadRequest)
+	}
+	rows := web.Param(r, "rows")
+	rowsPerPage, err := strconv.Atoi(rows)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
+	}
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	costRates, err := h.CostRate.QueryWorkspaceCostRates(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		return fmt.Errorf("unable to query for cost rates: %w", err)
+	}
+
+	return web.Respond(ctx, w, costRates, http.StatusOK)
+}
+
+// authorizeAdmin checks that the user is an admin of the workspace. Cost rates
+// are internal data and are never shown to regular members.
+func (h Handlers) authorizeAdmin(ctx context.Context, workspaceID, userID string) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
@@ -0,0 +1,430 @@
+// Package customfieldgrp maintains the group of handlers for custom field access.
+package customfieldgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AhmedShaef/wakt/business/core/customfield"
+	"github.com/AhmedShaef/wakt/business/core/project"
+	"github.com/AhmedShaef/wakt/business/core/task"
+	"github.com/AhmedShaef/wakt/business/core/timeentry"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of custom field endpoints.
+type Handlers struct {
+	CustomField   customfield.Core
+	Project       project.Core
+	Task          task.Core
+	TimeEntry     timeentry.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// Create adds a new custom field to a workspace.
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nf customfield.NewField
+	if err := web.Decode(r, &nf); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	workspaceID := web.Param(r, "id")
+
+	if err := h.authorize(ctx, workspaceID, claims.Subject, true); err != nil {
+		return err
+	}
+
+	field, err := h.CustomField.Create(ctx, workspaceID, nf, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, customfield.ErrNoOptions):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, customfield.ErrNameTaken):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("field[%+v]: %w", &nf, err)
+		}
+	}
+
+	return web.Respond(ctx, w, field, http.StatusCreated)
+}
+
+// Update updates a custom field in the system.
+func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var uf customfield.UpdateField
+	if err := web.Decode(r, &uf); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	fieldID := web.Param(r, "id")
+
+	field, err := h.queryField(ctx, fieldID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, field.WID, claims.Subject, true); err != nil {
+		return err
+	}
+
+	if err := h.CustomField.Update(ctx, fieldID, uf, v.Now); err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, customfield.ErrNoOptions):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, customfield.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, customfield.ErrNameTaken):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("ID[%s] field[%+v]: %w", fieldID, &uf, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Delete removes a custom field with its values from the system.
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	fieldID := web.Param(r, "id")
+
+	field, err := h.queryField(ctx, fieldID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, field.WID, claims.Subject, true); err != nil {
+		return err
+	}
+
+	if err := h.CustomField.Delete(ctx, fieldID); err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", fieldID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryByID returns a custom field by its ID.
+func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	fieldID := web.Param(r, "id")
+
+	field, err := h.queryField(ctx, fieldID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, field.WID, claims.Subject, false); err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, field, http.StatusOK)
+}
+
+// QueryWorkspaceFields returns the custom fields of a workspace, only the
+// ones of an entity type when the entity query parameter is set.
+func (h Handlers) QueryWorkspaceFields(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+	entity := r.URL.Query().Get("entity")
+
+	if err := h.authorize(ctx, workspaceID, claims.Subject, false); err != nil {
+		return err
+	}
+
+	fields, err := h.CustomField.QueryWorkspaceFields(ctx, workspaceID, entity)
+	if err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, customfield.ErrInvalidKey):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for fields: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, fields, http.StatusOK)
+}
+
+// QueryProjectValues returns the custom field values of a project.
+func (h Handlers) QueryProjectValues(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.queryValues(ctx, w, r, customfield.EntityProject)
+}
+
+// SetProjectValues sets the custom field values of a project.
+func (h Handlers) SetProjectValues(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setValues(ctx, w, r, customfield.EntityProject)
+}
+
+// QueryTaskValues returns the custom field values of a task.
+func (h Handlers) QueryTaskValues(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.queryValues(ctx, w, r, customfield.EntityTask)
+}
+
+// SetTaskValues sets the custom field values of a task.
+func (h Handlers) SetTaskValues(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setValues(ctx, w, r, customfield.EntityTask)
+}
+
+// QueryTimeEntryValues returns the custom field values of a time entry.
+func (h Handlers) QueryTimeEntryValues(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.queryValues(ctx, w, r, customfield.EntityTimeEntry)
+}
+
+// SetTimeEntryValues sets the custom field values of a time entry.
+func (h Handlers) SetTimeEntryValues(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.setValues(ctx, w, r, customfield.EntityTimeEntry)
+}
+
+// queryValues returns the custom field values of an entity.
+func (h Handlers) queryValues(ctx context.Context, w http.ResponseWriter, r *http.Request, entity string) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	entityID := web.Param(r, "id")
+
+	if _, _, err := h.checkEntity(ctx, entity, entityID, claims.Subject, false); err != nil {
+		return err
+	}
+
+	values, err := h.CustomField.QueryValues(ctx, entityID)
+	if err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for values: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, values, http.StatusOK)
+}
+
+// setValues sets the custom field values of an entity. Required fields must
+// keep a value on projects, tasks and billable time entries.
+func (h Handlers) setValues(ctx context.Context, w http.ResponseWriter, r *http.Request, entity string) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var sv customfield.SetValues
+	if err := web.Decode(r, &sv); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	entityID := web.Param(r, "id")
+
+	workspaceID, complete, err := h.checkEntity(ctx, entity, entityID, claims.Subject, true)
+	if err != nil {
+		return err
+	}
+
+	values, err := h.CustomField.SetValues(ctx, entity, entityID, workspaceID, sv, complete, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s] values[%+v]: %w", entityID, &sv, err)
+		}
+	}
+
+	return web.Respond(ctx, w, values, http.StatusOK)
+}
+
+// checkEntity makes sure the user may see the entity, or change it when write
+// is set, and returns the workspace of the entity along with whether its
+// required fields must have a value.
+func (h Handlers) checkEntity(ctx context.Context, entity, entityID, userID string, write bool) (string, bool, error) {
+	switch entity {
+	case customfield.EntityProject:
+		projects, err := h.Project.QueryByID(ctx, entityID)
+		if err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return "", false, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return "", false, v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return "", false, fmt.Errorf("querying project[%s]: %w", entityID, err)
+			}
+		}
+
+		if err := h.authorize(ctx, projects.WID, userID, write); err != nil {
+			return "", false, err
+		}
+
+		if err := h.Project.CheckAccess(ctx, projects.ID, userID); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return "", false, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return "", false, v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return "", false, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return "", false, fmt.Errorf("checking access project[%s]: %w", projects.ID, err)
+			}
+		}
+
+		return projects.WID, true, nil
+
+	case customfield.EntityTask:
+		tasks, err := h.Task.QueryByID(ctx, entityID)
+		if err != nil {
+			switch {
+			case errors.Is(err, task.ErrInvalidID):
+				return "", false, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, task.ErrNotFound):
+				return "", false, v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return "", false, fmt.Errorf("querying task[%s]: %w", entityID, err)
+			}
+		}
+
+		if err := h.authorize(ctx, tasks.WID, userID, false); err != nil {
+			return "", false, err
+		}
+
+		if err := h.Project.CheckAccess(ctx, tasks.PID, userID); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return "", false, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return "", false, v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return "", false, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return "", false, fmt.Errorf("checking access project[%s]: %w", tasks.PID, err)
+			}
+		}
+
+		return tasks.WID, true, nil
+
+	default:
+		timeEntry, err := h.TimeEntry.QueryByID(ctx, entityID)
+		if err != nil {
+			switch {
+			case errors.Is(err, timeentry.ErrInvalidID):
+				return "", false, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, timeentry.ErrNotFound):
+				return "", false, v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return "", false, fmt.Errorf("querying time entry[%s]: %w", entityID, err)
+			}
+		}
+
+		if userID != timeEntry.UID {
+			return "", false, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		}
+
+		if err := h.Project.CheckOptionalAccess(ctx, timeEntry.PID, userID); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return "", false, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return "", false, v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return "", false, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return "", false, fmt.Errorf("checking access project[%s]: %w", timeEntry.PID, err)
+			}
+		}
+
+		return timeEntry.WID, timeEntry.Billable, nil
+	}
+}
+
+// queryField returns the custom field or the matching request error.
+func (h Handlers) queryField(ctx context.Context, fieldID string) (customfield.Field, error) {
+	field, err := h.CustomField.QueryByID(ctx, fieldID)
+	if err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return customfield.Field{}, v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, customfield.ErrNotFound):
+			return customfield.Field{}, v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return customfield.Field{}, fmt.Errorf("querying field[%s]: %w", fieldID, err)
+		}
+	}
+
+	return field, nil
+}
+
+// authorize makes sure the user is a member of the workspace, and an admin of
+// it when admin is set.
+func (h Handlers) authorize(ctx context.Context, workspaceID, userID string, admin bool) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if admin && !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
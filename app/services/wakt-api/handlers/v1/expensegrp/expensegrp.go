@@ -0,0 +1,407 @@
+// Package expensegrp maintains the group of handlers for expense access.
+package expensegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AhmedShaef/wakt/business/core/expense"
+	"github.com/AhmedShaef/wakt/business/core/project"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/upload"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of expense endpoints.
+type Handlers struct {
+	Expense       expense.Core
+	Project       project.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// Create adds a new expense to the system.
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var ne expense.NewExpense
+	if err := web.Decode(r, &ne); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	projects, err := h.Project.QueryByID(ctx, ne.PID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", ne.PID, err)
+		}
+	}
+
+	// If you are not a member of the workspace you can't track expenses in it.
+	if _, err := h.workspaceUser(ctx, projects.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Project.CheckAccess(ctx, projects.ID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projects.ID, err)
+		}
+	}
+
+	exp, err := h.Expense.Create(ctx, projects.WID, claims.Subject, ne, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, expense.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, expense.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("expense[%+v]: %w", &exp, err)
+		}
+	}
+
+	return web.Respond(ctx, w, exp, http.StatusCreated)
+}
+
+// Update updates an expense in the system.
+func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var ue expense.UpdateExpense
+	if err := web.Decode(r, &ue); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	expenseID := web.Param(r, "id")
+
+	if _, err := h.authorizeOwner(ctx, expenseID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Expense.Update(ctx, expenseID, ue, v.Now); err != nil {
+		switch {
+		case errors.Is(err, expense.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, expense.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, expense.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, expense.ErrInvoiced):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("ID[%s] Expense[%+v]: %w", expenseID, &ue, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// UpdateReceipt uploads a receipt for an expense.
+func (h Handlers) UpdateReceipt(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	if err := r.ParseMultipartForm(2 << 20); err != nil {
+		return fmt.Errorf("unable to parse multipart form: %w", err)
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	expenseID := web.Param(r, "id")
+
+	if _, err := h.authorizeOwner(ctx, expenseID, claims.Subject); err != nil {
+		return err
+	}
+
+	file, handler, err := r.FormFile("receipt")
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("unable to get receipt file: %w", err), http.StatusBadRequest)
+	}
+	defer file.Close()
+
+	name, err := upload.Receipt(file, filepath.Ext(handler.Filename))
+	if err != nil {
+		return fmt.Errorf("unable to upload receipt: %w", err)
+	}
+
+	ur := expense.UpdateReceipt{
+		ReceiptName: name,
+	}
+
+	if err := h.Expense.UpdateReceipt(ctx, expenseID, ur, v.Now); err != nil {
+		switch {
+		case errors.Is(err, expense.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, expense.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] Receipt[%+v]: %w", expenseID, &ur, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// BulkInvoice marks billable expenses as added to a client invoice.
+func (h Handlers) BulkInvoice(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	expnsID := web.Param(r, "id")
+	expenseIDs := strings.Split(expnsID, ",")
+
+	for _, expenseID := range expenseIDs {
+		exp, err := h.Expense.QueryByID(ctx, expenseID)
+		if err != nil {
+			switch {
+			case errors.Is(err, expense.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, expense.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return fmt.Errorf("querying expense[%s]: %w", expenseID, err)
+			}
+		}
+
+		workspaceUser, err := h.workspaceUser(ctx, exp.WID, claims.Subject)
+		if err != nil {
+			return err
+		}
+
+		// Only admins may bill expenses to clients.
+		if !workspaceUser.Admin {
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		}
+
+		if err := h.Expense.Invoice(ctx, expenseID, v.Now); err != nil {
+			switch {
+			case errors.Is(err, expense.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, expense.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, expense.ErrInvoiced):
+				return v1Web.NewRequestError(err, http.StatusConflict)
+			default:
+				return fmt.Errorf("ID[%s]: %w", expenseID, err)
+			}
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Delete removes an expense from the system.
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	expenseID := web.Param(r, "id")
+
+	exp, err := h.authorizeOwner(ctx, expenseID, claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	if exp.Invoiced {
+		return v1Web.NewRequestError(expense.ErrInvoiced, http.StatusConflict)
+	}
+
+	if err := h.Expense.Delete(ctx, expenseID); err != nil {
+		switch {
+		case errors.Is(err, expense.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", expenseID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryByID returns an expense by its ID.
+func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	expenseID := web.Param(r, "id")
+
+	exp, err := h.authorizeOwner(ctx, expenseID, claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, exp, http.StatusOK)
+}
+
+// QueryProjectExpenses returns a list of project expenses with paging.
+func (h Handlers) QueryProjectExpenses(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	projectID := web.Param(r, "id")
+	page := web.Param(r, "page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid page format, page[%s]", page), http.StatusBadRequest)
+	}
+	rows := web.Param(r, "rows")
+	rowsPerPage, err := strconv.Atoi(rows)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
+	}
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	workspaceUser, err := h.workspaceUser(ctx, projects.WID, claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	// If you are not an admin you may only list the project expenses of
+	// a project you own.
+	if !workspaceUser.Admin && projects.UID != claims.Subject {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
+	expenses, err := h.Expense.QueryProjectExpenses(ctx, projectID, pageNumber, rowsPerPage)
+	if err != nil {
+		return fmt.Errorf("unable to query for expenses: %w", err)
+	}
+
+	return web.Respond(ctx, w, expenses, http.StatusOK)
+}
+
+// authorizeOwner returns the expense if the user created it or is an admin
+// of its workspace.
+func (h Handlers) authorizeOwner(ctx context.Context, expenseID, userID string) (expense.Expense, error) {
+	exp, err := h.Expense.QueryByID(ctx, expenseID)
+	if err != nil {
+		switch {
+		case errors.Is(err, expense.ErrInvalidID):
+			return expense.Expense{}, v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, expense.ErrNotFound):
+			return expense.Expense{}, v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return expense.Expense{}, fmt.Errorf("querying expense[%s]: %w", expenseID, err)
+		}
+	}
+
+	if exp.UID == userID {
+		if err := h.Project.CheckAccess(ctx, exp.PID, userID); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return expense.Expense{}, v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return expense.Expense{}, v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return expense.Expense{}, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return expense.Expense{}, fmt.Errorf("checking access project[%s]: %w", exp.PID, err)
+			}
+		}
+		return exp, nil
+	}
+
+	workspaceUser, err := h.workspaceUser(ctx, exp.WID, userID)
+	if err != nil {
+		return expense.Expense{}, err
+	}
+
+	if !workspaceUser.Admin {
+		return expense.Expense{}, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return exp, nil
+}
+
+// workspaceUser returns the membership of the user in the workspace. Users
+// that are not members of the workspace are forbidden.
+func (h Handlers) workspaceUser(ctx context.Context, workspaceID, userID string) (workspaceuser.WorkspaceUser, error) {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return workspaceuser.WorkspaceUser{}, v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return workspaceuser.WorkspaceUser{}, v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return workspaceuser.WorkspaceUser{}, fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	return workspaceUser, nil
+}
@@ -0,0 +1,166 @@
+// Package exportgrp maintains the group of handlers for export access.
+package exportgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/export"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of export endpoints.
+type Handlers struct {
+	Export        export.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// SaveFormat configures the columns of an export of a workspace.
+func (h Handlers) SaveFormat(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nf export.NewFormat
+	if err := web.Decode(r, &nf); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	workspaceID := web.Param(r, "id")
+	kind := web.Param(r, "kind")
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	format, err := h.Export.SaveFormat(ctx, workspaceID, kind, nf, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, export.ErrInvalidKind):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, export.ErrInvalidColumn):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, export.ErrInvalidHeaders):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("format[%+v]: %w", &nf, err)
+		}
+	}
+
+	return web.Respond(ctx, w, format, http.StatusOK)
+}
+
+// QueryFormat returns the columns of an export of a workspace.
+func (h Handlers) QueryFormat(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+	kind := web.Param(r, "kind")
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	format, err := h.Export.QueryFormat(ctx, workspaceID, kind)
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, export.ErrInvalidKind):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query export format: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, format, http.StatusOK)
+}
+
+// Ledger returns a ledger CSV of the billable hours of a workspace.
+func (h Handlers) Ledger(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.export(ctx, w, r, export.KindLedger, h.Export.Ledger)
+}
+
+// Payroll returns a payroll CSV of the hours of a workspace per user and pay period.
+func (h Handlers) Payroll(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.export(ctx, w, r, export.KindPayroll, h.Export.Payroll)
+}
+
+// export builds the records of an export and sends them as a CSV file.
+func (h Handlers) export(ctx context.Context, w http.ResponseWriter, r *http.Request, kind string, build func(context.Context, string, time.Time, time.Time) ([][]string, error)) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+
+	start, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid start_date format, start_date[%s]", r.URL.Query().Get("start_date")), http.StatusBadRequest)
+	}
+
+	end, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid end_date format, end_date[%s]", r.URL.Query().Get("end_date")), http.StatusBadRequest)
+	}
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	records, err := build(ctx, workspaceID, start, end)
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, export.ErrInvalidRange):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to build %s export: %w", kind, err)
+		}
+	}
+
+	name := fmt.Sprintf("%s-%s-%s.csv", kind, start.Format("20060102"), end.Format("20060102"))
+
+	return web.RespondCSV(ctx, w, name, records, http.StatusOK)
+}
+
+// authorizeAdmin checks that the user is an admin of the workspace. Exports
+// carry rates and the hours of every member, so only admins may run them.
+func (h Handlers) authorizeAdmin(ctx context.Context, workspaceID, userID string) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
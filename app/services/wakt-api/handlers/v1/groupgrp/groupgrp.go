@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/AhmedShaef/wakt/business/core/group"
 	"github.com/AhmedShaef/wakt/business/core/user"
@@ -159,3 +161,187 @@ func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Req
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
+
+// AddMembers adds users to a group in bulk.
+func (h Handlers) AddMembers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var nm group.NewMembers
+	if err := web.Decode(r, &nm); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	groupID := web.Param(r, "id")
+
+	if err := h.checkOwner(ctx, groupID); err != nil {
+		return err
+	}
+
+	members, err := h.Group.AddMembers(ctx, groupID, nm, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, group.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, group.ErrNotWorkspaceMember):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, group.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] members[%+v]: %w", groupID, &nm, err)
+		}
+	}
+
+	return web.Respond(ctx, w, members, http.StatusCreated)
+}
+
+// RemoveMembers removes users from a group in bulk.
+func (h Handlers) RemoveMembers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	groupID := web.Param(r, "id")
+	userIDs := strings.Split(web.Param(r, "uids"), ",")
+
+	if err := h.checkOwner(ctx, groupID); err != nil {
+		return err
+	}
+
+	if err := h.Group.RemoveMembers(ctx, groupID, userIDs); err != nil {
+		switch {
+		case errors.Is(err, group.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", groupID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryMembers returns the members of a group with paging.
+func (h Handlers) QueryMembers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	groupID := web.Param(r, "id")
+	page := web.Param(r, "page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid page format, page[%s]", page), http.StatusBadRequest)
+	}
+	rows := web.Param(r, "rows")
+	rowsPerPage, err := strconv.Atoi(rows)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
+	}
+
+	if err := h.checkOwner(ctx, groupID); err != nil {
+		return err
+	}
+
+	members, err := h.Group.QueryMembers(ctx, groupID, pageNumber, rowsPerPage)
+	if err != nil {
+		return fmt.Errorf("unable to query for members: %w", err)
+	}
+
+	return web.Respond(ctx, w, members, http.StatusOK)
+}
+
+// AssignProject assigns a group to a project as team members.
+func (h Handlers) AssignProject(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var np group.NewProjectGroup
+	if err := web.Decode(r, &np); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	groupID := web.Param(r, "id")
+
+	if err := h.checkOwner(ctx, groupID); err != nil {
+		return err
+	}
+
+	pg, err := h.Group.AssignProject(ctx, groupID, np, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, group.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, group.ErrOtherWorkspace):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, group.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, group.ErrProjectNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] project[%+v]: %w", groupID, &np, err)
+		}
+	}
+
+	return web.Respond(ctx, w, pg, http.StatusCreated)
+}
+
+// UnassignProject removes a group from the team of a project.
+func (h Handlers) UnassignProject(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	groupID := web.Param(r, "id")
+	projectID := web.Param(r, "pid")
+
+	if err := h.checkOwner(ctx, groupID); err != nil {
+		return err
+	}
+
+	if err := h.Group.UnassignProject(ctx, groupID, projectID); err != nil {
+		switch {
+		case errors.Is(err, group.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s] project[%s]: %w", groupID, projectID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryProjects returns the projects a group is assigned to.
+func (h Handlers) QueryProjects(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	groupID := web.Param(r, "id")
+
+	if err := h.checkOwner(ctx, groupID); err != nil {
+		return err
+	}
+
+	pgs, err := h.Group.QueryProjects(ctx, groupID)
+	if err != nil {
+		return fmt.Errorf("unable to query for projects: %w", err)
+	}
+
+	return web.Respond(ctx, w, pgs, http.StatusOK)
+}
+
+// checkOwner makes sure the group exists and belongs to the user of the
+// request.
+func (h Handlers) checkOwner(ctx context.Context, groupID string) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	groups, err := h.Group.QueryByID(ctx, groupID)
+	if err != nil {
+		switch {
+		case errors.Is(err, group.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, group.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying group[%s]: %w", groupID, err)
+		}
+	}
+
+	// If you are not an admin and looking to change a group you don't own.
+	if claims.Subject != groups.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
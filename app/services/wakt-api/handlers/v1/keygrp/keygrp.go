@@ -0,0 +1,46 @@
+// Package keygrp maintains the group of handlers for publishing signing keys.
+package keygrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of signing key endpoints.
+type Handlers struct {
+	Auth *auth.Auth
+}
+
+// JWKS returns the public keys tokens are signed with, including the keys
+// that were retired but still validate tokens issued before a rotation.
+func (h Handlers) JWKS(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	keys := h.Auth.PublicKeys()
+
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := auth.JWKS{
+		Keys: make([]auth.JWK, 0, len(kids)),
+	}
+	for _, kid := range kids {
+		jwk, err := auth.NewJWK(kid, keys[kid])
+		if err != nil {
+			return fmt.Errorf("kid[%s]: %w", kid, err)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	// Keep caches short so a newly added key is picked up before it is
+	// promoted to sign tokens.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	return web.Respond(ctx, w, jwks, http.StatusOK)
+}
@@ -0,0 +1,308 @@
+// Package milestonegrp maintains the group of handlers for milestone access.
+package milestonegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AhmedShaef/wakt/business/core/milestone"
+	"github.com/AhmedShaef/wakt/business/core/project"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of milestone endpoints.
+type Handlers struct {
+	Milestone     milestone.Core
+	Project       project.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// Create adds a new milestone to a project.
+func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nm milestone.NewMilestone
+	if err := web.Decode(r, &nm); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	prj, err := h.project(ctx, nm.PID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, prj.WID, claims.Subject, true); err != nil {
+		return err
+	}
+
+	mls, err := h.Milestone.Create(ctx, prj.WID, nm, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, milestone.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, milestone.ErrInvalidTask):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("milestone[%+v]: %w", &mls, err)
+		}
+	}
+
+	return web.Respond(ctx, w, mls, http.StatusCreated)
+}
+
+// Update updates a milestone in the system.
+func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var um milestone.UpdateMilestone
+	if err := web.Decode(r, &um); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	milestoneID := web.Param(r, "id")
+
+	mls, err := h.milestone(ctx, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, mls.WID, claims.Subject, true); err != nil {
+		return err
+	}
+
+	if err := h.Milestone.Update(ctx, milestoneID, um, v.Now); err != nil {
+		switch {
+		case errors.Is(err, milestone.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, milestone.ErrInvalidTask):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, milestone.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] Milestone[%+v]: %w", milestoneID, &um, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Delete removes a milestone from the system.
+func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	milestoneID := web.Param(r, "id")
+
+	mls, err := h.milestone(ctx, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, mls.WID, claims.Subject, true); err != nil {
+		return err
+	}
+
+	if err := h.Milestone.Delete(ctx, milestoneID); err != nil {
+		switch {
+		case errors.Is(err, milestone.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", milestoneID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryByID returns a milestone with its ordered tasks by its ID.
+func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	mls, err := h.milestone(ctx, web.Param(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, mls.WID, claims.Subject, false); err != nil {
+		return err
+	}
+
+	if err := h.Project.CheckAccess(ctx, mls.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", mls.PID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, mls, http.StatusOK)
+}
+
+// QueryProgress returns the progress of a milestone.
+func (h Handlers) QueryProgress(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	milestoneID := web.Param(r, "id")
+
+	mls, err := h.milestone(ctx, milestoneID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, mls.WID, claims.Subject, false); err != nil {
+		return err
+	}
+
+	if err := h.Project.CheckAccess(ctx, mls.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", mls.PID, err)
+		}
+	}
+
+	progress, err := h.Milestone.QueryProgress(ctx, milestoneID)
+	if err != nil {
+		return fmt.Errorf("ID[%s]: %w", milestoneID, err)
+	}
+
+	return web.Respond(ctx, w, progress, http.StatusOK)
+}
+
+// QueryTimeline returns the milestones of a project with their progress in
+// the order of their target date.
+func (h Handlers) QueryTimeline(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	prj, err := h.project(ctx, web.Param(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, prj.WID, claims.Subject, false); err != nil {
+		return err
+	}
+
+	if err := h.Project.CheckAccess(ctx, prj.ID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", prj.ID, err)
+		}
+	}
+
+	timeline, err := h.Milestone.QueryTimeline(ctx, prj.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, milestone.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for timeline: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, timeline, http.StatusOK)
+}
+
+// project gets the project a milestone belongs to.
+func (h Handlers) project(ctx context.Context, projectID string) (project.Project, error) {
+	prj, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return project.Project{}, v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return project.Project{}, v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return project.Project{}, fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	return prj, nil
+}
+
+// milestone gets the specified milestone.
+func (h Handlers) milestone(ctx context.Context, milestoneID string) (milestone.Milestone, error) {
+	mls, err := h.Milestone.QueryByID(ctx, milestoneID)
+	if err != nil {
+		switch {
+		case errors.Is(err, milestone.ErrInvalidID):
+			return milestone.Milestone{}, v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, milestone.ErrNotFound):
+			return milestone.Milestone{}, v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return milestone.Milestone{}, fmt.Errorf("querying milestone[%s]: %w", milestoneID, err)
+		}
+	}
+
+	return mls, nil
+}
+
+// authorize makes sure the user is a member of the workspace, and an admin
+// of it when the milestones are changed.
+func (h Handlers) authorize(ctx context.Context, workspaceID, userID string, admin bool) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if admin && !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
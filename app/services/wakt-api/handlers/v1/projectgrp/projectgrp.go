@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AhmedShaef/wakt/business/core/project"
 	"github.com/AhmedShaef/wakt/business/core/task"
@@ -95,8 +96,12 @@ func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Req
 		switch {
 		case errors.Is(err, project.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, project.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrNameTaken):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
 		default:
 			return fmt.Errorf("project[%+v]: %w", &prj, err)
 		}
@@ -168,10 +173,25 @@ func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
 	if err := h.Project.Update(ctx, projectID, up, v.Now); err != nil {
 		switch {
 		case errors.Is(err, project.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, project.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
 		default:
@@ -237,6 +257,19 @@ func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
 	return web.Respond(ctx, w, projects, http.StatusOK)
 }
 
@@ -280,10 +313,109 @@ func (h Handlers) BulkDelete(ctx context.Context, w http.ResponseWriter, r *http
 			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 		}
 
+		if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+			}
+		}
+
 		if err := h.Project.Delete(ctx, projectID); err != nil {
 			switch {
 			case errors.Is(err, project.ErrInvalidID):
 				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrInUse):
+				return v1Web.NewRequestError(err, http.StatusConflict)
+			default:
+				return fmt.Errorf("ID[%s]: %w", projectID, err)
+			}
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// BulkArchive archives a list of projects in the system.
+func (h Handlers) BulkArchive(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.bulkStatus(ctx, w, r, h.Project.Archive)
+}
+
+// BulkRestore restores a list of archived projects in the system.
+func (h Handlers) BulkRestore(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return h.bulkStatus(ctx, w, r, h.Project.Restore)
+}
+
+// bulkStatus applies a lifecycle change to every project in the comma separated
+// id list.
+func (h Handlers) bulkStatus(ctx context.Context, w http.ResponseWriter, r *http.Request, change func(context.Context, string, time.Time) error) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	projctID := web.Param(r, "id")
+	projectIDs := strings.Split(projctID, ",")
+
+	for _, projectID := range projectIDs {
+		projects, err := h.Project.QueryByID(ctx, projectID)
+		if err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return fmt.Errorf("querying workspace[%s]: %w", projectID, err)
+			}
+		}
+
+		workspaces, err := h.Workspace.QueryByID(ctx, projects.WID)
+		if err != nil {
+			switch {
+			case errors.Is(err, workspace.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, workspace.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			default:
+				return fmt.Errorf("querying workspace[%s]: %w", workspaces.ID, err)
+			}
+		}
+
+		// If you are not an admin and looking to retrieve someone other than yourself.
+		if claims.Subject != workspaces.UID {
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		}
+
+		if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+			}
+		}
+
+		if err := change(ctx, projectID, v.Now); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
 			default:
 				return fmt.Errorf("ID[%s]: %w", projectID, err)
 			}
@@ -329,6 +461,19 @@ func (h Handlers) QueryProjectTasks(ctx context.Context, w http.ResponseWriter,
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
 	tsk, err := h.Task.QueryProjectTasks(ctx, projectID, pageNumber, rowsPerPage)
 	if err != nil {
 		return fmt.Errorf("unable to query for task: %w", err)
@@ -336,3 +481,353 @@ func (h Handlers) QueryProjectTasks(ctx context.Context, w http.ResponseWriter,
 
 	return web.Respond(ctx, w, tsk, http.StatusOK)
 }
+
+// SaveTemplate saves a project as a template.
+func (h Handlers) SaveTemplate(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nt project.NewTemplate
+	if err := web.Decode(r, &nt); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	projectID := web.Param(r, "id")
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, projects.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	tpl, err := h.Project.SaveTemplate(ctx, projectID, claims.Subject, nt, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("template[%+v]: %w", &nt, err)
+		}
+	}
+
+	return web.Respond(ctx, w, tpl, http.StatusCreated)
+}
+
+// CreateFromTemplate adds a new project to the system from a template.
+func (h Handlers) CreateFromTemplate(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nf project.NewFromTemplate
+	if err := web.Decode(r, &nf); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	templateID := web.Param(r, "id")
+
+	tpl, err := h.Project.QueryTemplateByID(ctx, templateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrTemplateNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying template[%s]: %w", templateID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, tpl.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	prj, err := h.Project.CreateFromTemplate(ctx, templateID, claims.Subject, nf, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrTemplateNotFound), errors.Is(err, project.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrOtherWorkspace), errors.Is(err, project.ErrNameTaken):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("project[%+v]: %w", &nf, err)
+		}
+	}
+
+	return web.Respond(ctx, w, prj, http.StatusCreated)
+}
+
+// Clone deep copies a project in the system.
+func (h Handlers) Clone(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var cp project.CloneProject
+	if err := web.Decode(r, &cp); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	projectID := web.Param(r, "id")
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, projects.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	// Copying into another workspace needs admin rights there as well.
+	if cp.WID != "" && cp.WID != projects.WID {
+		if err := h.authorizeAdmin(ctx, cp.WID, claims.Subject); err != nil {
+			return err
+		}
+	}
+
+	prj, err := h.Project.Clone(ctx, projectID, claims.Subject, cp, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound), errors.Is(err, project.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrOtherWorkspace), errors.Is(err, project.ErrNameTaken):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("project[%+v]: %w", &cp, err)
+		}
+	}
+
+	return web.Respond(ctx, w, prj, http.StatusCreated)
+}
+
+// Move reassigns a project to another client or workspace. A move that
+// changes rates or currency is answered with a conflict listing the changes
+// until it is confirmed.
+func (h Handlers) Move(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var mp project.MoveProject
+	if err := web.Decode(r, &mp); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	projectID := web.Param(r, "id")
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, projects.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	// Moving into another workspace needs admin rights there as well.
+	if mp.WID != "" && mp.WID != projects.WID {
+		if err := h.authorizeAdmin(ctx, mp.WID, claims.Subject); err != nil {
+			return err
+		}
+	}
+
+	report, err := h.Project.Move(ctx, projectID, mp, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound), errors.Is(err, project.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrOtherOwner), errors.Is(err, project.ErrOtherWorkspace):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNameTaken), errors.Is(err, project.ErrNotMember):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s] move[%+v]: %w", projectID, &mp, err)
+		}
+	}
+
+	if !report.Moved {
+		return web.Respond(ctx, w, report, http.StatusConflict)
+	}
+
+	return web.Respond(ctx, w, report, http.StatusOK)
+}
+
+// DeleteTemplate removes a project template from the system.
+func (h Handlers) DeleteTemplate(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	templateID := web.Param(r, "id")
+
+	tpl, err := h.Project.QueryTemplateByID(ctx, templateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrTemplateNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying template[%s]: %w", templateID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, tpl.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Project.DeleteTemplate(ctx, templateID); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s]: %w", templateID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryTemplateByID returns a project template by its ID.
+func (h Handlers) QueryTemplateByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	templateID := web.Param(r, "id")
+
+	tpl, err := h.Project.QueryTemplateByID(ctx, templateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrTemplateNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying template[%s]: %w", templateID, err)
+		}
+	}
+
+	if err := h.authorizeAdmin(ctx, tpl.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tpl, http.StatusOK)
+}
+
+// QueryWorkspaceTemplates returns a list of project templates with paging.
+func (h Handlers) QueryWorkspaceTemplates(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+	page := web.Param(r, "page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid page format, page[%s]", page), http.StatusBadRequest)
+	}
+	rows := web.Param(r, "rows")
+	rowsPerPage, err := strconv.Atoi(rows)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
+	}
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	tpls, err := h.Project.QueryWorkspaceTemplates(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for templates: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, tpls, http.StatusOK)
+}
+
+// authorizeAdmin makes sure the user is an admin of the workspace.
+func (h Handlers) authorizeAdmin(ctx context.Context, workspaceID, userID string) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+// Package reportgrp maintains the group of handlers for report access.
+package reportgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/client"
+	"github.com/AhmedShaef/wakt/business/core/project"
+	"github.com/AhmedShaef/wakt/business/core/report"
+	"github.com/AhmedShaef/wakt/business/core/workspace"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of report endpoints.
+type Handlers struct {
+	Report        report.Core
+	Project       project.Core
+	Client        client.Core
+	Workspace     workspace.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// QueryProjectProfitability returns the profitability of the workspace projects.
+func (h Handlers) QueryProjectProfitability(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+
+	start, end, err := dateRange(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	profits, err := h.Report.QueryProjectProfitability(ctx, workspaceID, start, end, fieldFilter(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, report.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, report.ErrInvalidRange):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query project profitability: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, profits, http.StatusOK)
+}
+
+// QueryClientProfitability returns the profitability of the workspace clients.
+func (h Handlers) QueryClientProfitability(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+
+	start, end, err := dateRange(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authorize(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	profits, err := h.Report.QueryClientProfitability(ctx, workspaceID, start, end, fieldFilter(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, report.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, report.ErrInvalidRange):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query client profitability: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, profits, http.StatusOK)
+}
+
+// QueryProjectBudget returns how much of a project budget is spent.
+func (h Handlers) QueryProjectBudget(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	projectID := web.Param(r, "id")
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	if err := h.authorize(ctx, projects.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
+	budget, err := h.Report.QueryProjectBudget(ctx, projects.WID, projectID, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, report.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, report.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("unable to query project budget: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, budget, http.StatusOK)
+}
+
+// QueryClientInvoice returns a draft invoice of a client with the billable
+// time and the billable expenses that were not invoiced yet.
+func (h Handlers) QueryClientInvoice(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	clientID := web.Param(r, "id")
+
+	start, end, err := dateRange(r)
+	if err != nil {
+		return err
+	}
+
+	clients, err := h.Client.QueryByID(ctx, clientID)
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, client.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying client[%s]: %w", clientID, err)
+		}
+	}
+
+	if err := h.authorize(ctx, clients.WID, claims.Subject); err != nil {
+		return err
+	}
+
+	invoice, err := h.Report.QueryClientInvoice(ctx, clients.WID, clientID, start, end)
+	if err != nil {
+		switch {
+		case errors.Is(err, report.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, report.ErrInvalidRange):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query client invoice: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, invoice, http.StatusOK)
+}
+
+// authorize checks that the user may see the profitability of the workspace.
+// Labor cost is internal data, so only admins may see the report.
+func (h Handlers) authorize(ctx context.Context, workspaceID, userID string) error {
+	if _, err := h.Workspace.QueryByID(ctx, workspaceID); err != nil {
+		switch {
+		case errors.Is(err, workspace.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspace.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying workspace[%s]: %w", workspaceID, err)
+		}
+	}
+
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// dateRange parses the start_date and end_date query parameters.
+func dateRange(r *http.Request) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
+	if err != nil {
+		return time.Time{}, time.Time{}, v1Web.NewRequestError(fmt.Errorf("invalid start_date format, start_date[%s]", r.URL.Query().Get("start_date")), http.StatusBadRequest)
+	}
+
+	end, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
+	if err != nil {
+		return time.Time{}, time.Time{}, v1Web.NewRequestError(fmt.Errorf("invalid end_date format, end_date[%s]", r.URL.Query().Get("end_date")), http.StatusBadRequest)
+	}
+
+	return start, end, nil
+}
+
+// fieldFilter reads the custom field filter of a report from the field and
+// value query parameters.
+func fieldFilter(r *http.Request) report.FieldFilter {
+	return report.FieldFilter{
+		FieldID: r.URL.Query().Get("field"),
+		Value:   r.URL.Query().Get("value"),
+	}
+}
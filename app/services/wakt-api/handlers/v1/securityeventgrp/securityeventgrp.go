@@ -0,0 +1,82 @@
+// Package securityeventgrp maintains the group of handlers for security event access.
+package securityeventgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AhmedShaef/wakt/business/core/securityevent"
+	"github.com/AhmedShaef/wakt/business/core/workspaceuser"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of security event endpoints.
+type Handlers struct {
+	SecurityEvent securityevent.Core
+	WorkspaceUser workspaceuser.Core
+}
+
+// QueryWorkspaceEvents returns a list of the security events of the members
+// of a workspace with paging.
+func (h Handlers) QueryWorkspaceEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	workspaceID := web.Param(r, "id")
+	page := web.Param(r, "page")
+	pageNumber, err := strconv.Atoi(page)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid page format, page[%s]", page), http.StatusBadRequest)
+	}
+	rows := web.Param(r, "rows")
+	rowsPerPage, err := strconv.Atoi(rows)
+	if err != nil {
+		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
+	}
+
+	if err := h.authorizeAdmin(ctx, workspaceID, claims.Subject); err != nil {
+		return err
+	}
+
+	events, err := h.SecurityEvent.QueryWorkspaceEvents(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, securityevent.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for security events: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, events, http.StatusOK)
+}
+
+// authorizeAdmin checks that the user is an admin of the workspace. The
+// security events show the emails and IP addresses of members, so only
+// admins get to see them.
+func (h Handlers) authorizeAdmin(ctx context.Context, workspaceID, userID string) error {
+	workspaceUser, err := h.WorkspaceUser.QueryByuIDwID(ctx, workspaceID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspaceuser.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspaceuser.ErrNotFound):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("querying workspace user[%s]: %w", userID, err)
+		}
+	}
+
+	if !workspaceUser.Admin {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return nil
+}
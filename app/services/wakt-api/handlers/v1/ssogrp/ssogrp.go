@@ -0,0 +1,99 @@
+// Package ssogrp maintains the group of handlers for single sign-on access.
+package ssogrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AhmedShaef/wakt/business/core/sso"
+	"github.com/AhmedShaef/wakt/business/core/user"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
+	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
+	"github.com/AhmedShaef/wakt/foundation/web"
+)
+
+// Handlers manages the set of single sign-on endpoints.
+type Handlers struct {
+	SSO  sso.Core
+	User user.Core
+	Auth *auth.Auth
+}
+
+// Login starts signing in with the OpenID Connect provider and returns the
+// URL to send the user to.
+func (h Handlers) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	authURL, err := h.SSO.Login(ctx, v.Now)
+	if err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	login := struct {
+		AuthURL string `json:"auth_url"`
+	}{
+		AuthURL: authURL,
+	}
+
+	return web.Respond(ctx, w, login, http.StatusOK)
+}
+
+// Callback provides an API token for the user the provider redirected back
+// with. Users with two-factor authentication get a challenge instead.
+func (h Handlers) Callback(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	cb := sso.Callback{
+		State: r.URL.Query().Get("state"),
+		Code:  r.URL.Query().Get("code"),
+	}
+
+	userID, err := h.SSO.Callback(ctx, cb, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, sso.ErrInvalidState), errors.Is(err, oidc.ErrInvalidIDToken):
+			return v1Web.NewRequestError(err, http.StatusUnauthorized)
+		case errors.Is(err, sso.ErrUnverifiedEmail), errors.Is(err, sso.ErrUnverifiedUser), errors.Is(err, sso.ErrDomainNotAllowed):
+			return v1Web.NewRequestError(err, http.StatusForbidden)
+		case errors.Is(err, sso.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("callback: %w", err)
+		}
+	}
+
+	claims := user.NewClaims(userID, v.Now)
+
+	challenge, err := h.User.Challenge(ctx, claims.Subject, v.Now)
+	if err != nil {
+		return fmt.Errorf("challenge: %w", err)
+	}
+	if challenge.Token != "" {
+		return web.Respond(ctx, w, challenge, http.StatusOK)
+	}
+
+	var tkn struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	tkn.Token, err = h.Auth.GenerateToken(claims)
+	if err != nil {
+		return fmt.Errorf("generating token: %w", err)
+	}
+
+	tkn.RefreshToken, err = h.User.NewRefreshToken(ctx, claims, v.Now)
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+
+	return web.Respond(ctx, w, tkn, http.StatusOK)
+}
@@ -71,6 +71,8 @@ func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Req
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
 		case errors.Is(err, workspace.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, tag.ErrUniqueName):
+			return v1Web.NewRequestError(err, http.StatusConflict)
 		default:
 			return fmt.Errorf("tag[%+v]: %w", &tags, err)
 		}
@@ -131,6 +133,8 @@ func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Req
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
 		case errors.Is(err, tag.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, tag.ErrUniqueName):
+			return v1Web.NewRequestError(err, http.StatusConflict)
 		default:
 			return fmt.Errorf("ID[%s] tag[%+v]: %w", tagID, &ug, err)
 		}
@@ -187,3 +191,69 @@ func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Req
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
+
+// Merge folds a set of tags into a tag of the same workspace.
+func (h Handlers) Merge(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var mt tag.MergeTags
+	if err := web.Decode(r, &mt); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	tagID := web.Param(r, "id")
+
+	tags, err := h.Tag.QueryByID(ctx, tagID)
+	if err != nil {
+		switch {
+		case errors.Is(err, tag.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, tag.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying tag[%s]: %w", tagID, err)
+		}
+	}
+	workspaces, err := h.Workspace.QueryByID(ctx, tags.WID)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspace.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, workspace.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying workspace[%s]: %w", workspaces.ID, err)
+		}
+	}
+
+	// If you are not an admin and looking to retrieve someone other than yourself.
+	if claims.Subject != workspaces.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	merged, err := h.Tag.Merge(ctx, tagID, mt, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, tag.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, tag.ErrMergeIntoItself):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, tag.ErrOtherWorkspace):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, tag.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("ID[%s] tag[%+v]: %w", tagID, &mt, err)
+		}
+	}
+
+	return web.Respond(ctx, w, merged, http.StatusOK)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/AhmedShaef/wakt/business/core/project"
 	"github.com/AhmedShaef/wakt/business/core/task"
 	"github.com/AhmedShaef/wakt/business/core/user"
 	"github.com/AhmedShaef/wakt/business/core/workspace"
@@ -19,6 +20,7 @@ import (
 // Handlers manages the set of task endpoints.
 type Handlers struct {
 	Task      task.Core
+	Project   project.Core
 	Workspace workspace.Core
 	User      user.Core
 }
@@ -48,13 +50,30 @@ func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Req
 		nt.WID = users.DefaultWid
 	}
 
+	if err := h.Project.CheckAccess(ctx, nt.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", nt.PID, err)
+		}
+	}
+
 	tsk, err := h.Task.Create(ctx, claims.Subject, nt, v.Now)
 	if err != nil {
 		switch {
 		case errors.Is(err, task.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, task.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, task.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, task.ErrInvalidStatus), errors.Is(err, task.ErrOtherProject):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
 		default:
 			return fmt.Errorf("task[%+v]: %w", &tsk, err)
 		}
@@ -89,6 +108,19 @@ func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckAccess(ctx, tasks.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", tasks.PID, err)
+		}
+	}
+
 	return web.Respond(ctx, w, tasks, http.StatusOK)
 }
 
@@ -130,12 +162,31 @@ func (h Handlers) BulkUpdate(ctx context.Context, w http.ResponseWriter, r *http
 			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 		}
 
+		if err := h.Project.CheckAccess(ctx, tasks.PID, claims.Subject); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return fmt.Errorf("checking access project[%s]: %w", tasks.PID, err)
+			}
+		}
+
 		if err := h.Task.Update(ctx, taskID, ut, v.Now); err != nil {
 			switch {
 			case errors.Is(err, task.ErrInvalidID):
 				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, task.ErrReferenceNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
 			case errors.Is(err, task.ErrNotFound):
 				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, task.ErrInvalidStatus), errors.Is(err, task.ErrOtherProject):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, task.ErrCycle), errors.Is(err, task.ErrBlocked):
+				return v1Web.NewRequestError(err, http.StatusConflict)
 			default:
 				return fmt.Errorf("ID[%s] task[%+v]: %w", taskID, &ut, err)
 			}
@@ -147,6 +198,11 @@ func (h Handlers) BulkUpdate(ctx context.Context, w http.ResponseWriter, r *http
 
 // BulkDelete removes a task from the system.
 func (h Handlers) BulkDelete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
 	claims, err := auth.GetClaims(ctx)
 	if err != nil {
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
@@ -173,10 +229,25 @@ func (h Handlers) BulkDelete(ctx context.Context, w http.ResponseWriter, r *http
 			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 		}
 
-		if err := h.Task.Delete(ctx, taskID); err != nil {
+		if err := h.Project.CheckAccess(ctx, tasks.PID, claims.Subject); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return fmt.Errorf("checking access project[%s]: %w", tasks.PID, err)
+			}
+		}
+
+		if err := h.Task.Delete(ctx, taskID, v.Now); err != nil {
 			switch {
 			case errors.Is(err, task.ErrInvalidID):
 				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, task.ErrInUse):
+				return v1Web.NewRequestError(err, http.StatusConflict)
 			case errors.Is(err, task.ErrNotFound):
 				return v1Web.NewRequestError(err, http.StatusNotFound)
 			default:
@@ -187,3 +258,250 @@ func (h Handlers) BulkDelete(ctx context.Context, w http.ResponseWriter, r *http
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
+
+// QueryBoard returns the tasks of a project grouped by status.
+func (h Handlers) QueryBoard(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	projectID := web.Param(r, "id")
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	// If you are not an admin and looking to retrieve someone other than yourself.
+	if claims.Subject != projects.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
+	board, err := h.Task.QueryBoard(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for board: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, board, http.StatusOK)
+}
+
+// QueryUserTasks returns the open tasks assigned to the user.
+func (h Handlers) QueryUserTasks(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	assigned, err := h.Task.QueryUserTasks(ctx, claims.Subject, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for user tasks: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, assigned, http.StatusOK)
+}
+
+// AddDependency blocks a task by another task.
+func (h Handlers) AddDependency(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var nd task.NewDependency
+	if err := web.Decode(r, &nd); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	taskID := web.Param(r, "id")
+
+	tasks, err := h.Task.QueryByID(ctx, taskID)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, task.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying task[%s]: %w", taskID, err)
+		}
+	}
+
+	// If you are not an admin and looking to retrieve someone other than yourself.
+	if claims.Subject != tasks.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.Project.CheckAccess(ctx, tasks.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", tasks.PID, err)
+		}
+	}
+
+	dep, err := h.Task.AddDependency(ctx, taskID, nd, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID), errors.Is(err, task.ErrOtherProject):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, task.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, task.ErrCycle), errors.Is(err, task.ErrDependency):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("dependency[%+v]: %w", &nd, err)
+		}
+	}
+
+	return web.Respond(ctx, w, dep, http.StatusCreated)
+}
+
+// RemoveDependency stops a task from being blocked by another task.
+func (h Handlers) RemoveDependency(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	taskID := web.Param(r, "id")
+	blockedBy := web.Param(r, "blocked_by")
+
+	tasks, err := h.Task.QueryByID(ctx, taskID)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, task.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying task[%s]: %w", taskID, err)
+		}
+	}
+
+	// If you are not an admin and looking to retrieve someone other than yourself.
+	if claims.Subject != tasks.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.Project.CheckAccess(ctx, tasks.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", tasks.PID, err)
+		}
+	}
+
+	if err := h.Task.RemoveDependency(ctx, taskID, blockedBy); err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("ID[%s] blocked by[%s]: %w", taskID, blockedBy, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// QueryGraph returns the tasks of a project with their dependencies.
+func (h Handlers) QueryGraph(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	projectID := web.Param(r, "id")
+
+	projects, err := h.Project.QueryByID(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querying project[%s]: %w", projectID, err)
+		}
+	}
+
+	// If you are not an admin and looking to retrieve someone other than yourself.
+	if claims.Subject != projects.UID {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.Project.CheckAccess(ctx, projectID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", projectID, err)
+		}
+	}
+
+	graph, err := h.Task.QueryGraph(ctx, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for graph: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, graph, http.StatusOK)
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/AhmedShaef/wakt/business/core/customfield"
+	"github.com/AhmedShaef/wakt/business/core/project"
 	"github.com/AhmedShaef/wakt/business/core/timeentry"
 	"github.com/AhmedShaef/wakt/business/core/user"
 	"github.com/AhmedShaef/wakt/business/core/workspace"
@@ -20,9 +22,11 @@ import (
 
 // Handlers manages the set of timeEntry endpoints.
 type Handlers struct {
-	TimeEntry timeentry.Core
-	Workspace workspace.Core
-	User      user.Core
+	TimeEntry   timeentry.Core
+	Project     project.Core
+	Workspace   workspace.Core
+	User        user.Core
+	CustomField customfield.Core
 }
 
 // Create adds a new timeEntry to the system.
@@ -50,18 +54,37 @@ func (h Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Req
 		nte.WID = users.DefaultWid
 	}
 
+	if err := h.CustomField.Check(ctx, nte.WID, customfield.EntityTimeEntry, nte.CustomFields, nte.Billable); err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("checking custom fields[%+v]: %w", nte.CustomFields, err)
+		}
+	}
+
 	usr, err := h.TimeEntry.Create(ctx, nte, claims.Subject, v.Now)
 	if err != nil {
 		switch {
 		case errors.Is(err, timeentry.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, timeentry.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, timeentry.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, timeentry.ErrArchived):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		case errors.Is(err, timeentry.ErrPrivate):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 		default:
 			return fmt.Errorf("timeEntry[%+v]: %w", &usr, err)
 		}
 	}
 
+	if err := h.setCustomFields(ctx, usr, nte.CustomFields, v.Now); err != nil {
+		return err
+	}
+
 	return web.Respond(ctx, w, usr, http.StatusCreated)
 }
 
@@ -90,18 +113,37 @@ func (h Handlers) Start(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		ste.WID = users.DefaultWid
 	}
 
+	if err := h.CustomField.Check(ctx, ste.WID, customfield.EntityTimeEntry, ste.CustomFields, ste.Billable); err != nil {
+		switch {
+		case errors.Is(err, customfield.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("checking custom fields[%+v]: %w", ste.CustomFields, err)
+		}
+	}
+
 	usr, err := h.TimeEntry.Start(ctx, ste, claims.Subject, v.Now)
 	if err != nil {
 		switch {
 		case errors.Is(err, timeentry.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, timeentry.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, timeentry.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, timeentry.ErrArchived):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		case errors.Is(err, timeentry.ErrPrivate):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 		default:
 			return fmt.Errorf("timeEntry[%+v]: %w", &usr, err)
 		}
 	}
 
+	if err := h.setCustomFields(ctx, usr, ste.CustomFields, v.Now); err != nil {
+		return err
+	}
+
 	return web.Respond(ctx, w, usr, http.StatusCreated)
 }
 
@@ -136,6 +178,19 @@ func (h Handlers) Stop(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckOptionalAccess(ctx, timeEntrys.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", timeEntrys.PID, err)
+		}
+	}
+
 	timeEntry, err := h.TimeEntry.Stop(ctx, timeEntryID, v.Now)
 	if err != nil {
 		switch {
@@ -187,10 +242,25 @@ func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckOptionalAccess(ctx, timeEntrys.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", timeEntrys.PID, err)
+		}
+	}
+
 	if err := h.TimeEntry.Update(ctx, timeEntryID, ute, v.Now); err != nil {
 		switch {
 		case errors.Is(err, timeentry.ErrInvalidID):
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, timeentry.ErrReferenceNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, timeentry.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
 		default:
@@ -227,6 +297,19 @@ func (h Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckOptionalAccess(ctx, timeEntrys.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", timeEntrys.PID, err)
+		}
+	}
+
 	if err := h.TimeEntry.Delete(ctx, timeEntryID); err != nil {
 		switch {
 		case errors.Is(err, timeentry.ErrInvalidID):
@@ -265,6 +348,19 @@ func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
+	if err := h.Project.CheckOptionalAccess(ctx, timeEntry.PID, claims.Subject); err != nil {
+		switch {
+		case errors.Is(err, project.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, project.ErrNotFound):
+			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, project.ErrForbidden):
+			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+		default:
+			return fmt.Errorf("checking access project[%s]: %w", timeEntry.PID, err)
+		}
+	}
+
 	users, err := h.User.QueryByID(ctx, timeEntry.UID)
 	if err != nil {
 		switch {
@@ -355,9 +451,19 @@ func (h Handlers) QueryRange(ctx context.Context, w http.ResponseWriter, r *http
 		return v1Web.NewRequestError(fmt.Errorf("invalid end_date format, end_date[%s]", end), http.StatusBadRequest)
 	}
 
-	timentry, err := h.TimeEntry.QueryRange(ctx, claims.Subject, pageNumber, rowsPerPage, start, end)
+	ff := timeentry.FieldFilter{
+		FieldID: r.URL.Query().Get("field"),
+		Value:   r.URL.Query().Get("value"),
+	}
+
+	timentry, err := h.TimeEntry.QueryRange(ctx, claims.Subject, pageNumber, rowsPerPage, start, end, ff)
 	if err != nil {
-		return fmt.Errorf("unable to query for time entries: %w", err)
+		switch {
+		case errors.Is(err, timeentry.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unable to query for time entries: %w", err)
+		}
 	}
 
 	for _, v := range timentry {
@@ -419,6 +525,19 @@ func (h Handlers) UpdateTags(ctx context.Context, w http.ResponseWriter, r *http
 			return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 		}
 
+		if err := h.Project.CheckOptionalAccess(ctx, timeEntries.PID, claims.Subject); err != nil {
+			switch {
+			case errors.Is(err, project.ErrInvalidID):
+				return v1Web.NewRequestError(err, http.StatusBadRequest)
+			case errors.Is(err, project.ErrNotFound):
+				return v1Web.NewRequestError(err, http.StatusNotFound)
+			case errors.Is(err, project.ErrForbidden):
+				return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+			default:
+				return fmt.Errorf("checking access project[%s]: %w", timeEntries.PID, err)
+			}
+		}
+
 		if err := h.TimeEntry.UpdateTags(ctx, timeEntryID, ut, v.Now); err != nil {
 			switch {
 			case errors.Is(err, timeentry.ErrInvalidID):
@@ -466,3 +585,18 @@ func (h Handlers) QueryDash(ctx context.Context, w http.ResponseWriter, r *http.
 
 	return web.Respond(ctx, w, timentry, http.StatusOK)
 }
+
+// setCustomFields stores the custom field values of a new time entry, which
+// were checked before the time entry was created.
+func (h Handlers) setCustomFields(ctx context.Context, te timeentry.TimeEntry, values map[string][]string, now time.Time) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sv := customfield.SetValues{Values: values}
+	if _, err := h.CustomField.SetValues(ctx, customfield.EntityTimeEntry, te.ID, te.WID, sv, te.Billable, now); err != nil {
+		return fmt.Errorf("setting custom fields time entry[%s]: %w", te.ID, err)
+	}
+
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
 	"github.com/AhmedShaef/wakt/foundation/upload"
 	"github.com/AhmedShaef/wakt/foundation/web"
+	"net"
 	"net/http"
 	"strconv"
 )
@@ -55,6 +56,11 @@ func (h Handlers) SignUp(ctx context.Context, w http.ResponseWriter, r *http.Req
 		}
 		return fmt.Errorf("user[%+v]: %w", &usr, err)
 	}
+
+	if err := h.User.SendVerification(ctx, usr.ID, v.Now); err != nil {
+		return fmt.Errorf("send verification: %w", err)
+	}
+
 	nw := workspace.NewWorkspace{
 		Name: usr.FullName,
 		UID:  usr.ID,
@@ -105,7 +111,12 @@ func (h Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Req
 	}
 
 	if err := h.User.Update(ctx, claims.Subject, upd, v.Now); err != nil {
-		return fmt.Errorf("ID[%s] User[%+v]: %w", claims.Subject, &upd, err)
+		switch {
+		case errors.Is(err, user.ErrUniqueEmail):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("ID[%s] User[%+v]: %w", claims.Subject, &upd, err)
+		}
 	}
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
@@ -172,6 +183,126 @@ func (h Handlers) ChangePassword(ctx context.Context, w http.ResponseWriter, r *
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
 
+// ForgotPassword emails a password reset token to the user with the email.
+// The response is the same whether the email belongs to a user or not.
+func (h Handlers) ForgotPassword(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var fp user.ForgotPassword
+	if err := web.Decode(r, &fp); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.User.ForgotPassword(ctx, fp, v.Now); err != nil {
+		return fmt.Errorf("forgot password: %w", err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// ResetPassword sets a new password with an emailed reset token.
+func (h Handlers) ResetPassword(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var rp user.ResetPassword
+	if err := web.Decode(r, &rp); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.User.ResetPassword(ctx, rp, v.Now); err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidToken):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("reset password: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// VerifyEmail verifies an email with the token that was emailed to it.
+func (h Handlers) VerifyEmail(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var ve user.VerifyEmail
+	if err := web.Decode(r, &ve); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.User.VerifyEmail(ctx, ve, v.Now); err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidToken):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, user.ErrUniqueEmail):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("verify email: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// UnlockAccount unlocks the account an unlock token was emailed for after too
+// many failed sign in attempts.
+func (h Handlers) UnlockAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var ua user.UnlockAccount
+	if err := web.Decode(r, &ua); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.User.UnlockAccount(ctx, ua, v.Now); err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidToken):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("unlock account: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// ResendVerification emails a new verification token to the authenticated
+// user.
+func (h Handlers) ResendVerification(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.User.SendVerification(ctx, claims.Subject, v.Now); err != nil {
+		switch {
+		case errors.Is(err, user.ErrVerified):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("ID[%s]: %w", claims.Subject, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
 // QueryByID returns a user by its ID.
 func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	claims, err := auth.GetClaims(ctx)
@@ -189,6 +320,10 @@ func (h Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.
 
 // NewToken provides an API token for the authenticated user.
 func (h Handlers) NewToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
 
 	email, pass, ok := r.BasicAuth()
 	if !ok {
@@ -196,21 +331,237 @@ func (h Handlers) NewToken(ctx context.Context, w http.ResponseWriter, r *http.R
 		return v1Web.NewRequestError(err, http.StatusUnauthorized)
 	}
 
-	claims, err := h.User.Authenticate(ctx, email, pass)
+	claims, err := h.User.Authenticate(ctx, email, pass, clientIP(r), v.Now)
 	if err != nil {
 		switch {
 		case errors.Is(err, user.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
 		case errors.Is(err, user.ErrAuthenticationFailure):
 			return v1Web.NewRequestError(err, http.StatusUnauthorized)
+		case errors.Is(err, user.ErrTooManyAttempts):
+			return v1Web.NewRequestError(err, http.StatusTooManyRequests)
+		case errors.Is(err, user.ErrAccountLocked):
+			return v1Web.NewRequestError(err, http.StatusLocked)
 		default:
 			return fmt.Errorf("authenticating: %w", err)
 		}
 	}
 
-	var tkn struct {
-		Token string `json:"token"`
+	// Users with two-factor authentication get a challenge to sign in with
+	// their code first.
+	challenge, err := h.User.Challenge(ctx, claims.Subject, v.Now)
+	if err != nil {
+		return fmt.Errorf("challenge: %w", err)
+	}
+	if challenge.Token != "" {
+		return web.Respond(ctx, w, challenge, http.StatusOK)
+	}
+
+	var tkn token
+	tkn.Token, err = h.Auth.GenerateToken(claims)
+	if err != nil {
+		return fmt.Errorf("generating token: %w", err)
+	}
+
+	tkn.RefreshToken, err = h.User.NewRefreshToken(ctx, claims, v.Now)
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+
+	return web.Respond(ctx, w, tkn, http.StatusOK)
+}
+
+// TwoFactorToken provides an API token for a user that completes a two-factor
+// challenge.
+func (h Handlers) TwoFactorToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var vc user.VerifyChallenge
+	if err := web.Decode(r, &vc); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	claims, codes, err := h.User.VerifyChallenge(ctx, vc, clientIP(r), v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidToken), errors.Is(err, user.ErrInvalidCode):
+			return v1Web.NewRequestError(err, http.StatusUnauthorized)
+		case errors.Is(err, user.ErrTooManyAttempts):
+			return v1Web.NewRequestError(err, http.StatusTooManyRequests)
+		case errors.Is(err, user.ErrAccountLocked):
+			return v1Web.NewRequestError(err, http.StatusLocked)
+		case errors.Is(err, user.ErrTwoFactorNotEnabled):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("verifying challenge: %w", err)
+		}
+	}
+
+	tkn := token{RecoveryCodes: codes}
+	tkn.Token, err = h.Auth.GenerateToken(claims)
+	if err != nil {
+		return fmt.Errorf("generating token: %w", err)
+	}
+
+	tkn.RefreshToken, err = h.User.NewRefreshToken(ctx, claims, v.Now)
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+
+	return web.Respond(ctx, w, tkn, http.StatusOK)
+}
+
+// EnrollChallenge enrolls a user that has to use two-factor authentication
+// to complete a challenge.
+func (h Handlers) EnrollChallenge(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var ec user.EnrollChallenge
+	if err := web.Decode(r, &ec); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	enrollment, err := h.User.EnrollChallenge(ctx, ec, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidToken):
+			return v1Web.NewRequestError(err, http.StatusUnauthorized)
+		case errors.Is(err, user.ErrTwoFactorEnabled):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("enrolling: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, enrollment, http.StatusOK)
+}
+
+// EnrollTwoFactor generates a two-factor secret for the authenticated user.
+func (h Handlers) EnrollTwoFactor(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	enrollment, err := h.User.EnrollTwoFactor(ctx, claims.Subject, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrTwoFactorEnabled):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("ID[%s]: %w", claims.Subject, err)
+		}
+	}
+
+	return web.Respond(ctx, w, enrollment, http.StatusOK)
+}
+
+// ConfirmTwoFactor enables two-factor authentication for the authenticated
+// user and returns their recovery codes.
+func (h Handlers) ConfirmTwoFactor(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
 	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var tc user.TwoFactorCode
+	if err := web.Decode(r, &tc); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	codes, err := h.User.ConfirmTwoFactor(ctx, claims.Subject, tc, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidCode), errors.Is(err, user.ErrTwoFactorNotEnabled):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, user.ErrTwoFactorEnabled):
+			return v1Web.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("ID[%s]: %w", claims.Subject, err)
+		}
+	}
+
+	recovery := struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}{
+		RecoveryCodes: codes,
+	}
+
+	return web.Respond(ctx, w, recovery, http.StatusOK)
+}
+
+// DisableTwoFactor turns two-factor authentication off for the authenticated
+// user.
+func (h Handlers) DisableTwoFactor(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	var tc user.TwoFactorCode
+	if err := web.Decode(r, &tc); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if err := h.User.DisableTwoFactor(ctx, claims.Subject, tc, v.Now); err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidCode), errors.Is(err, user.ErrTwoFactorNotEnabled):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, user.ErrTwoFactorRequired):
+			return v1Web.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("ID[%s]: %w", claims.Subject, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// RefreshToken exchanges a refresh token for a new API token and refresh
+// token.
+func (h Handlers) RefreshToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	var rt user.RefreshToken
+	if err := web.Decode(r, &rt); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	claims, refresh, err := h.User.Refresh(ctx, rt, v.Now)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidToken), errors.Is(err, user.ErrTokenReused):
+			return v1Web.NewRequestError(err, http.StatusUnauthorized)
+		default:
+			return fmt.Errorf("refreshing: %w", err)
+		}
+	}
+
+	tkn := token{RefreshToken: refresh}
 	tkn.Token, err = h.Auth.GenerateToken(claims)
 	if err != nil {
 		return fmt.Errorf("generating token: %w", err)
@@ -219,6 +570,37 @@ func (h Handlers) NewToken(ctx context.Context, w http.ResponseWriter, r *http.R
 	return web.Respond(ctx, w, tkn, http.StatusOK)
 }
 
+// Logout revokes the API token of the request and its refresh tokens.
+func (h Handlers) Logout(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	v, err := web.GetValues(ctx)
+	if err != nil {
+		return web.NewShutdownError("web value missing from context")
+	}
+
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	if err := h.User.Logout(ctx, claims, v.Now); err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidID):
+			return v1Web.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("logout: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// token is the response of the endpoints that issue API tokens.
+type token struct {
+	Token         string   `json:"token"`
+	RefreshToken  string   `json:"refresh_token"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
 // QueryUserProjects returns a list of projects for the user.
 func (h Handlers) QueryUserProjects(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	claims, err := auth.GetClaims(ctx)
@@ -237,7 +619,8 @@ func (h Handlers) QueryUserProjects(ctx context.Context, w http.ResponseWriter,
 		return v1Web.NewRequestError(fmt.Errorf("invalid rows format, rows[%s]", rows), http.StatusBadRequest)
 	}
 
-	UserProject, err := h.Project.QueryUserProjects(ctx, claims.Subject, pageNumber, rowsPerPage)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	UserProject, err := h.Project.QueryUserProjects(ctx, claims.Subject, includeArchived, pageNumber, rowsPerPage)
 	if err != nil {
 		switch {
 		case errors.Is(err, user.ErrInvalidID):
@@ -251,3 +634,13 @@ func (h Handlers) QueryUserProjects(ctx context.Context, w http.ResponseWriter,
 
 	return web.Respond(ctx, w, UserProject, http.StatusOK)
 }
+
+// clientIP returns the IP address the request came from, failed sign in
+// attempts are tracked by it.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -3,18 +3,39 @@
 package v1
 
 import (
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/accountcodegrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/apitokengrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/clientgrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/costrategrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/customfieldgrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/expensegrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/exportgrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/groupgrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/keygrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/milestonegrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/projectgrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/reportgrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/securityeventgrp"
+	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/ssogrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/taggrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/taskgrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/teamgrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/timeentrygrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/workspacegrp"
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/workspaceusergrp"
+	"github.com/AhmedShaef/wakt/business/core/accountcode"
+	"github.com/AhmedShaef/wakt/business/core/apitoken"
 	"github.com/AhmedShaef/wakt/business/core/client"
+	"github.com/AhmedShaef/wakt/business/core/costrate"
+	"github.com/AhmedShaef/wakt/business/core/customfield"
+	"github.com/AhmedShaef/wakt/business/core/expense"
+	"github.com/AhmedShaef/wakt/business/core/export"
 	"github.com/AhmedShaef/wakt/business/core/group"
+	"github.com/AhmedShaef/wakt/business/core/milestone"
 	"github.com/AhmedShaef/wakt/business/core/project"
+	"github.com/AhmedShaef/wakt/business/core/report"
+	"github.com/AhmedShaef/wakt/business/core/securityevent"
+	"github.com/AhmedShaef/wakt/business/core/sso"
 	"github.com/AhmedShaef/wakt/business/core/tag"
 	"github.com/AhmedShaef/wakt/business/core/task"
 	"github.com/AhmedShaef/wakt/business/core/team"
@@ -26,24 +47,52 @@ import (
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers/v1/usergrp"
 	"github.com/AhmedShaef/wakt/business/core/user"
 	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
 	"github.com/AhmedShaef/wakt/business/web/v1/mid"
 	"github.com/AhmedShaef/wakt/foundation/web"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 )
 
-// Config contains all the mandatory systems required by handlers.
+// Config contains all the mandatory systems required by handlers. Single
+// sign-on is only served when an OIDC provider is configured.
 type Config struct {
 	Log  *zap.SugaredLogger
 	Auth *auth.Auth
 	DB   *sqlx.DB
+	OIDC oidc.Provider
 }
 
 // Routes binds all the version 1 routes.
 func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
-	authen := mid.Authenticate(cfg.Auth)
+	authen := mid.Authenticate(cfg.Auth, apitoken.NewCore(cfg.Log, cfg.DB).Authenticate, user.NewCore(cfg.Log, cfg.DB).CheckSession)
+	readEntries := mid.Scope(apitoken.ScopeTimeEntriesRead)
+	writeEntries := mid.Scope(apitoken.ScopeTimeEntriesWrite)
+	readReports := mid.Scope(apitoken.ScopeReportsRead)
+
+	// Register personal API token endpoints.
+	atgh := apitokengrp.Handlers{
+		APIToken: apitoken.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPost, version, "/apitoken", atgh.Create, authen)
+	app.Handle(http.MethodGet, version, "/apitoken", atgh.QueryUserTokens, authen)
+	app.Handle(http.MethodDelete, version, "/apitoken/:id", atgh.Delete, authen)
+
+	// Register account code management endpoints.
+	acgh := accountcodegrp.Handlers{
+		AccountCode:   accountcode.NewCore(cfg.Log, cfg.DB),
+		Project:       project.NewCore(cfg.Log, cfg.DB),
+		Client:        client.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPost, version, "/accountcode", acgh.Create, authen)
+	app.Handle(http.MethodPut, version, "/accountcode/:id", acgh.Update, authen)
+	app.Handle(http.MethodDelete, version, "/accountcode/:id", acgh.Delete, authen)
+	app.Handle(http.MethodGet, version, "/workspace/:id/accountcodes/:page/:rows", acgh.QueryWorkspaceAccountCodes, authen)
 
 	// Register client management endpoints.
 	cgh := clientgrp.Handlers{
@@ -59,6 +108,78 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodGet, version, "/client/:id", cgh.QueryByID, authen)
 	app.Handle(http.MethodGet, version, "/client/:page/:rows", cgh.Query, authen)
 	app.Handle(http.MethodGet, version, "/client/:id/project/:page/:rows", cgh.QueryClientProjects, authen)
+	app.Handle(http.MethodPut, version, "/client/:id/archive", cgh.Archive, authen)
+	app.Handle(http.MethodPut, version, "/client/:id/unarchive", cgh.Unarchive, authen)
+	app.Handle(http.MethodPost, version, "/client/:id/contact", cgh.CreateContact, authen)
+	app.Handle(http.MethodPut, version, "/client/:id/contact/:contact_id", cgh.UpdateContact, authen)
+	app.Handle(http.MethodDelete, version, "/client/:id/contact/:contact_id", cgh.DeleteContact, authen)
+	app.Handle(http.MethodGet, version, "/client/:id/contacts", cgh.QueryClientContacts, authen)
+
+	// Register cost rate management endpoints.
+	crgh := costrategrp.Handlers{
+		CostRate:      costrate.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPost, version, "/costrate", crgh.Create, authen)
+	app.Handle(http.MethodPut, version, "/costrate/:id", crgh.Update, authen)
+	app.Handle(http.MethodDelete, version, "/costrate/:id", crgh.Delete, authen)
+	app.Handle(http.MethodGet, version, "/workspace/:id/costrates/:page/:rows", crgh.QueryWorkspaceCostRates, authen)
+
+	// Register security event endpoints.
+	segh := securityeventgrp.Handlers{
+		SecurityEvent: securityevent.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodGet, version, "/workspace/:id/security_events/:page/:rows", segh.QueryWorkspaceEvents, authen)
+
+	// Register custom field management endpoints.
+	cfgh := customfieldgrp.Handlers{
+		CustomField:   customfield.NewCore(cfg.Log, cfg.DB),
+		Project:       project.NewCore(cfg.Log, cfg.DB),
+		Task:          task.NewCore(cfg.Log, cfg.DB),
+		TimeEntry:     timeentry.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPost, version, "/workspace/:id/fields", cfgh.Create, authen)
+	app.Handle(http.MethodGet, version, "/workspace/:id/fields", cfgh.QueryWorkspaceFields, authen)
+	app.Handle(http.MethodGet, version, "/field/:id", cfgh.QueryByID, authen)
+	app.Handle(http.MethodPut, version, "/field/:id", cfgh.Update, authen)
+	app.Handle(http.MethodDelete, version, "/field/:id", cfgh.Delete, authen)
+	app.Handle(http.MethodGet, version, "/project/:id/fields", cfgh.QueryProjectValues, authen)
+	app.Handle(http.MethodPut, version, "/project/:id/fields", cfgh.SetProjectValues, authen)
+	app.Handle(http.MethodGet, version, "/task/:id/fields", cfgh.QueryTaskValues, authen)
+	app.Handle(http.MethodPut, version, "/task/:id/fields", cfgh.SetTaskValues, authen)
+	app.Handle(http.MethodGet, version, "/timeEntry/:id/fields", cfgh.QueryTimeEntryValues, authen, readEntries)
+	app.Handle(http.MethodPut, version, "/timeEntry/:id/fields", cfgh.SetTimeEntryValues, authen, writeEntries)
+
+	// Register expense management endpoints.
+	egh := expensegrp.Handlers{
+		Expense:       expense.NewCore(cfg.Log, cfg.DB),
+		Project:       project.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPost, version, "/expense", egh.Create, authen)
+	app.Handle(http.MethodPut, version, "/expense/:id", egh.Update, authen)
+	app.Handle(http.MethodPost, version, "/expense/:id/receipt", egh.UpdateReceipt, authen)
+	app.Handle(http.MethodPut, version, "/expense/:id/invoice", egh.BulkInvoice, authen)
+	app.Handle(http.MethodDelete, version, "/expense/:id", egh.Delete, authen)
+	app.Handle(http.MethodGet, version, "/expense/:id", egh.QueryByID, authen)
+	app.Handle(http.MethodGet, version, "/project/:id/expense/:page/:rows", egh.QueryProjectExpenses, authen)
+
+	// Register export endpoints.
+	exgh := exportgrp.Handlers{
+		Export:        export.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPut, version, "/workspace/:id/exportformat/:kind", exgh.SaveFormat, authen)
+	app.Handle(http.MethodGet, version, "/workspace/:id/exportformat/:kind", exgh.QueryFormat, authen)
+	app.Handle(http.MethodGet, version, "/workspace/:id/export/ledger", exgh.Ledger, authen, readReports)
+	app.Handle(http.MethodGet, version, "/workspace/:id/export/payroll", exgh.Payroll, authen, readReports)
 
 	// Register group management endpoints.
 	ggh := groupgrp.Handlers{
@@ -70,6 +191,26 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodPost, version, "/group", ggh.Create, authen)
 	app.Handle(http.MethodPut, version, "/group/:id", ggh.Update, authen)
 	app.Handle(http.MethodDelete, version, "/group/:id", ggh.Delete, authen)
+	app.Handle(http.MethodPost, version, "/group/:id/members", ggh.AddMembers, authen)
+	app.Handle(http.MethodDelete, version, "/group/:id/members/:uids", ggh.RemoveMembers, authen)
+	app.Handle(http.MethodGet, version, "/group/:id/members/:page/:rows", ggh.QueryMembers, authen)
+	app.Handle(http.MethodPost, version, "/group/:id/projects", ggh.AssignProject, authen)
+	app.Handle(http.MethodDelete, version, "/group/:id/projects/:pid", ggh.UnassignProject, authen)
+	app.Handle(http.MethodGet, version, "/group/:id/projects", ggh.QueryProjects, authen)
+
+	// Register milestone management endpoints.
+	mgh := milestonegrp.Handlers{
+		Milestone:     milestone.NewCore(cfg.Log, cfg.DB),
+		Project:       project.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodPost, version, "/milestone", mgh.Create, authen)
+	app.Handle(http.MethodPut, version, "/milestone/:id", mgh.Update, authen)
+	app.Handle(http.MethodDelete, version, "/milestone/:id", mgh.Delete, authen)
+	app.Handle(http.MethodGet, version, "/milestone/:id", mgh.QueryByID, authen)
+	app.Handle(http.MethodGet, version, "/milestone/:id/progress", mgh.QueryProgress, authen)
+	app.Handle(http.MethodGet, version, "/project/:id/timeline", mgh.QueryTimeline, authen)
 
 	// Register project management endpoints.
 	pgh := projectgrp.Handlers{
@@ -84,8 +225,31 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodPost, version, "/project", pgh.Create, authen)
 	app.Handle(http.MethodPut, version, "/project/:id", pgh.Update, authen)
 	app.Handle(http.MethodDelete, version, "/project/:id", pgh.BulkDelete, authen)
+	app.Handle(http.MethodPut, version, "/project/:id/archive", pgh.BulkArchive, authen)
+	app.Handle(http.MethodPut, version, "/project/:id/restore", pgh.BulkRestore, authen)
 	app.Handle(http.MethodGet, version, "/project/:id", pgh.QueryByID, authen)
 	app.Handle(http.MethodGet, version, "/project/:id/task/:page/:rows", pgh.QueryProjectTasks, authen)
+	app.Handle(http.MethodPost, version, "/project/:id/clone", pgh.Clone, authen)
+	app.Handle(http.MethodPost, version, "/project/:id/move", pgh.Move, authen)
+	app.Handle(http.MethodPost, version, "/project/:id/template", pgh.SaveTemplate, authen)
+	app.Handle(http.MethodPost, version, "/template/:id/project", pgh.CreateFromTemplate, authen)
+	app.Handle(http.MethodDelete, version, "/template/:id", pgh.DeleteTemplate, authen)
+	app.Handle(http.MethodGet, version, "/template/:id", pgh.QueryTemplateByID, authen)
+	app.Handle(http.MethodGet, version, "/workspace/:id/templates/:page/:rows", pgh.QueryWorkspaceTemplates, authen)
+
+	// Register report endpoints.
+	rgh := reportgrp.Handlers{
+		Report:        report.NewCore(cfg.Log, cfg.DB),
+		Project:       project.NewCore(cfg.Log, cfg.DB),
+		Client:        client.NewCore(cfg.Log, cfg.DB),
+		Workspace:     workspace.NewCore(cfg.Log, cfg.DB),
+		WorkspaceUser: workspaceuser.NewCore(cfg.Log, cfg.DB),
+	}
+
+	app.Handle(http.MethodGet, version, "/workspace/:id/report/projects", rgh.QueryProjectProfitability, authen, readReports)
+	app.Handle(http.MethodGet, version, "/workspace/:id/report/clients", rgh.QueryClientProfitability, authen, readReports)
+	app.Handle(http.MethodGet, version, "/project/:id/budget", rgh.QueryProjectBudget, authen, readReports)
+	app.Handle(http.MethodGet, version, "/client/:id/invoice", rgh.QueryClientInvoice, authen, readReports)
 
 	// Register team management endpoints.
 	pugh := teamgrp.Handlers{
@@ -108,10 +272,12 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodPost, version, "/tag", tgh.Create, authen)
 	app.Handle(http.MethodPut, version, "/tag/:id", tgh.Update, authen)
 	app.Handle(http.MethodDelete, version, "/tag/:id", tgh.Delete, authen)
+	app.Handle(http.MethodPost, version, "/tag/:id/merge", tgh.Merge, authen)
 
 	// Register task management endpoints.
 	tkgh := taskgrp.Handlers{
 		Task:      task.NewCore(cfg.Log, cfg.DB),
+		Project:   project.NewCore(cfg.Log, cfg.DB),
 		Workspace: workspace.NewCore(cfg.Log, cfg.DB),
 		User:      user.NewCore(cfg.Log, cfg.DB),
 	}
@@ -120,24 +286,31 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodPut, version, "/task/:id", tkgh.BulkUpdate, authen)
 	app.Handle(http.MethodDelete, version, "/task/:id", tkgh.BulkDelete, authen)
 	app.Handle(http.MethodGet, version, "/task/:id", tkgh.QueryByID, authen)
+	app.Handle(http.MethodPost, version, "/task/:id/dependency", tkgh.AddDependency, authen)
+	app.Handle(http.MethodDelete, version, "/task/:id/dependency/:blocked_by", tkgh.RemoveDependency, authen)
+	app.Handle(http.MethodGet, version, "/project/:id/board", tkgh.QueryBoard, authen)
+	app.Handle(http.MethodGet, version, "/project/:id/graph", tkgh.QueryGraph, authen)
+	app.Handle(http.MethodGet, version, "/user_tasks", tkgh.QueryUserTasks, authen)
 
 	// Register time entry management endpoints.
 	tegh := timeentrygrp.Handlers{
-		TimeEntry: timeentry.NewCore(cfg.Log, cfg.DB),
-		Workspace: workspace.NewCore(cfg.Log, cfg.DB),
-		User:      user.NewCore(cfg.Log, cfg.DB),
+		TimeEntry:   timeentry.NewCore(cfg.Log, cfg.DB),
+		Project:     project.NewCore(cfg.Log, cfg.DB),
+		Workspace:   workspace.NewCore(cfg.Log, cfg.DB),
+		User:        user.NewCore(cfg.Log, cfg.DB),
+		CustomField: customfield.NewCore(cfg.Log, cfg.DB),
 	}
 
-	app.Handle(http.MethodPost, version, "/timeEntry", tegh.Create, authen)
-	app.Handle(http.MethodPost, version, "/timeEntry/start", tegh.Start, authen)
-	app.Handle(http.MethodPut, version, "/timeEntry/:id/stop", tegh.Stop, authen)
-	app.Handle(http.MethodGet, version, "/timeEntry/:id", tegh.QueryByID, authen)
-	app.Handle(http.MethodGet, version, "/timeEntry/running/:page/:rows", tegh.QueryRunning, authen)
-	app.Handle(http.MethodPut, version, "/timeEntry/update/:id", tegh.Update, authen)
-	app.Handle(http.MethodPut, version, "/timeEntry/tags/:id", tegh.UpdateTags, authen)
-	app.Handle(http.MethodDelete, version, "/timeEntry/delete/:id", tegh.Delete, authen)
-	app.Handle(http.MethodGet, version, "/timeEntry/:page/:rows", tegh.QueryRange, authen)
-	app.Handle(http.MethodGet, version, "/dashboard", tegh.QueryDash, authen)
+	app.Handle(http.MethodPost, version, "/timeEntry", tegh.Create, authen, writeEntries)
+	app.Handle(http.MethodPost, version, "/timeEntry/start", tegh.Start, authen, writeEntries)
+	app.Handle(http.MethodPut, version, "/timeEntry/:id/stop", tegh.Stop, authen, writeEntries)
+	app.Handle(http.MethodGet, version, "/timeEntry/:id", tegh.QueryByID, authen, readEntries)
+	app.Handle(http.MethodGet, version, "/timeEntry/running/:page/:rows", tegh.QueryRunning, authen, readEntries)
+	app.Handle(http.MethodPut, version, "/timeEntry/update/:id", tegh.Update, authen, writeEntries)
+	app.Handle(http.MethodPut, version, "/timeEntry/tags/:id", tegh.UpdateTags, authen, writeEntries)
+	app.Handle(http.MethodDelete, version, "/timeEntry/delete/:id", tegh.Delete, authen, writeEntries)
+	app.Handle(http.MethodGet, version, "/timeEntry/:page/:rows", tegh.QueryRange, authen, readEntries)
+	app.Handle(http.MethodGet, version, "/dashboard", tegh.QueryDash, authen, readEntries)
 
 	// Register user management and authentication endpoints.
 	ugh := usergrp.Handlers{
@@ -149,6 +322,18 @@ func Routes(app *web.App, cfg Config) {
 	}
 	app.Handle(http.MethodPost, version, "/signup", ugh.SignUp)
 	app.Handle(http.MethodGet, version, "/token", ugh.NewToken)
+	app.Handle(http.MethodPost, version, "/token/refresh", ugh.RefreshToken)
+	app.Handle(http.MethodPost, version, "/token/two_factor", ugh.TwoFactorToken)
+	app.Handle(http.MethodPost, version, "/token/two_factor/enroll", ugh.EnrollChallenge)
+	app.Handle(http.MethodPost, version, "/two_factor", ugh.EnrollTwoFactor, authen)
+	app.Handle(http.MethodPost, version, "/two_factor/confirm", ugh.ConfirmTwoFactor, authen)
+	app.Handle(http.MethodPost, version, "/two_factor/disable", ugh.DisableTwoFactor, authen)
+	app.Handle(http.MethodPost, version, "/logout", ugh.Logout, authen)
+	app.Handle(http.MethodPost, version, "/forgot_password", ugh.ForgotPassword)
+	app.Handle(http.MethodPost, version, "/reset_password", ugh.ResetPassword)
+	app.Handle(http.MethodPost, version, "/verify_email", ugh.VerifyEmail)
+	app.Handle(http.MethodPost, version, "/unlock_account", ugh.UnlockAccount)
+	app.Handle(http.MethodPost, version, "/verify_email/resend", ugh.ResendVerification, authen)
 	app.Handle(http.MethodPost, version, "/image", ugh.UpdateImage, authen)
 	app.Handle(http.MethodGet, version, "/me", ugh.QueryByID, authen)
 	app.Handle(http.MethodPut, version, "/me", ugh.Update, authen)
@@ -156,6 +341,26 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodPut, version, "/change_image", ugh.UpdateImage, authen)
 	app.Handle(http.MethodGet, version, "/user_projects/:page/:rows", ugh.QueryUserProjects, authen)
 
+	// Register single sign-on endpoints.
+	if cfg.OIDC.Issuer != "" {
+		ssgh := ssogrp.Handlers{
+			SSO:  sso.NewCore(cfg.Log, cfg.DB, cfg.OIDC),
+			User: user.NewCore(cfg.Log, cfg.DB),
+			Auth: cfg.Auth,
+		}
+
+		app.Handle(http.MethodGet, version, "/oidc/login", ssgh.Login)
+		app.Handle(http.MethodGet, version, "/oidc/callback", ssgh.Callback)
+	}
+
+	// Register signing key endpoints. These live at the well-known path
+	// rather than under the version.
+	kgh := keygrp.Handlers{
+		Auth: cfg.Auth,
+	}
+
+	app.Handle(http.MethodGet, "", "/.well-known/jwks.json", kgh.JWKS)
+
 	// Register workspace management endpoints.
 	wgh := workspacegrp.Handlers{
 		Workspace:     workspace.NewCore(cfg.Log, cfg.DB),
@@ -449,7 +449,8 @@ func (h Handlers) QueryWorkspaceProjects(ctx context.Context, w http.ResponseWri
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
-	work, err := h.Project.QueryWorkspaceProjects(ctx, workspaceID, pageNumber, rowsPerPage)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	work, err := h.Project.QueryWorkspaceProjects(ctx, workspaceID, claims.Subject, includeArchived, pageNumber, rowsPerPage)
 	if err != nil {
 		return fmt.Errorf("unable to query for workspaces: %w", err)
 	}
@@ -493,7 +494,7 @@ func (h Handlers) QueryWorkspaceTasks(ctx context.Context, w http.ResponseWriter
 		return v1Web.NewRequestError(auth.ErrForbidden, http.StatusForbidden)
 	}
 
-	work, err := h.Task.QueryWorkspaceTasks(ctx, workspaceID, pageNumber, rowsPerPage)
+	work, err := h.Task.QueryWorkspaceTasks(ctx, workspaceID, claims.Subject, pageNumber, rowsPerPage)
 	if err != nil {
 		return fmt.Errorf("unable to query for workspaces: %w", err)
 	}
@@ -59,6 +59,8 @@ func (h Handlers) Invite(ctx context.Context, w http.ResponseWriter, r *http.Req
 			return v1Web.NewRequestError(err, http.StatusBadRequest)
 		case errors.Is(err, workspaceuser.ErrNotFound):
 			return v1Web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, workspaceuser.ErrUnverified):
+			return v1Web.NewRequestError(err, http.StatusForbidden)
 		default:
 			return fmt.Errorf("workspaceUser[%+v]: %w", &clint, err)
 		}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/AhmedShaef/wakt/app/services/wakt-api/handlers"
 	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
 	"github.com/AhmedShaef/wakt/foundation/logger"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -85,6 +86,13 @@ func run(log *zap.SugaredLogger) error {
 			KeysFolder string `conf:"default:zarf/keys/"`
 			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
 		}
+		OIDC struct {
+			Issuer         string
+			ClientID       string
+			ClientSecret   string `conf:"mask"`
+			RedirectURL    string `conf:"default:http://localhost:3000/v1/oidc/callback"`
+			AllowedDomains []string
+		}
 		DB struct {
 			User         string `conf:"default:postgres"`
 			Password     string `conf:"default:postgres,mask"`
@@ -142,11 +150,43 @@ func run(log *zap.SugaredLogger) error {
 		return fmt.Errorf("reading keys: %w", err)
 	}
 
-	authN, err := auth.New(cfg.Auth.ActiveKID, ks)
+	activeKID, err := activeKey(cfg.Auth.KeysFolder, cfg.Auth.ActiveKID)
+	if err != nil {
+		return err
+	}
+
+	authN, err := auth.New(activeKID, ks)
 	if err != nil {
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	retired, err := keystore.ReadRetired(os.DirFS(cfg.Auth.KeysFolder))
+	if err != nil {
+		return err
+	}
+	authN.SetRetired(retired)
+
+	// Reload the key folder on a SIGHUP so keys can be added, promoted and
+	// retired without a restart.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	// Stop listening on the way out, which ends the reload goroutine.
+	defer func() {
+		signal.Stop(reload)
+		close(reload)
+	}()
+
+	go func() {
+		for range reload {
+			if err := reloadKeys(ks, authN, cfg.Auth.KeysFolder, cfg.Auth.ActiveKID); err != nil {
+				log.Errorw("reload", "status", "keys not reloaded", "ERROR", err)
+				continue
+			}
+			log.Infow("reload", "status", "keys reloaded", "activeKID", authN.ActiveKID())
+		}
+	}()
+
 	// =========================================================================
 	// Database Support
 
@@ -220,6 +260,13 @@ func run(log *zap.SugaredLogger) error {
 		Log:      log,
 		Auth:     authN,
 		DB:       db,
+		OIDC: oidc.Provider{
+			Issuer:         cfg.OIDC.Issuer,
+			ClientID:       cfg.OIDC.ClientID,
+			ClientSecret:   cfg.OIDC.ClientSecret,
+			RedirectURL:    cfg.OIDC.RedirectURL,
+			AllowedDomains: cfg.OIDC.AllowedDomains,
+		},
 	})
 
 	// Construct a server to service the requests against the mux.
@@ -271,6 +318,47 @@ func run(log *zap.SugaredLogger) error {
 
 // =============================================================================
 
+// activeKey returns the kid of the key to sign tokens with, which was either
+// promoted by the admin tooling or is the configured one.
+func activeKey(keysFolder string, configured string) (string, error) {
+	kid, err := keystore.ReadActive(os.DirFS(keysFolder))
+	if err != nil {
+		return "", err
+	}
+
+	if kid == "" {
+		return configured, nil
+	}
+	return kid, nil
+}
+
+// reloadKeys reads the key folder again and promotes the active key. The
+// active key is only changed once the new set of keys is in place, so
+// tokens are never signed with a key that can't be validated. Retired keys
+// stop validating tokens once those have expired.
+func reloadKeys(ks *keystore.KeyStore, authN *auth.Auth, keysFolder string, configured string) error {
+	kid, err := activeKey(keysFolder, configured)
+	if err != nil {
+		return err
+	}
+
+	retired, err := keystore.ReadRetired(os.DirFS(keysFolder))
+	if err != nil {
+		return err
+	}
+
+	if err := ks.Reload(os.DirFS(keysFolder)); err != nil {
+		return fmt.Errorf("reading keys: %w", err)
+	}
+
+	if err := authN.SetActiveKID(kid); err != nil {
+		return fmt.Errorf("promoting kid[%s]: %w", kid, err)
+	}
+	authN.SetRetired(retired)
+
+	return nil
+}
+
 // startTracing configure open telemetry to be used with zipkin.
 func startTracing(serviceName string, reporterURI string, probability float64) (*trace.TracerProvider, error) {
 
@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -9,15 +13,34 @@ import (
 	"os"
 )
 
-// GenKey creates an x509 private/public key for auth tokens.
-func GenKey() error {
+// Set of key types that can be generated, each signs tokens with its own
+// algorithm.
+const (
+	KeyTypeRSA     = "rsa"     // RS256
+	KeyTypeECDSA   = "ecdsa"   // ES256
+	KeyTypeEd25519 = "ed25519" // EdDSA
+)
+
+// GenKey creates an x509 private/public key for auth tokens of the key type,
+// RSA when none is given.
+func GenKey(keyType string) error {
+	if keyType == "" {
+		keyType = KeyTypeRSA
+	}
 
 	// Generate a new private key.
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey, err := generateKey(keyType)
 	if err != nil {
+		fmt.Println("help: genkey [rsa|ecdsa|ed25519]")
 		return ErrHelp
 	}
 
+	// Construct a PEM block for the private key.
+	privateBlock, err := privatePEM(privateKey)
+	if err != nil {
+		return err
+	}
+
 	// Create a file for the private key information in PEM form.
 	privateFile, err := os.Create("private.pem")
 	if err != nil {
@@ -25,19 +48,13 @@ func GenKey() error {
 	}
 	defer privateFile.Close()
 
-	// Construct a PEM block for the private key.
-	privateBlock := pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
-	}
-
 	// Write the private key to the private key file.
 	if err := pem.Encode(privateFile, &privateBlock); err != nil {
 		return fmt.Errorf("encoding to private file: %w", err)
 	}
 
 	// Marshal the public key from the private key to PKIX.
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.(crypto.Signer).Public())
 	if err != nil {
 		return fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -51,15 +68,56 @@ func GenKey() error {
 
 	// Construct a PEM block for the public key.
 	publicBlock := pem.Block{
-		Type:  "RSA PUBLIC KEY",
+		Type:  "PUBLIC KEY",
 		Bytes: asn1Bytes,
 	}
+	if keyType == KeyTypeRSA {
+		publicBlock.Type = "RSA PUBLIC KEY"
+	}
 
 	// Write the public key to the private key file.
 	if err := pem.Encode(publicFile, &publicBlock); err != nil {
 		return fmt.Errorf("encoding to public file: %w", err)
 	}
 
-	fmt.Println("private and public key files generated")
+	fmt.Printf("private and public %s key files generated\n", keyType)
 	return nil
 }
+
+// generateKey generates a new private key of the key type.
+func generateKey(keyType string) (crypto.PrivateKey, error) {
+	switch keyType {
+	case KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyTypeECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeEd25519:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	default:
+		return nil, fmt.Errorf("key type %q is not supported", keyType)
+	}
+}
+
+// privatePEM constructs the PEM block of a private key, in the form the key
+// store reads it in.
+func privatePEM(privateKey crypto.PrivateKey) (pem.Block, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}, nil
+
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return pem.Block{}, fmt.Errorf("marshaling private key: %w", err)
+		}
+		return pem.Block{Type: "EC PRIVATE KEY", Bytes: der}, nil
+
+	default:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return pem.Block{}, fmt.Errorf("marshaling private key: %w", err)
+		}
+		return pem.Block{Type: "PRIVATE KEY", Bytes: der}, nil
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/AhmedShaef/wakt/foundation/keystore"
+)
+
+// RotateKey drives the rotation of the key auth tokens are signed with. With
+// no argument or a key type it adds a new key to the key folder, with a kid
+// it promotes that key to sign new tokens. The service picks up either step
+// on a SIGHUP. The active kid is the one the service is configured with,
+// used until a key was promoted.
+//
+// Adding and promoting are separate steps so every instance of the service
+// can validate tokens signed with the new key before any of them signs one.
+func RotateKey(keysFolder string, activeKID string, arg string) error {
+	switch arg {
+	case "":
+		return addKey(keysFolder, KeyTypeRSA)
+	case KeyTypeRSA, KeyTypeECDSA, KeyTypeEd25519:
+		return addKey(keysFolder, arg)
+	default:
+		return promoteKey(keysFolder, activeKID, arg)
+	}
+}
+
+// addKey generates a new private key of the key type in the key folder.
+func addKey(keysFolder string, keyType string) error {
+	privateKey, err := generateKey(keyType)
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	privateBlock, err := privatePEM(privateKey)
+	if err != nil {
+		return err
+	}
+
+	kid := validate.GenerateID()
+	fileName := filepath.Join(keysFolder, kid+".pem")
+
+	// Only the service needs to read the private key.
+	if err := os.WriteFile(fileName, pem.EncodeToMemory(&privateBlock), 0600); err != nil {
+		return fmt.Errorf("writing key file: %w", err)
+	}
+
+	fmt.Printf("%s key %s added to %s\n", keyType, kid, keysFolder)
+	fmt.Println("send SIGHUP to the service to publish it, then promote it with:")
+	fmt.Printf("rotatekey %s\n", kid)
+	return nil
+}
+
+// promoteKey makes the key the active one by recording its kid in the key
+// folder. The previous active key is recorded as retired, the service stops
+// validating tokens with it once they have expired.
+func promoteKey(keysFolder string, activeKID string, kid string) error {
+	fsys := os.DirFS(keysFolder)
+
+	ks, err := keystore.NewFS(fsys)
+	if err != nil {
+		return fmt.Errorf("reading keys: %w", err)
+	}
+
+	if _, err := ks.PrivateKey(kid); err != nil {
+		return fmt.Errorf("kid[%s] is not in %s", kid, keysFolder)
+	}
+
+	previous, err := keystore.ReadActive(fsys)
+	if err != nil {
+		return err
+	}
+	if previous == "" {
+		previous = activeKID
+	}
+
+	retired, err := keystore.ReadRetired(fsys)
+	if err != nil {
+		return err
+	}
+	if previous != kid {
+		retired[previous] = time.Now().UTC()
+	}
+	delete(retired, kid)
+
+	data, err := json.MarshalIndent(retired, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding retired kids: %w", err)
+	}
+
+	// The retirement counts from now, the service should be sent the SIGHUP
+	// right away so the previous key stops signing tokens at the same time.
+	if err := writeFile(filepath.Join(keysFolder, keystore.RetiredFile), append(data, '\n')); err != nil {
+		return fmt.Errorf("writing retired kids: %w", err)
+	}
+	if err := writeFile(filepath.Join(keysFolder, keystore.ActiveFile), []byte(kid+"\n")); err != nil {
+		return fmt.Errorf("writing active kid: %w", err)
+	}
+
+	fmt.Printf("key %s promoted, send SIGHUP to the service to sign with it\n", kid)
+	fmt.Printf("key %s stops validating tokens in %s, its file can be removed then\n", previous, auth.TokenTTL)
+	return nil
+}
+
+// writeFile writes the data aside and renames it into place so the service
+// never reads a partial file.
+func writeFile(fileName string, data []byte) error {
+	if err := os.WriteFile(fileName+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(fileName+".tmp", fileName)
+}
@@ -44,7 +44,11 @@ func run(log *zap.SugaredLogger) error {
 	cfg := struct {
 		conf.Version
 		Args conf.Args
-		DB   struct {
+		Auth struct {
+			KeysFolder string `conf:"default:zarf/keys/"`
+			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
+		}
+		DB struct {
 			User       string `conf:"default:postgres"`
 			Password   string `conf:"default:postgres,mask"`
 			Host       string `conf:"default:localhost"`
@@ -85,12 +89,12 @@ func run(log *zap.SugaredLogger) error {
 		DisableTLS: cfg.DB.DisableTLS,
 	}
 
-	return processCommands(cfg.Args, log, dbConfig)
+	return processCommands(cfg.Args, log, dbConfig, cfg.Auth.KeysFolder, cfg.Auth.ActiveKID)
 }
 
 // processCommands handles the execution of the commands specified on
 // the command line.
-func processCommands(args conf.Args, log *zap.SugaredLogger, dbConfig database.Config) error {
+func processCommands(args conf.Args, log *zap.SugaredLogger, dbConfig database.Config, keysFolder string, activeKID string) error {
 	switch args.Num(0) {
 	case "migrate":
 		if err := commands.Migrate(dbConfig); err != nil {
@@ -103,7 +107,8 @@ func processCommands(args conf.Args, log *zap.SugaredLogger, dbConfig database.C
 		}
 
 	case "genkey":
-		if err := commands.GenKey(); err != nil {
+		keyType := args.Num(1)
+		if err := commands.GenKey(keyType); err != nil {
 			return fmt.Errorf("key generation: %w", err)
 		}
 
@@ -114,13 +119,20 @@ func processCommands(args conf.Args, log *zap.SugaredLogger, dbConfig database.C
 			return fmt.Errorf("generating token: %w", err)
 		}
 
+	case "rotatekey":
+		arg := args.Num(1)
+		if err := commands.RotateKey(keysFolder, activeKID, arg); err != nil {
+			return fmt.Errorf("rotating key: %w", err)
+		}
+
 	default:
 		fmt.Println("migrate: create the schema in the database")
 		fmt.Println("seed: add data to the database")
 		fmt.Println("useradd: add a new user to the database")
 		fmt.Println("users: get a list of users from the database")
-		fmt.Println("genkey: generate a set of private/public key files")
+		fmt.Println("genkey: generate a set of private/public key files of a type: rsa, ecdsa or ed25519")
 		fmt.Println("gentoken: generate a JWT for a user with claims")
+		fmt.Println("rotatekey: add a new signing key of a type, or promote one by its kid")
 		fmt.Println("provide a command to get more help.")
 		return commands.ErrHelp
 	}
@@ -0,0 +1,159 @@
+// Package accountcode provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package accountcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/accountcode/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound      = errors.New("account code not found")
+	ErrInvalidID     = errors.New("ID is not in its proper form")
+	ErrInvalidTarget = errors.New("account code must map exactly one project or client")
+	ErrUniqueTarget  = errors.New("project or client already has an account code")
+)
+
+// Core manages the set of APIs for account code access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for account code api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// Create inserts a new account code into the database.
+func (c Core) Create(ctx context.Context, nac NewAccountCode, now time.Time) (AccountCode, error) {
+	if err := validate.Check(nac); err != nil {
+		return AccountCode{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	if err := validate.CheckID(nac.WID); err != nil {
+		return AccountCode{}, ErrInvalidID
+	}
+
+	if (nac.PID == "") == (nac.CID == "") {
+		return AccountCode{}, ErrInvalidTarget
+	}
+
+	dbAccountCode := db.AccountCode{
+		ID:          validate.GenerateID(),
+		WID:         nac.WID,
+		PID:         database.NullID(nac.PID),
+		CID:         database.NullID(nac.CID),
+		Code:        nac.Code,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if dbAccountCode.PID == "" {
+		dbAccountCode.PID = database.NoID
+	}
+	if dbAccountCode.CID == "" {
+		dbAccountCode.CID = database.NoID
+	}
+
+	if err := validate.CheckID(string(dbAccountCode.PID)); err != nil {
+		return AccountCode{}, ErrInvalidID
+	}
+	if err := validate.CheckID(string(dbAccountCode.CID)); err != nil {
+		return AccountCode{}, ErrInvalidID
+	}
+
+	if err := c.store.Create(ctx, dbAccountCode); err != nil {
+		if errors.Is(err, database.ErrDBDuplicatedEntry) {
+			return AccountCode{}, fmt.Errorf("create: %w", ErrUniqueTarget)
+		}
+		return AccountCode{}, fmt.Errorf("create: %w", err)
+	}
+
+	return toAccountCode(dbAccountCode), nil
+}
+
+// Update replaces an account code document in the database.
+func (c Core) Update(ctx context.Context, accountCodeID string, uac UpdateAccountCode, now time.Time) error {
+	if err := validate.CheckID(accountCodeID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(uac); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbAccountCode, err := c.store.QueryByID(ctx, accountCodeID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating account code accountCodeID[%s]: %w", accountCodeID, err)
+	}
+
+	if uac.Code != nil {
+		dbAccountCode.Code = *uac.Code
+	}
+	dbAccountCode.DateUpdated = now
+
+	if err := c.store.Update(ctx, dbAccountCode); err != nil {
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an account code from the database.
+func (c Core) Delete(ctx context.Context, accountCodeID string) error {
+	if err := validate.CheckID(accountCodeID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.Delete(ctx, accountCodeID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified account code from the database.
+func (c Core) QueryByID(ctx context.Context, accountCodeID string) (AccountCode, error) {
+	if err := validate.CheckID(accountCodeID); err != nil {
+		return AccountCode{}, ErrInvalidID
+	}
+
+	dbAccountCode, err := c.store.QueryByID(ctx, accountCodeID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return AccountCode{}, ErrNotFound
+		}
+		return AccountCode{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toAccountCode(dbAccountCode), nil
+}
+
+// QueryWorkspaceAccountCodes retrieves a list of existing account codes from the database.
+func (c Core) QueryWorkspaceAccountCodes(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]AccountCode, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []AccountCode{}, ErrInvalidID
+	}
+
+	dbAccountCodes, err := c.store.QueryWorkspaceAccountCodes(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toAccountCodeSlice(dbAccountCodes), nil
+}
@@ -0,0 +1,152 @@
+package accountcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbschema"
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestAccountCode(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testaccountcode")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to work with AccountCode records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a single AccountCode.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			nac := NewAccountCode{
+				WID:  "7da3ca14-6366-47cf-b953-f706226567d8",
+				PID:  "d774cc57-e4a6-4be2-bca1-cb50610fb3f5",
+				Code: "4200",
+			}
+
+			accountCode, err := core.Create(ctx, nac, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create AccountCode.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, accountCode.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve AccountCode by ID: %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve AccountCode by ID.", dbtest.Success, testID)
+
+			if diff := cmp.Diff(accountCode, saved); diff != "" {
+				t.Errorf("\t%s\tTest %d:\tShould get back the same AccountCode. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get back the same AccountCode.", dbtest.Success, testID)
+
+			upd := UpdateAccountCode{
+				Code: dbtest.StringPointer("4210"),
+			}
+
+			if err := core.Update(ctx, accountCode.ID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to update AccountCode.", dbtest.Success, testID)
+
+			saved, err = core.QueryByID(ctx, accountCode.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve updated AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve updated AccountCode.", dbtest.Success, testID)
+
+			if saved.Code != *upd.Code {
+				t.Errorf("\t%s\tTest %d:\tShould be able to see updates to Code.", dbtest.Failed, testID)
+				t.Logf("\t\tTest %d:\tGot: %v", testID, saved.Code)
+				t.Logf("\t\tTest %d:\tExp: %v", testID, *upd.Code)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould be able to see updates to Code.", dbtest.Success, testID)
+			}
+
+			if err := core.Delete(ctx, accountCode.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to delete AccountCode.", dbtest.Success, testID)
+
+			_, err = core.QueryByID(ctx, accountCode.ID)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to retrieve AccountCode.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestPagingAccountCode(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testpaging")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to page through AccountCode records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen paging through 2 AccountCodes.", testID)
+		{
+			ctx := context.Background()
+
+			accountCodes1, err := core.QueryWorkspaceAccountCodes(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 1, 1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve AccountCodes for page 1 : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve AccountCodes for page 1.", dbtest.Success, testID)
+
+			if len(accountCodes1) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single AccountCode.", dbtest.Success, testID)
+
+			accountCodes2, err := core.QueryWorkspaceAccountCodes(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 2, 1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve AccountCodes for page 2 : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve AccountCodes for page 2.", dbtest.Success, testID)
+
+			if len(accountCodes2) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single AccountCode : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single AccountCode.", dbtest.Success, testID)
+
+			if accountCodes1[0].ID == accountCodes2[0].ID {
+				t.Logf("\t\tTest %d:\tAccountCode1: %v", testID, accountCodes1[0].ID)
+				t.Logf("\t\tTest %d:\tAccountCode2: %v", testID, accountCodes2[0].ID)
+				t.Fatalf("\t%s\tTest %d:\tShould have different AccountCodes : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have different AccountCodes.", dbtest.Success, testID)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package db contains account code related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for account code access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new account code into the database.
+func (s Store) Create(ctx context.Context, accountCode AccountCode) error {
+	const q = `
+	INSERT INTO account_codes
+		(account_code_id, wid, pid, cid, code, date_created, date_updated)
+	VALUES
+		(:account_code_id, :wid, :pid, :cid, :code, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, accountCode); err != nil {
+		return fmt.Errorf("inserting account code: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an account code document in the database.
+func (s Store) Update(ctx context.Context, accountCode AccountCode) error {
+	const q = `
+	UPDATE
+		account_codes
+	SET
+		"code" = :code,
+		"date_updated" = :date_updated
+	WHERE
+		account_code_id = :account_code_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, accountCode); err != nil {
+		return fmt.Errorf("updating accountCodeID[%s]: %w", accountCode.ID, err)
+	}
+
+	return nil
+}
+
+// Delete removes an account code from the database.
+func (s Store) Delete(ctx context.Context, accountCodeID string) error {
+	data := struct {
+		AccountCodeID string `db:"account_code_id"`
+	}{
+		AccountCodeID: accountCodeID,
+	}
+
+	const q = `
+	DELETE FROM
+		account_codes
+	WHERE
+		account_code_id = :account_code_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting accountCodeID[%s]: %w", accountCodeID, err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified account code from the database.
+func (s Store) QueryByID(ctx context.Context, accountCodeID string) (AccountCode, error) {
+	data := struct {
+		AccountCodeID string `db:"account_code_id"`
+	}{
+		AccountCodeID: accountCodeID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		account_codes
+	WHERE
+		account_code_id = :account_code_id`
+
+	var accountCode AccountCode
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &accountCode); err != nil {
+		return AccountCode{}, fmt.Errorf("selecting accountCodeID[%q]: %w", accountCodeID, err)
+	}
+
+	return accountCode, nil
+}
+
+// QueryWorkspaceAccountCodes retrieves a list of existing account codes from the database.
+func (s Store) QueryWorkspaceAccountCodes(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]AccountCode, error) {
+	data := struct {
+		Offset      int    `db:"offset"`
+		RowsPerPage int    `db:"rows_per_page"`
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+		WorkspaceID: workspaceID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		account_codes
+	WHERE
+		wid = :workspace_id
+	ORDER BY
+		code
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var accountCodes []AccountCode
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &accountCodes); err != nil {
+		return nil, fmt.Errorf("selecting account codes: %w", err)
+	}
+
+	return accountCodes, nil
+}
@@ -0,0 +1,19 @@
+package db
+
+import (
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+)
+
+// AccountCode represent the structure we need for moving data
+// between the app and the database.
+type AccountCode struct {
+	ID          string          `db:"account_code_id"`
+	WID         string          `db:"wid"`
+	PID         database.NullID `db:"pid"`
+	CID         database.NullID `db:"cid"`
+	Code        string          `db:"code"`
+	DateCreated time.Time       `db:"date_created"`
+	DateUpdated time.Time       `db:"date_updated"`
+}
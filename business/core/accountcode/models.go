@@ -0,0 +1,55 @@
+package accountcode
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/accountcode/db"
+)
+
+// AccountCode represents the ledger account the billable hours of a project
+// or of a client are booked to. A project account code wins over the account
+// code of its client.
+type AccountCode struct {
+	ID          string    `json:"id"`
+	WID         string    `json:"wid"`
+	PID         string    `json:"pid"`
+	CID         string    `json:"cid"`
+	Code        string    `json:"code"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+// NewAccountCode contains information needed to create a new account code.
+// Exactly one of PID and CID must be set.
+type NewAccountCode struct {
+	WID  string `json:"wid" validate:"required"`
+	PID  string `json:"pid"`
+	CID  string `json:"cid"`
+	Code string `json:"code" validate:"required"`
+}
+
+// UpdateAccountCode defines what information may be provided to modify an existing
+// account code. All fields are optional so account codes can send just the fields they want
+// changed. It uses pointer fields ,so we can differentiate between a field that
+// was not provided and a field that was provided as explicitly blank. Normally
+// we do not want to use pointers to basic types ,but we make exceptions around
+// marshalling/unmarshalling.
+type UpdateAccountCode struct {
+	Code *string `json:"code" validate:"omitempty,min=1"`
+}
+
+// =============================================================================
+
+func toAccountCode(dbAccountCode db.AccountCode) AccountCode {
+	pu := (*AccountCode)(unsafe.Pointer(&dbAccountCode))
+	return *pu
+}
+
+func toAccountCodeSlice(dbAccountCodes []db.AccountCode) []AccountCode {
+	accountCodes := make([]AccountCode, len(dbAccountCodes))
+	for i, dbAccountCode := range dbAccountCodes {
+		accountCodes[i] = toAccountCode(dbAccountCode)
+	}
+	return accountCodes
+}
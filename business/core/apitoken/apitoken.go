@@ -0,0 +1,173 @@
+// Package apitoken provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/apitoken/db"
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound      = errors.New("api token not found")
+	ErrInvalidID     = errors.New("ID is not in its proper form")
+	ErrInvalidExpiry = errors.New("expiry date must be in the future")
+	ErrInvalidToken  = errors.New("api token is invalid or expired")
+)
+
+// prefix marks personal API tokens apart from the JWTs of sessions.
+const prefix = "wakt_"
+
+// Core manages the set of APIs for personal API token access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for personal API token api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// Create generates a new personal API token for a user. The token to
+// authenticate with is only returned here.
+func (c Core) Create(ctx context.Context, userID string, nt NewToken, now time.Time) (CreatedToken, error) {
+	if err := validate.CheckID(userID); err != nil {
+		return CreatedToken{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nt); err != nil {
+		return CreatedToken{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	if nt.DateExpires != nil && !nt.DateExpires.After(now) {
+		return CreatedToken{}, ErrInvalidExpiry
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return CreatedToken{}, fmt.Errorf("generating token: %w", err)
+	}
+	token := prefix + hex.EncodeToString(secret)
+
+	dbToken := db.Token{
+		ID:          validate.GenerateID(),
+		UID:         userID,
+		Name:        nt.Name,
+		TokenHash:   hashToken(token),
+		Scopes:      nt.Scopes,
+		DateExpires: nt.DateExpires,
+		DateCreated: now,
+	}
+
+	if err := c.store.Create(ctx, dbToken); err != nil {
+		return CreatedToken{}, fmt.Errorf("create: %w", err)
+	}
+
+	return CreatedToken{Token: toToken(dbToken), Secret: token}, nil
+}
+
+// Delete revokes a personal API token by removing it from the database.
+func (c Core) Delete(ctx context.Context, tokenID string) error {
+	if err := validate.CheckID(tokenID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.Delete(ctx, tokenID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified personal API token from the database.
+func (c Core) QueryByID(ctx context.Context, tokenID string) (Token, error) {
+	if err := validate.CheckID(tokenID); err != nil {
+		return Token{}, ErrInvalidID
+	}
+
+	dbToken, err := c.store.QueryByID(ctx, tokenID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Token{}, ErrNotFound
+		}
+		return Token{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toToken(dbToken), nil
+}
+
+// QueryUserTokens retrieves the personal API tokens of a user.
+func (c Core) QueryUserTokens(ctx context.Context, userID string) ([]Token, error) {
+	if err := validate.CheckID(userID); err != nil {
+		return []Token{}, ErrInvalidID
+	}
+
+	dbTokens, err := c.store.QueryUserTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toTokenSlice(dbTokens), nil
+}
+
+// Authenticate finds the personal API token and records its use. On success
+// it returns the claims of the user the token belongs to along with the
+// scopes it was granted.
+func (c Core) Authenticate(ctx context.Context, token string, now time.Time) (auth.Claims, []string, error) {
+	if !strings.HasPrefix(token, prefix) {
+		return auth.Claims{}, nil, ErrInvalidToken
+	}
+
+	dbToken, err := c.store.QueryByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return auth.Claims{}, nil, ErrInvalidToken
+		}
+		return auth.Claims{}, nil, fmt.Errorf("query: %w", err)
+	}
+
+	if dbToken.DateExpires != nil && !now.Before(*dbToken.DateExpires) {
+		return auth.Claims{}, nil, ErrInvalidToken
+	}
+
+	if err := c.store.UpdateLastUsed(ctx, dbToken.ID, now); err != nil {
+		return auth.Claims{}, nil, fmt.Errorf("udpate: %w", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       dbToken.ID,
+			Subject:  dbToken.UID,
+			Issuer:   "wakt project",
+			IssuedAt: jwt.NewNumericDate(dbToken.DateCreated),
+		},
+	}
+	if dbToken.DateExpires != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*dbToken.DateExpires)
+	}
+
+	return claims, dbToken.Scopes, nil
+}
+
+// hashToken returns the hash of a token that is stored in its place.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
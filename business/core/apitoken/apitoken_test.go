@@ -0,0 +1,97 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestAPIToken(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testapitoken")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to work with personal API tokens.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a token that expires.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			expires := now.Add(24 * time.Hour)
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+			nt := NewToken{
+				Name:        "Invoicing",
+				Scopes:      []string{ScopeTimeEntriesRead, ScopeReportsRead},
+				DateExpires: &expires,
+			}
+
+			token, err := core.Create(ctx, userID, nt, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create token.", dbtest.Success, testID)
+
+			claims, scopes, err := core.Authenticate(ctx, token.Secret, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authenticate with the token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to authenticate with the token.", dbtest.Success, testID)
+
+			if claims.Subject != userID {
+				t.Fatalf("\t%s\tTest %d:\tShould get the claims of the user : %s.", dbtest.Failed, testID, claims.Subject)
+			}
+			if diff := cmp.Diff(nt.Scopes, scopes); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get back the scopes. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the claims and scopes of the token.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, token.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve token by ID : %s.", dbtest.Failed, testID, err)
+			}
+			if saved.DateLastUsed == nil || !saved.DateLastUsed.Equal(now) {
+				t.Fatalf("\t%s\tTest %d:\tShould record the use of the token : %v.", dbtest.Failed, testID, saved.DateLastUsed)
+			}
+			t.Logf("\t%s\tTest %d:\tShould record the use of the token.", dbtest.Success, testID)
+
+			if _, _, err := core.Authenticate(ctx, token.Secret, expires); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept an expired token : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept an expired token.", dbtest.Success, testID)
+
+			if err := core.Delete(ctx, token.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to revoke the token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to revoke the token.", dbtest.Success, testID)
+
+			if _, _, err := core.Authenticate(ctx, token.Secret, now); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept a revoked token : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept a revoked token.", dbtest.Success, testID)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package db contains personal API token related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for personal API token access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new personal API token into the database.
+func (s Store) Create(ctx context.Context, token Token) error {
+	const q = `
+	INSERT INTO api_tokens
+		(token_id, uid, name, token_hash, scopes, date_expires, date_last_used, date_created)
+	VALUES
+		(:token_id, :uid, :name, :token_hash, :scopes, :date_expires, :date_last_used, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, token); err != nil {
+		return fmt.Errorf("inserting api token: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a personal API token from the database.
+func (s Store) Delete(ctx context.Context, tokenID string) error {
+	data := struct {
+		TokenID string `db:"token_id"`
+	}{
+		TokenID: tokenID,
+	}
+
+	const q = `
+	DELETE FROM
+		api_tokens
+	WHERE
+		token_id = :token_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting api token tokenID[%s]: %w", tokenID, err)
+	}
+
+	return nil
+}
+
+// DeleteUserTokens removes every personal API token of a user from the
+// database.
+func (s Store) DeleteUserTokens(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		api_tokens
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting api tokens userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records the date a personal API token was last used.
+func (s Store) UpdateLastUsed(ctx context.Context, tokenID string, used time.Time) error {
+	data := struct {
+		TokenID      string    `db:"token_id"`
+		DateLastUsed time.Time `db:"date_last_used"`
+	}{
+		TokenID:      tokenID,
+		DateLastUsed: used,
+	}
+
+	const q = `
+	UPDATE
+		api_tokens
+	SET
+		date_last_used = :date_last_used
+	WHERE
+		token_id = :token_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("updating api token last used tokenID[%s]: %w", tokenID, err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified personal API token from the database.
+func (s Store) QueryByID(ctx context.Context, tokenID string) (Token, error) {
+	data := struct {
+		TokenID string `db:"token_id"`
+	}{
+		TokenID: tokenID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		api_tokens
+	WHERE
+		token_id = :token_id`
+
+	var token Token
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &token); err != nil {
+		return Token{}, fmt.Errorf("selecting api token tokenID[%q]: %w", tokenID, err)
+	}
+
+	return token, nil
+}
+
+// QueryByHash gets the personal API token with the hash from the database.
+func (s Store) QueryByHash(ctx context.Context, tokenHash string) (Token, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		api_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var token Token
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &token); err != nil {
+		return Token{}, fmt.Errorf("selecting api token: %w", err)
+	}
+
+	return token, nil
+}
+
+// QueryUserTokens retrieves the personal API tokens of a user from the
+// database.
+func (s Store) QueryUserTokens(ctx context.Context, userID string) ([]Token, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		api_tokens
+	WHERE
+		uid = :user_id
+	ORDER BY
+		date_created`
+
+	var tokens []Token
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &tokens); err != nil {
+		return nil, fmt.Errorf("selecting api tokens userID[%s]: %w", userID, err)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Token represent the structure we need for moving data
+// between the app and the database.
+type Token struct {
+	ID           string         `db:"token_id"`
+	UID          string         `db:"uid"`
+	Name         string         `db:"name"`
+	TokenHash    string         `db:"token_hash"`
+	Scopes       pq.StringArray `db:"scopes"`
+	DateExpires  *time.Time     `db:"date_expires"`
+	DateLastUsed *time.Time     `db:"date_last_used"`
+	DateCreated  time.Time      `db:"date_created"`
+}
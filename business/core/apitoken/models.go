@@ -0,0 +1,58 @@
+package apitoken
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/apitoken/db"
+)
+
+// Set of scopes a personal API token can be granted.
+const (
+	ScopeTimeEntriesRead  = "time_entries:read"
+	ScopeTimeEntriesWrite = "time_entries:write"
+	ScopeReportsRead      = "reports:read"
+)
+
+// Token represents a personal API token a user created for an integration.
+// The token itself is only known when it is created, just its hash is kept.
+type Token struct {
+	ID           string     `json:"id"`
+	UID          string     `json:"uid"`
+	Name         string     `json:"name"`
+	TokenHash    string     `json:"-"`
+	Scopes       []string   `json:"scopes"`
+	DateExpires  *time.Time `json:"date_expires"`
+	DateLastUsed *time.Time `json:"date_last_used"`
+	DateCreated  time.Time  `json:"date_created"`
+}
+
+// CreatedToken is a personal API token along with the token to authenticate
+// with, which is only returned once.
+type CreatedToken struct {
+	Token
+	Secret string `json:"token"`
+}
+
+// NewToken contains information needed to create a new personal API token.
+// The token never expires when no expiry date is given.
+type NewToken struct {
+	Name        string     `json:"name" validate:"required"`
+	Scopes      []string   `json:"scopes" validate:"required,min=1,unique,dive,oneof=time_entries:read time_entries:write reports:read"`
+	DateExpires *time.Time `json:"date_expires"`
+}
+
+// =============================================================================
+
+func toToken(dbToken db.Token) Token {
+	pu := (*Token)(unsafe.Pointer(&dbToken))
+	return *pu
+}
+
+func toTokenSlice(dbTokens []db.Token) []Token {
+	tokens := make([]Token, len(dbTokens))
+	for i, dbToken := range dbTokens {
+		tokens[i] = toToken(dbToken)
+	}
+	return tokens
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/AhmedShaef/wakt/business/core/client/db"
@@ -18,8 +19,10 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound  = errors.New("user not found")
-	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrNotFound        = errors.New("user not found")
+	ErrInvalidID       = errors.New("ID is not in its proper form")
+	ErrContactNotFound = errors.New("contact not found")
+	ErrInUse           = errors.New("client still has projects")
 )
 
 // Core manages the set of APIs for user access.
@@ -49,13 +52,18 @@ func (c Core) Create(ctx context.Context, nc NewClient, userID string, now time.
 	}
 
 	dbclint := db.Client{
-		ID:          validate.GenerateID(),
-		Name:        nc.Name,
-		UID:         userID,
-		WID:         nc.WID,
-		Notes:       nc.Notes,
-		DateCreated: now,
-		DateUpdated: now,
+		ID:             validate.GenerateID(),
+		Name:           nc.Name,
+		UID:            userID,
+		WID:            nc.WID,
+		Notes:          nc.Notes,
+		DateCreated:    now,
+		DateUpdated:    now,
+		BillingAddress: nc.BillingAddress,
+		TaxID:          nc.TaxID,
+		Currency:       strings.ToUpper(nc.Currency),
+		Rate:           nc.Rate,
+		PaymentTerms:   nc.PaymentTerms,
 	}
 
 	if err := c.store.Create(ctx, dbclint); err != nil {
@@ -89,6 +97,21 @@ func (c Core) Update(ctx context.Context, clientID string, uc UpdateClient, now
 	if uc.Notes != nil {
 		dbclient.Notes = *uc.Notes
 	}
+	if uc.BillingAddress != nil {
+		dbclient.BillingAddress = *uc.BillingAddress
+	}
+	if uc.TaxID != nil {
+		dbclient.TaxID = *uc.TaxID
+	}
+	if uc.Currency != nil {
+		dbclient.Currency = strings.ToUpper(*uc.Currency)
+	}
+	if uc.Rate != nil {
+		dbclient.Rate = *uc.Rate
+	}
+	if uc.PaymentTerms != nil {
+		dbclient.PaymentTerms = *uc.PaymentTerms
+	}
 	dbclient.DateUpdated = now
 
 	if err := c.store.Update(ctx, dbclient); err != nil {
@@ -98,6 +121,38 @@ func (c Core) Update(ctx context.Context, clientID string, uc UpdateClient, now
 	return nil
 }
 
+// Archive sets the archive state of a client and cascades it to the client
+// projects, so they are hidden from the lists but are kept in the reports.
+func (c Core) Archive(ctx context.Context, clientID string, archived bool, now time.Time) error {
+	if err := validate.CheckID(clientID); err != nil {
+		return ErrInvalidID
+	}
+
+	dbclient, err := c.store.QueryByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("archiving client clientID[%s]: %w", clientID, err)
+	}
+
+	dbclient.Archived = archived
+	dbclient.DateUpdated = now
+
+	tran := func(tx sqlx.ExtContext) error {
+		if err := c.store.Tran(tx).Archive(ctx, dbclient); err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
 // Delete removes a client from the database.
 func (c Core) Delete(ctx context.Context, clientID string) error {
 	if err := validate.CheckID(clientID); err != nil {
@@ -105,6 +160,9 @@ func (c Core) Delete(ctx context.Context, clientID string) error {
 	}
 
 	if err := c.store.Delete(ctx, clientID); err != nil {
+		if errors.Is(err, database.ErrDBReferenced) {
+			return ErrInUse
+		}
 		return fmt.Errorf("delete: %w", err)
 	}
 
@@ -152,3 +210,139 @@ func (c Core) QueryWorkspaceClients(ctx context.Context, workspaceID string, pag
 	}
 	return toClientsSlice(dbClient), nil
 }
+
+// CreateContact inserts a new contact of a client into the database. A new
+// primary contact replaces the previous one.
+func (c Core) CreateContact(ctx context.Context, clientID string, nc NewContact, now time.Time) (Contact, error) {
+	if err := validate.CheckID(clientID); err != nil {
+		return Contact{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nc); err != nil {
+		return Contact{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbcontact := db.Contact{
+		ID:          validate.GenerateID(),
+		CID:         clientID,
+		Name:        nc.Name,
+		Email:       nc.Email,
+		Phone:       nc.Phone,
+		Primary:     nc.Primary,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		if dbcontact.Primary {
+			if err := c.store.Tran(tx).ClearPrimaryContact(ctx, clientID); err != nil {
+				return fmt.Errorf("create: %w", err)
+			}
+		}
+		if err := c.store.Tran(tx).CreateContact(ctx, dbcontact); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return Contact{}, fmt.Errorf("tran: %w", err)
+	}
+
+	return toContact(dbcontact), nil
+}
+
+// UpdateContact replaces a client contact document in the database.
+func (c Core) UpdateContact(ctx context.Context, contactID string, uc UpdateContact, now time.Time) error {
+	if err := validate.CheckID(contactID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(uc); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbcontact, err := c.store.QueryContactByID(ctx, contactID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrContactNotFound
+		}
+		return fmt.Errorf("updating contact contactID[%s]: %w", contactID, err)
+	}
+
+	if uc.Name != nil {
+		dbcontact.Name = *uc.Name
+	}
+	if uc.Email != nil {
+		dbcontact.Email = *uc.Email
+	}
+	if uc.Phone != nil {
+		dbcontact.Phone = *uc.Phone
+	}
+	if uc.Primary != nil {
+		dbcontact.Primary = *uc.Primary
+	}
+	dbcontact.DateUpdated = now
+
+	tran := func(tx sqlx.ExtContext) error {
+		if dbcontact.Primary {
+			if err := c.store.Tran(tx).ClearPrimaryContact(ctx, dbcontact.CID); err != nil {
+				return fmt.Errorf("udpate: %w", err)
+			}
+		}
+		if err := c.store.Tran(tx).UpdateContact(ctx, dbcontact); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteContact removes a client contact from the database.
+func (c Core) DeleteContact(ctx context.Context, contactID string) error {
+	if err := validate.CheckID(contactID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.DeleteContact(ctx, contactID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryContactByID gets the specified client contact from the database.
+func (c Core) QueryContactByID(ctx context.Context, contactID string) (Contact, error) {
+	if err := validate.CheckID(contactID); err != nil {
+		return Contact{}, ErrInvalidID
+	}
+
+	dbcontact, err := c.store.QueryContactByID(ctx, contactID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Contact{}, ErrContactNotFound
+		}
+		return Contact{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toContact(dbcontact), nil
+}
+
+// QueryClientContacts retrieves the contacts of a client from the database.
+func (c Core) QueryClientContacts(ctx context.Context, clientID string) ([]Contact, error) {
+	if err := validate.CheckID(clientID); err != nil {
+		return []Contact{}, ErrInvalidID
+	}
+
+	dbcontacts, err := c.store.QueryClientContacts(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toContactsSlice(dbcontacts), nil
+}
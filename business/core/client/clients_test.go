@@ -175,3 +175,187 @@ func TestPagingClient(t *testing.T) {
 		}
 	}
 }
+
+func TestContact(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testcontact")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to work with client contact records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a single contact.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			nc := NewContact{
+				Name:    "Ahmed Shaef",
+				Email:   "ahmed@example.com",
+				Primary: true,
+			}
+
+			contact, err := core.CreateContact(ctx, "c78db68e-e004-44f5-895b-ba562dc53d9d", nc, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create contact : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create contact.", dbtest.Success, testID)
+
+			contacts, err := core.QueryClientContacts(ctx, "c78db68e-e004-44f5-895b-ba562dc53d9d")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve client contacts : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve client contacts.", dbtest.Success, testID)
+
+			if len(contacts) != 2 || contacts[0].ID != contact.ID || contacts[1].Primary {
+				t.Fatalf("\t%s\tTest %d:\tShould have the new contact as the only primary contact : %+v.", dbtest.Failed, testID, contacts)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have the new contact as the only primary contact.", dbtest.Success, testID)
+
+			upd := UpdateContact{
+				Email: dbtest.StringPointer("shehab@example.com"),
+			}
+
+			if err := core.UpdateContact(ctx, contact.ID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update contact : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to update contact.", dbtest.Success, testID)
+
+			saved, err := core.QueryContactByID(ctx, contact.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve contact by ID : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve contact by ID.", dbtest.Success, testID)
+
+			if saved.Email != *upd.Email {
+				t.Errorf("\t%s\tTest %d:\tShould be able to see updates to Email.", dbtest.Failed, testID)
+				t.Logf("\t\tTest %d:\tGot: %v", testID, saved.Email)
+				t.Logf("\t\tTest %d:\tExp: %v", testID, *upd.Email)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould be able to see updates to Email.", dbtest.Success, testID)
+			}
+
+			if err := core.DeleteContact(ctx, contact.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete contact : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to delete contact.", dbtest.Success, testID)
+
+			_, err = core.QueryContactByID(ctx, contact.ID)
+			if !errors.Is(err, ErrContactNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve contact : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to retrieve contact.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestArchiveClient(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testarchiveclient")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to archive clients.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen archiving a client with projects.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			clientID := "c78db68e-e004-44f5-895b-ba562dc53d9d"
+			const onHoldID = "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			const archivedID = "d774cc57-e4a6-4be2-bca1-cb50610fb3f5"
+
+			const status = `UPDATE projects SET status = $1, active = false WHERE project_id = $2`
+			if _, err := db.ExecContext(ctx, status, "on_hold", onHoldID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to put a project on hold : %s.", dbtest.Failed, testID, err)
+			}
+			if _, err := db.ExecContext(ctx, status, "archived", archivedID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to archive a project : %s.", dbtest.Failed, testID, err)
+			}
+
+			if err := core.Archive(ctx, clientID, true, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to archive client : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to archive client.", dbtest.Success, testID)
+
+			clients, err := core.QueryWorkspaceClients(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 1, 10)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve workspace clients : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve workspace clients.", dbtest.Success, testID)
+
+			for _, clnt := range clients {
+				if clnt.ID == clientID {
+					t.Fatalf("\t%s\tTest %d:\tShould hide the archived client.", dbtest.Failed, testID)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould hide the archived client.", dbtest.Success, testID)
+
+			var active int
+			if err := db.GetContext(ctx, &active, `SELECT count(*) FROM projects WHERE cid = $1 AND status <> 'archived'`, clientID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count client projects : %s.", dbtest.Failed, testID, err)
+			}
+			if active != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould archive the client projects : %d left.", dbtest.Failed, testID, active)
+			}
+			t.Logf("\t%s\tTest %d:\tShould archive the client projects.", dbtest.Success, testID)
+
+			if err := core.Archive(ctx, clientID, false, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to unarchive client : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to unarchive client.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, clientID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve client by ID: %s.", dbtest.Failed, testID, err)
+			}
+
+			if saved.Archived {
+				t.Fatalf("\t%s\tTest %d:\tShould see the client unarchived.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see the client unarchived.", dbtest.Success, testID)
+
+			statuses := make(map[string]string)
+			for _, projectID := range []string{onHoldID, archivedID} {
+				var got string
+				if err := db.GetContext(ctx, &got, `SELECT status FROM projects WHERE project_id = $1`, projectID); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to query the project status : %s.", dbtest.Failed, testID, err)
+				}
+				statuses[projectID] = got
+			}
+			if statuses[onHoldID] != "on_hold" || statuses[archivedID] != "archived" {
+				t.Fatalf("\t%s\tTest %d:\tShould restore the projects to their previous status : %v.", dbtest.Failed, testID, statuses)
+			}
+			t.Logf("\t%s\tTest %d:\tShould restore the projects to their previous status.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestDeleteReferencedClient(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testdeletereferencedclient")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to keep clients that still have projects.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen deleting a client with projects.", testID)
+		{
+			ctx := context.Background()
+			clientID := "c78db68e-e004-44f5-895b-ba562dc53d9d"
+
+			if err := core.Delete(ctx, clientID); !errors.Is(err, ErrInUse) {
+				t.Fatalf("\t%s\tTest %d:\tShould refuse to delete the client : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould refuse to delete the client.", dbtest.Success, testID)
+
+			if _, err := core.QueryByID(ctx, clientID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould still retrieve the client : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould still retrieve the client.", dbtest.Success, testID)
+		}
+	}
+}
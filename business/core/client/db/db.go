@@ -50,9 +50,9 @@ func (s Store) Tran(tx sqlx.ExtContext) Store {
 func (s Store) Create(ctx context.Context, client Client) error {
 	const q = `
 	INSERT INTO clients
-		(client_id, name, uid, wid, notes, date_created, date_updated)
+		(client_id, name, uid, wid, notes, date_created, date_updated, billing_address, tax_id, currency, rate, payment_terms, archived)
 	VALUES
-		(:client_id, :name, :uid, :wid, :notes, :date_created, :date_updated)`
+		(:client_id, :name, :uid, :wid, :notes, :date_created, :date_updated, :billing_address, :tax_id, :currency, :rate, :payment_terms, :archived)`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, client); err != nil {
 		return fmt.Errorf("inserting client: %w", err)
@@ -69,6 +69,11 @@ func (s Store) Update(ctx context.Context, client Client) error {
 	SET 
 		"name" = :name,
 		"notes" = :notes,
+		"billing_address" = :billing_address,
+		"tax_id" = :tax_id,
+		"currency" = :currency,
+		"rate" = :rate,
+		"payment_terms" = :payment_terms,
 		"date_updated" = :date_updated
 	WHERE
 		client_id = :client_id`
@@ -101,6 +106,58 @@ func (s Store) Delete(ctx context.Context, clientID string) error {
 	return nil
 }
 
+// Archive sets the archive state of a client and of its projects in the
+// database. Archiving records the status of every project it archives, and
+// unarchiving restores those projects to it. Projects that were archived on
+// their own stay archived.
+func (s Store) Archive(ctx context.Context, client Client) error {
+	const q = `
+	UPDATE
+		clients
+	SET
+		"archived" = :archived,
+		"date_updated" = :date_updated
+	WHERE
+		client_id = :client_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, client); err != nil {
+		return fmt.Errorf("archiving clientID[%s]: %w", client.ID, err)
+	}
+
+	const archive = `
+	UPDATE
+		projects
+	SET
+		"previous_status" = "status",
+		"status" = 'archived',
+		"active" = false,
+		"date_updated" = :date_updated
+	WHERE
+		cid = :client_id AND status <> 'archived'`
+
+	const unarchive = `
+	UPDATE
+		projects
+	SET
+		"status" = "previous_status",
+		"active" = "previous_status" = 'active',
+		"previous_status" = NULL,
+		"date_updated" = :date_updated
+	WHERE
+		cid = :client_id AND previous_status IS NOT NULL`
+
+	qp := unarchive
+	if client.Archived {
+		qp = archive
+	}
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, qp, client); err != nil {
+		return fmt.Errorf("archiving projects of clientID[%s]: %w", client.ID, err)
+	}
+
+	return nil
+}
+
 // List retrieves a list of existing client from the database.
 func (s Store) List(ctx context.Context, userID string, pageNumber int, rowsPerPage int) ([]Client, error) {
 	data := struct {
@@ -119,7 +176,7 @@ func (s Store) List(ctx context.Context, userID string, pageNumber int, rowsPerP
 	FROM
 		clients
 	WHERE
-		uid = :user_id
+		uid = :user_id AND archived = false
 	ORDER BY
 		client_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -202,7 +259,7 @@ func (s Store) QueryWorkspaceClients(ctx context.Context, workspaceID string, pa
 	FROM
 		clients
 	WHERE
-		wid = :workspace_id
+		wid = :workspace_id AND archived = false
 	ORDER BY
 		client_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -214,3 +271,134 @@ func (s Store) QueryWorkspaceClients(ctx context.Context, workspaceID string, pa
 
 	return clients, nil
 }
+
+// CreateContact inserts a new client contact into the database.
+func (s Store) CreateContact(ctx context.Context, contact Contact) error {
+	const q = `
+	INSERT INTO client_contacts
+		(client_contact_id, cid, name, email, phone, is_primary, date_created, date_updated)
+	VALUES
+		(:client_contact_id, :cid, :name, :email, :phone, :is_primary, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, contact); err != nil {
+		return fmt.Errorf("inserting contact: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateContact replaces a client contact document in the database.
+func (s Store) UpdateContact(ctx context.Context, contact Contact) error {
+	const q = `
+	UPDATE
+		client_contacts
+	SET
+		"name" = :name,
+		"email" = :email,
+		"phone" = :phone,
+		"is_primary" = :is_primary,
+		"date_updated" = :date_updated
+	WHERE
+		client_contact_id = :client_contact_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, contact); err != nil {
+		return fmt.Errorf("updating contactID[%s]: %w", contact.ID, err)
+	}
+
+	return nil
+}
+
+// ClearPrimaryContact unsets the primary flag of every contact of a client in
+// the database.
+func (s Store) ClearPrimaryContact(ctx context.Context, clientID string) error {
+	data := struct {
+		ClientID string `db:"client_id"`
+	}{
+		ClientID: clientID,
+	}
+
+	const q = `
+	UPDATE
+		client_contacts
+	SET
+		"is_primary" = false
+	WHERE
+		cid = :client_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("clearing primary contact clientID[%s]: %w", clientID, err)
+	}
+
+	return nil
+}
+
+// DeleteContact removes a client contact from the database.
+func (s Store) DeleteContact(ctx context.Context, contactID string) error {
+	data := struct {
+		ContactID string `db:"client_contact_id"`
+	}{
+		ContactID: contactID,
+	}
+
+	const q = `
+	DELETE FROM
+		client_contacts
+	WHERE
+		client_contact_id = :client_contact_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting contactID[%s]: %w", contactID, err)
+	}
+
+	return nil
+}
+
+// QueryContactByID gets the specified client contact from the database.
+func (s Store) QueryContactByID(ctx context.Context, contactID string) (Contact, error) {
+	data := struct {
+		ContactID string `db:"client_contact_id"`
+	}{
+		ContactID: contactID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		client_contacts
+	WHERE
+		client_contact_id = :client_contact_id`
+
+	var contact Contact
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &contact); err != nil {
+		return Contact{}, fmt.Errorf("selecting contactID[%q]: %w", contactID, err)
+	}
+
+	return contact, nil
+}
+
+// QueryClientContacts retrieves the contacts of a client from the database.
+func (s Store) QueryClientContacts(ctx context.Context, clientID string) ([]Contact, error) {
+	data := struct {
+		ClientID string `db:"client_id"`
+	}{
+		ClientID: clientID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		client_contacts
+	WHERE
+		cid = :client_id
+	ORDER BY
+		is_primary DESC, name`
+
+	var contacts []Contact
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &contacts); err != nil {
+		return nil, fmt.Errorf("selecting contacts: %w", err)
+	}
+
+	return contacts, nil
+}
@@ -7,11 +7,30 @@ import (
 // Client represent the structure we need for moving data
 // between the app and the database.
 type Client struct {
-	ID          string    `db:"client_id"`
+	ID             string    `db:"client_id"`
+	Name           string    `db:"name"`
+	UID            string    `db:"uid"`
+	WID            string    `db:"wid"`
+	Notes          string    `db:"notes"`
+	DateCreated    time.Time `db:"date_created"`
+	DateUpdated    time.Time `db:"date_updated"`
+	BillingAddress string    `db:"billing_address"`
+	TaxID          string    `db:"tax_id"`
+	Currency       string    `db:"currency"`
+	Rate           float64   `db:"rate"`
+	PaymentTerms   int       `db:"payment_terms"`
+	Archived       bool      `db:"archived"`
+}
+
+// Contact represent the structure we need for moving data
+// between the app and the database.
+type Contact struct {
+	ID          string    `db:"client_contact_id"`
+	CID         string    `db:"cid"`
 	Name        string    `db:"name"`
-	UID         string    `db:"uid"`
-	WID         string    `db:"wid"`
-	Notes       string    `db:"notes"`
+	Email       string    `db:"email"`
+	Phone       string    `db:"phone"`
+	Primary     bool      `db:"is_primary"`
 	DateCreated time.Time `db:"date_created"`
 	DateUpdated time.Time `db:"date_updated"`
 }
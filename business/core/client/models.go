@@ -7,22 +7,35 @@ import (
 	"github.com/AhmedShaef/wakt/business/core/client/db"
 )
 
-// Client represents an individual client.
+// Client represents an individual client. PaymentTerms is the number of days
+// the client has to pay an invoice. Archived clients are hidden from the client
+// lists but are kept in the reports.
 type Client struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	UID         string    `json:"uid"`
-	WID         string    `json:"wid"`
-	Notes       string    `json:"notes"`
-	DateCreated time.Time `json:"date_created"`
-	DateUpdated time.Time `json:"date_updated"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	UID            string    `json:"uid"`
+	WID            string    `json:"wid"`
+	Notes          string    `json:"notes"`
+	DateCreated    time.Time `json:"date_created"`
+	DateUpdated    time.Time `json:"date_updated"`
+	BillingAddress string    `json:"billing_address"`
+	TaxID          string    `json:"tax_id"`
+	Currency       string    `json:"currency"`
+	Rate           float64   `json:"rate"`
+	PaymentTerms   int       `json:"payment_terms"`
+	Archived       bool      `json:"archived"`
 }
 
 // NewClient contains information needed to create a new client.
 type NewClient struct {
-	Name  string `json:"name" validate:"required"`
-	WID   string `json:"wid"`
-	Notes string `json:"notes"`
+	Name           string  `json:"name" validate:"required"`
+	WID            string  `json:"wid"`
+	Notes          string  `json:"notes"`
+	BillingAddress string  `json:"billing_address"`
+	TaxID          string  `json:"tax_id"`
+	Currency       string  `json:"currency" validate:"omitempty,len=3"`
+	Rate           float64 `json:"rate" validate:"gte=0"`
+	PaymentTerms   int     `json:"payment_terms" validate:"gte=0"`
 }
 
 // UpdateClient defines what information may be provided to modify an existing
@@ -32,8 +45,46 @@ type NewClient struct {
 // we do not want to use pointers to basic types ,but we make exceptions around
 // marshalling/unmarshalling.
 type UpdateClient struct {
-	Name  *string `json:"name"`
-	Notes *string `json:"notes"`
+	Name           *string  `json:"name"`
+	Notes          *string  `json:"notes"`
+	BillingAddress *string  `json:"billing_address"`
+	TaxID          *string  `json:"tax_id"`
+	Currency       *string  `json:"currency" validate:"omitempty,len=3"`
+	Rate           *float64 `json:"rate" validate:"omitempty,gte=0"`
+	PaymentTerms   *int     `json:"payment_terms" validate:"omitempty,gte=0"`
+}
+
+// Contact represents a person to reach at a client.
+type Contact struct {
+	ID          string    `json:"id"`
+	CID         string    `json:"cid"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	Primary     bool      `json:"primary"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+// NewContact contains information needed to create a new contact.
+type NewContact struct {
+	Name    string `json:"name" validate:"required"`
+	Email   string `json:"email" validate:"required,email"`
+	Phone   string `json:"phone"`
+	Primary bool   `json:"primary"`
+}
+
+// UpdateContact defines what information may be provided to modify an existing
+// contact. All fields are optional so clients can send just the fields they want
+// changed. It uses pointer fields ,so we can differentiate between a field that
+// was not provided and a field that was provided as explicitly blank. Normally
+// we do not want to use pointers to basic types ,but we make exceptions around
+// marshalling/unmarshalling.
+type UpdateContact struct {
+	Name    *string `json:"name"`
+	Email   *string `json:"email" validate:"omitempty,email"`
+	Phone   *string `json:"phone"`
+	Primary *bool   `json:"primary"`
 }
 
 // =============================================================================
@@ -50,3 +101,16 @@ func toClientsSlice(dbClient []db.Client) []Client {
 	}
 	return clients
 }
+
+func toContact(dbContact db.Contact) Contact {
+	pc := (*Contact)(unsafe.Pointer(&dbContact))
+	return *pc
+}
+
+func toContactsSlice(dbContacts []db.Contact) []Contact {
+	contacts := make([]Contact, len(dbContacts))
+	for i, dbContact := range dbContacts {
+		contacts[i] = toContact(dbContact)
+	}
+	return contacts
+}
@@ -0,0 +1,143 @@
+// Package costrate provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package costrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/costrate/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound  = errors.New("cost rate not found")
+	ErrInvalidID = errors.New("ID is not in its proper form")
+)
+
+// Core manages the set of APIs for cost rate access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for cost rate api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// Create inserts a new cost rate into the database.
+func (c Core) Create(ctx context.Context, ncr NewCostRate, now time.Time) (CostRate, error) {
+	if err := validate.Check(ncr); err != nil {
+		return CostRate{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	if err := validate.CheckID(ncr.WID); err != nil {
+		return CostRate{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(ncr.UID); err != nil {
+		return CostRate{}, ErrInvalidID
+	}
+
+	dbCostRate := db.CostRate{
+		ID:            validate.GenerateID(),
+		WID:           ncr.WID,
+		UID:           ncr.UID,
+		Rate:          ncr.Rate,
+		EffectiveDate: ncr.EffectiveDate,
+		DateCreated:   now,
+		DateUpdated:   now,
+	}
+
+	if err := c.store.Create(ctx, dbCostRate); err != nil {
+		return CostRate{}, fmt.Errorf("create: %w", err)
+	}
+
+	return toCostRate(dbCostRate), nil
+}
+
+// Update replaces a cost rate document in the database.
+func (c Core) Update(ctx context.Context, costRateID string, ucr UpdateCostRate, now time.Time) error {
+	if err := validate.CheckID(costRateID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(ucr); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbCostRate, err := c.store.QueryByID(ctx, costRateID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating cost rate costRateID[%s]: %w", costRateID, err)
+	}
+
+	if ucr.Rate != nil {
+		dbCostRate.Rate = *ucr.Rate
+	}
+	if ucr.EffectiveDate != nil {
+		dbCostRate.EffectiveDate = *ucr.EffectiveDate
+	}
+	dbCostRate.DateUpdated = now
+
+	if err := c.store.Update(ctx, dbCostRate); err != nil {
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a cost rate from the database.
+func (c Core) Delete(ctx context.Context, costRateID string) error {
+	if err := validate.CheckID(costRateID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.Delete(ctx, costRateID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified cost rate from the database.
+func (c Core) QueryByID(ctx context.Context, costRateID string) (CostRate, error) {
+	if err := validate.CheckID(costRateID); err != nil {
+		return CostRate{}, ErrInvalidID
+	}
+
+	dbCostRate, err := c.store.QueryByID(ctx, costRateID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return CostRate{}, ErrNotFound
+		}
+		return CostRate{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toCostRate(dbCostRate), nil
+}
+
+// QueryWorkspaceCostRates retrieves a list of existing cost rates from the database.
+func (c Core) QueryWorkspaceCostRates(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]CostRate, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []CostRate{}, ErrInvalidID
+	}
+
+	dbCostRates, err := c.store.QueryWorkspaceCostRates(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toCostRateSlice(dbCostRates), nil
+}
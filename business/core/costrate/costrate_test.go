@@ -0,0 +1,153 @@
+package costrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbschema"
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestCostRate(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testcostrate")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to work with CostRate records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a single CostRate.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			ncr := NewCostRate{
+				WID:           "7da3ca14-6366-47cf-b953-f706226567d8",
+				UID:           "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+				Rate:          25,
+				EffectiveDate: time.Date(2021, time.September, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			costRate, err := core.Create(ctx, ncr, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create CostRate.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, costRate.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve CostRate by ID: %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve CostRate by ID.", dbtest.Success, testID)
+
+			if diff := cmp.Diff(costRate, saved); diff != "" {
+				t.Errorf("\t%s\tTest %d:\tShould get back the same CostRate. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get back the same CostRate.", dbtest.Success, testID)
+
+			upd := UpdateCostRate{
+				Rate: dbtest.Float64Pointer(30),
+			}
+
+			if err := core.Update(ctx, costRate.ID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to update CostRate.", dbtest.Success, testID)
+
+			saved, err = core.QueryByID(ctx, costRate.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve updated CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve updated CostRate.", dbtest.Success, testID)
+
+			if saved.Rate != *upd.Rate {
+				t.Errorf("\t%s\tTest %d:\tShould be able to see updates to Rate.", dbtest.Failed, testID)
+				t.Logf("\t\tTest %d:\tGot: %v", testID, saved.Rate)
+				t.Logf("\t\tTest %d:\tExp: %v", testID, *upd.Rate)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould be able to see updates to Rate.", dbtest.Success, testID)
+			}
+
+			if err := core.Delete(ctx, costRate.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to delete CostRate.", dbtest.Success, testID)
+
+			_, err = core.QueryByID(ctx, costRate.ID)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to retrieve CostRate.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestPagingCostRate(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testpaging")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to page through CostRate records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen paging through 2 CostRates.", testID)
+		{
+			ctx := context.Background()
+
+			costRates1, err := core.QueryWorkspaceCostRates(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 1, 1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve CostRates for page 1 : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve CostRates for page 1.", dbtest.Success, testID)
+
+			if len(costRates1) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single CostRate.", dbtest.Success, testID)
+
+			costRates2, err := core.QueryWorkspaceCostRates(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 2, 1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve CostRates for page 2 : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve CostRates for page 2.", dbtest.Success, testID)
+
+			if len(costRates2) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single CostRate : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single CostRate.", dbtest.Success, testID)
+
+			if costRates1[0].ID == costRates2[0].ID {
+				t.Logf("\t\tTest %d:\tCostRate1: %v", testID, costRates1[0].ID)
+				t.Logf("\t\tTest %d:\tCostRate2: %v", testID, costRates2[0].ID)
+				t.Fatalf("\t%s\tTest %d:\tShould have different CostRates : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have different CostRates.", dbtest.Success, testID)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package db contains cost rate related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for cost rate access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new cost rate into the database.
+func (s Store) Create(ctx context.Context, costRate CostRate) error {
+	const q = `
+	INSERT INTO cost_rates
+		(cost_rate_id, wid, uid, rate, effective_date, date_created, date_updated)
+	VALUES
+		(:cost_rate_id, :wid, :uid, :rate, :effective_date, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, costRate); err != nil {
+		return fmt.Errorf("inserting cost rate: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a cost rate document in the database.
+func (s Store) Update(ctx context.Context, costRate CostRate) error {
+	const q = `
+	UPDATE
+		cost_rates
+	SET
+		"rate" = :rate,
+		"effective_date" = :effective_date,
+		"date_updated" = :date_updated
+	WHERE
+		cost_rate_id = :cost_rate_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, costRate); err != nil {
+		return fmt.Errorf("updating costRateID[%s]: %w", costRate.ID, err)
+	}
+
+	return nil
+}
+
+// Delete removes a cost rate from the database.
+func (s Store) Delete(ctx context.Context, costRateID string) error {
+	data := struct {
+		CostRateID string `db:"cost_rate_id"`
+	}{
+		CostRateID: costRateID,
+	}
+
+	const q = `
+	DELETE FROM
+		cost_rates
+	WHERE
+		cost_rate_id = :cost_rate_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting costRateID[%s]: %w", costRateID, err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified cost rate from the database.
+func (s Store) QueryByID(ctx context.Context, costRateID string) (CostRate, error) {
+	data := struct {
+		CostRateID string `db:"cost_rate_id"`
+	}{
+		CostRateID: costRateID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		cost_rates
+	WHERE
+		cost_rate_id = :cost_rate_id`
+
+	var costRate CostRate
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &costRate); err != nil {
+		return CostRate{}, fmt.Errorf("selecting costRateID[%q]: %w", costRateID, err)
+	}
+
+	return costRate, nil
+}
+
+// QueryWorkspaceCostRates retrieves a list of existing cost rates from the database.
+func (s Store) QueryWorkspaceCostRates(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]CostRate, error) {
+	data := struct {
+		Offset      int    `db:"offset"`
+		RowsPerPage int    `db:"rows_per_page"`
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+		WorkspaceID: workspaceID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		cost_rates
+	WHERE
+		wid = :workspace_id
+	ORDER BY
+		uid, effective_date DESC
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var costRates []CostRate
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &costRates); err != nil {
+		return nil, fmt.Errorf("selecting cost rates: %w", err)
+	}
+
+	return costRates, nil
+}
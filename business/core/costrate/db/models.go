@@ -0,0 +1,15 @@
+package db
+
+import "time"
+
+// CostRate represent the structure we need for moving data
+// between the app and the database.
+type CostRate struct {
+	ID            string    `db:"cost_rate_id"`
+	WID           string    `db:"wid"`
+	UID           string    `db:"uid"`
+	Rate          float64   `db:"rate"`
+	EffectiveDate time.Time `db:"effective_date"`
+	DateCreated   time.Time `db:"date_created"`
+	DateUpdated   time.Time `db:"date_updated"`
+}
@@ -0,0 +1,54 @@
+package costrate
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/costrate/db"
+)
+
+// CostRate represents the internal hourly cost of a workspace member
+// starting from its effective date.
+type CostRate struct {
+	ID            string    `json:"id"`
+	WID           string    `json:"wid"`
+	UID           string    `json:"uid"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+	DateCreated   time.Time `json:"date_created"`
+	DateUpdated   time.Time `json:"date_updated"`
+}
+
+// NewCostRate contains information needed to create a new cost rate.
+type NewCostRate struct {
+	WID           string    `json:"wid" validate:"required"`
+	UID           string    `json:"uid" validate:"required"`
+	Rate          float64   `json:"rate" validate:"gte=0"`
+	EffectiveDate time.Time `json:"effective_date" validate:"required"`
+}
+
+// UpdateCostRate defines what information may be provided to modify an existing
+// cost rate. All fields are optional so cost rates can send just the fields they want
+// changed. It uses pointer fields ,so we can differentiate between a field that
+// was not provided and a field that was provided as explicitly blank. Normally
+// we do not want to use pointers to basic types ,but we make exceptions around
+// marshalling/unmarshalling.
+type UpdateCostRate struct {
+	Rate          *float64   `json:"rate" validate:"omitempty,gte=0"`
+	EffectiveDate *time.Time `json:"effective_date"`
+}
+
+// =============================================================================
+
+func toCostRate(dbCostRate db.CostRate) CostRate {
+	pu := (*CostRate)(unsafe.Pointer(&dbCostRate))
+	return *pu
+}
+
+func toCostRateSlice(dbCostRates []db.CostRate) []CostRate {
+	costRates := make([]CostRate, len(dbCostRates))
+	for i, dbCostRate := range dbCostRates {
+		costRates[i] = toCostRate(dbCostRate)
+	}
+	return costRates
+}
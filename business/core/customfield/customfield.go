@@ -0,0 +1,415 @@
+// Package customfield provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package customfield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/customfield/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound   = errors.New("custom field not found")
+	ErrInvalidID  = errors.New("ID is not in its proper form")
+	ErrNameTaken  = errors.New("custom field name already exists")
+	ErrNoOptions  = errors.New("select fields need options")
+	ErrInvalidKey = errors.New("entity type is not supported")
+)
+
+// Core manages the set of APIs for custom field access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for custom field api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// Create defines a new custom field of a workspace in the database.
+func (c Core) Create(ctx context.Context, workspaceID string, nf NewField, now time.Time) (Field, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Field{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nf); err != nil {
+		return Field{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbField := db.Field{
+		ID:          validate.GenerateID(),
+		WID:         workspaceID,
+		Entity:      nf.Entity,
+		Name:        nf.Name,
+		Kind:        nf.Kind,
+		Options:     nf.Options,
+		Required:    nf.Required,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if dbField.Options == nil {
+		dbField.Options = []string{}
+	}
+
+	if isSelect(dbField.Kind) && len(dbField.Options) == 0 {
+		return Field{}, ErrNoOptions
+	}
+
+	if err := c.store.Create(ctx, dbField); err != nil {
+		if errors.Is(err, database.ErrDBDuplicatedEntry) {
+			return Field{}, ErrNameTaken
+		}
+		return Field{}, fmt.Errorf("create: %w", err)
+	}
+
+	return toField(dbField), nil
+}
+
+// Update modifies the name, options or required flag of a custom field.
+func (c Core) Update(ctx context.Context, fieldID string, uf UpdateField, now time.Time) error {
+	if err := validate.CheckID(fieldID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(uf); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbField, err := c.store.QueryByID(ctx, fieldID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if uf.Name != nil {
+		dbField.Name = *uf.Name
+	}
+	if uf.Options != nil {
+		if !isSelect(dbField.Kind) {
+			return ErrNoOptions
+		}
+		dbField.Options = uf.Options
+	}
+	if uf.Required != nil {
+		dbField.Required = *uf.Required
+	}
+	dbField.DateUpdated = now
+
+	if err := c.store.Update(ctx, dbField); err != nil {
+		if errors.Is(err, database.ErrDBDuplicatedEntry) {
+			return ErrNameTaken
+		}
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a custom field with all its values from the database.
+func (c Core) Delete(ctx context.Context, fieldID string) error {
+	if err := validate.CheckID(fieldID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.Delete(ctx, fieldID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified custom field from the database.
+func (c Core) QueryByID(ctx context.Context, fieldID string) (Field, error) {
+	if err := validate.CheckID(fieldID); err != nil {
+		return Field{}, ErrInvalidID
+	}
+
+	dbField, err := c.store.QueryByID(ctx, fieldID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Field{}, ErrNotFound
+		}
+		return Field{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toField(dbField), nil
+}
+
+// QueryWorkspaceFields retrieves the custom fields of a workspace, only the
+// ones of the entity type when one is given.
+func (c Core) QueryWorkspaceFields(ctx context.Context, workspaceID, entity string) ([]Field, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []Field{}, ErrInvalidID
+	}
+
+	if entity != "" && !isEntity(entity) {
+		return []Field{}, ErrInvalidKey
+	}
+
+	dbFields, err := c.store.QueryWorkspaceFields(ctx, workspaceID, entity)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toFieldSlice(dbFields), nil
+}
+
+// Check validates the custom field values of a new entity of a workspace
+// before it is created. Required fields must have a value when complete is
+// set.
+func (c Core) Check(ctx context.Context, workspaceID, entity string, values map[string][]string, complete bool) error {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return ErrInvalidID
+	}
+
+	if !isEntity(entity) {
+		return ErrInvalidKey
+	}
+
+	dbFields, err := c.store.QueryWorkspaceFields(ctx, workspaceID, entity)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if err := c.check(ctx, workspaceID, dbFields, values, values, complete); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	return nil
+}
+
+// SetValues sets the custom field values of an entity of a workspace and
+// returns all the values the entity has. Required fields must keep a value
+// when complete is set.
+func (c Core) SetValues(ctx context.Context, entity, entityID, workspaceID string, sv SetValues, complete bool, now time.Time) ([]Value, error) {
+	if err := validate.CheckID(entityID); err != nil {
+		return []Value{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []Value{}, ErrInvalidID
+	}
+
+	if !isEntity(entity) {
+		return []Value{}, ErrInvalidKey
+	}
+
+	if err := validate.Check(sv); err != nil {
+		return []Value{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbFields, err := c.store.QueryWorkspaceFields(ctx, workspaceID, entity)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	dbValues, err := c.store.QueryValues(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	merged := make(map[string][]string, len(dbValues)+len(sv.Values))
+	for _, dbValue := range dbValues {
+		merged[dbValue.FieldID] = dbValue.Value
+	}
+	for fieldID, values := range sv.Values {
+		merged[fieldID] = values
+	}
+
+	if err := c.check(ctx, workspaceID, dbFields, sv.Values, merged, complete); err != nil {
+		return nil, fmt.Errorf("validating data: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		for fieldID, values := range sv.Values {
+			if len(values) == 0 {
+				if err := store.DeleteValue(ctx, fieldID, entityID); err != nil {
+					return fmt.Errorf("delete: %w", err)
+				}
+				continue
+			}
+
+			dbValue := db.Value{
+				FieldID:     fieldID,
+				EntityID:    entityID,
+				Value:       values,
+				DateUpdated: now,
+			}
+			if err := store.SetValue(ctx, dbValue); err != nil {
+				return fmt.Errorf("set: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return nil, fmt.Errorf("tran: %w", err)
+	}
+
+	return c.QueryValues(ctx, entityID)
+}
+
+// QueryValues retrieves the custom field values of an entity.
+func (c Core) QueryValues(ctx context.Context, entityID string) ([]Value, error) {
+	if err := validate.CheckID(entityID); err != nil {
+		return []Value{}, ErrInvalidID
+	}
+
+	dbValues, err := c.store.QueryValues(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toValueSlice(dbValues), nil
+}
+
+// check validates the values set on an entity against the fields of its
+// entity type. Merged holds every value the entity has once they are set,
+// which required fields are checked against when complete is set.
+func (c Core) check(ctx context.Context, workspaceID string, dbFields []db.Field, values, merged map[string][]string, complete bool) error {
+	byID := make(map[string]db.Field, len(dbFields))
+	for _, dbField := range dbFields {
+		byID[dbField.ID] = dbField
+	}
+
+	// Validate the fields in a stable order so the errors are too.
+	fieldIDs := make([]string, 0, len(values))
+	for fieldID := range values {
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	sort.Strings(fieldIDs)
+
+	var fields validate.FieldErrors
+	for _, fieldID := range fieldIDs {
+		dbField, exists := byID[fieldID]
+		if !exists {
+			fields = append(fields, validate.FieldError{
+				Field: fieldID,
+				Error: fieldID + " is not a custom field of this entity",
+			})
+			continue
+		}
+
+		fieldErrors, err := c.checkValue(ctx, workspaceID, dbField, values[fieldID])
+		if err != nil {
+			return err
+		}
+		fields = append(fields, fieldErrors...)
+	}
+
+	if complete {
+		for _, dbField := range dbFields {
+			if dbField.Required && len(merged[dbField.ID]) == 0 {
+				fields = append(fields, validate.FieldError{
+					Field: dbField.Name,
+					Error: dbField.Name + " is a required field",
+				})
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return fields
+	}
+
+	return nil
+}
+
+// checkValue validates the value of a custom field against its kind.
+func (c Core) checkValue(ctx context.Context, workspaceID string, dbField db.Field, values []string) (validate.FieldErrors, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	if dbField.Kind != KindMultiSelect && len(values) > 1 {
+		return validate.FieldErrors{{
+			Field: dbField.Name,
+			Error: dbField.Name + " takes a single value",
+		}}, nil
+	}
+
+	tags := map[string]string{
+		KindText:   "required",
+		KindNumber: "required,numeric",
+		KindDate:   "required,datetime=2006-01-02",
+		KindUser:   "required,uuid",
+	}
+
+	var fields validate.FieldErrors
+	if err := validate.CheckVar(dbField.Name, values, "unique"); err != nil {
+		fields = append(fields, validate.GetFieldErrors(err)...)
+	}
+
+	for _, value := range values {
+		if isSelect(dbField.Kind) {
+			if !contains(dbField.Options, value) {
+				fields = append(fields, validate.FieldError{
+					Field: dbField.Name,
+					Error: fmt.Sprintf("%s must be one of [%s]", dbField.Name, strings.Join(dbField.Options, " ")),
+				})
+			}
+			continue
+		}
+
+		if err := validate.CheckVar(dbField.Name, value, tags[dbField.Kind]); err != nil {
+			fields = append(fields, validate.GetFieldErrors(err)...)
+			continue
+		}
+
+		if dbField.Kind == KindUser {
+			member, err := c.store.QueryWorkspaceMember(ctx, workspaceID, value)
+			if err != nil {
+				return nil, fmt.Errorf("query member: %w", err)
+			}
+			if !member {
+				fields = append(fields, validate.FieldError{
+					Field: dbField.Name,
+					Error: dbField.Name + " must be a member of the workspace",
+				})
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// isEntity reports whether custom fields can be defined for the entity type.
+func isEntity(entity string) bool {
+	switch entity {
+	case EntityProject, EntityTask, EntityTimeEntry:
+		return true
+	}
+	return false
+}
+
+// isSelect reports whether the values of a field kind come from its options.
+func isSelect(kind string) bool {
+	return kind == KindSelect || kind == KindMultiSelect
+}
+
+// contains reports whether the value is one of the options.
+func contains(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package customfield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestCustomField(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testcustomfield")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to work with custom fields on projects.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a required select field.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+			const projectID = "45cf87a3-5915-4079-a9af-6c559239ddbf"
+
+			nf := NewField{
+				Entity:   EntityProject,
+				Name:     "Department",
+				Kind:     KindSelect,
+				Options:  []string{"Sales", "Support"},
+				Required: true,
+			}
+
+			field, err := core.Create(ctx, workspaceID, nf, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create field : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create field.", dbtest.Success, testID)
+
+			if _, err := core.Create(ctx, workspaceID, nf, now); !errors.Is(err, ErrNameTaken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to create the field twice : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to create the field twice.", dbtest.Success, testID)
+
+			sv := SetValues{Values: map[string][]string{field.ID: {"Marketing"}}}
+			if _, err := core.SetValues(ctx, EntityProject, projectID, workspaceID, sv, true, now); !validate.IsFieldErrors(err) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to set a value outside the options : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to set a value outside the options.", dbtest.Success, testID)
+
+			if err := core.Check(ctx, workspaceID, EntityProject, nil, true); !validate.IsFieldErrors(err) {
+				t.Fatalf("\t%s\tTest %d:\tShould require the field : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould require the field.", dbtest.Success, testID)
+
+			sv = SetValues{Values: map[string][]string{field.ID: {"Sales"}}}
+			values, err := core.SetValues(ctx, EntityProject, projectID, workspaceID, sv, true, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to set the value : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to set the value.", dbtest.Success, testID)
+
+			exp := []Value{{FieldID: field.ID, Name: "Department", Kind: KindSelect, Values: []string{"Sales"}}}
+			if diff := cmp.Diff(exp, values); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get back the value. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get back the value.", dbtest.Success, testID)
+
+			sv = SetValues{Values: map[string][]string{field.ID: {}}}
+			if _, err := core.SetValues(ctx, EntityProject, projectID, workspaceID, sv, true, now); !validate.IsFieldErrors(err) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to clear a required field : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to clear a required field.", dbtest.Success, testID)
+		}
+	}
+}
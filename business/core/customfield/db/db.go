@@ -0,0 +1,255 @@
+// Package db contains custom field related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for custom field access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new custom field into the database.
+func (s Store) Create(ctx context.Context, field Field) error {
+	const q = `
+	INSERT INTO custom_fields
+		(field_id, wid, entity, name, kind, options, required, date_created, date_updated)
+	VALUES
+		(:field_id, :wid, :entity, :name, :kind, :options, :required, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, field); err != nil {
+		return fmt.Errorf("inserting custom field: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a custom field document in the database.
+func (s Store) Update(ctx context.Context, field Field) error {
+	const q = `
+	UPDATE
+		custom_fields
+	SET
+		"name" = :name,
+		"options" = :options,
+		"required" = :required,
+		"date_updated" = :date_updated
+	WHERE
+		field_id = :field_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, field); err != nil {
+		return fmt.Errorf("updating fieldID[%s]: %w", field.ID, err)
+	}
+
+	return nil
+}
+
+// Delete removes a custom field with its values from the database.
+func (s Store) Delete(ctx context.Context, fieldID string) error {
+	data := struct {
+		FieldID string `db:"field_id"`
+	}{
+		FieldID: fieldID,
+	}
+
+	const q = `
+	DELETE FROM
+		custom_fields
+	WHERE
+		field_id = :field_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting fieldID[%s]: %w", fieldID, err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified custom field from the database.
+func (s Store) QueryByID(ctx context.Context, fieldID string) (Field, error) {
+	data := struct {
+		FieldID string `db:"field_id"`
+	}{
+		FieldID: fieldID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		custom_fields
+	WHERE
+		field_id = :field_id`
+
+	var field Field
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &field); err != nil {
+		return Field{}, fmt.Errorf("selecting fieldID[%q]: %w", fieldID, err)
+	}
+
+	return field, nil
+}
+
+// QueryWorkspaceFields retrieves the custom fields of a workspace from the
+// database, only the ones of the entity type when one is given.
+func (s Store) QueryWorkspaceFields(ctx context.Context, workspaceID, entity string) ([]Field, error) {
+	data := struct {
+		WorkspaceID string `db:"workspace_id"`
+		Entity      string `db:"entity"`
+	}{
+		WorkspaceID: workspaceID,
+		Entity:      entity,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		custom_fields
+	WHERE
+		wid = :workspace_id AND (:entity = '' OR entity = :entity)
+	ORDER BY
+		entity, name`
+
+	var fields []Field
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &fields); err != nil {
+		return nil, fmt.Errorf("selecting custom fields workspaceID[%q]: %w", workspaceID, err)
+	}
+
+	return fields, nil
+}
+
+// SetValue stores the value of a custom field for an entity, replacing the
+// one it had.
+func (s Store) SetValue(ctx context.Context, value Value) error {
+	const q = `
+	INSERT INTO custom_field_values
+		(field_id, entity_id, value, date_updated)
+	VALUES
+		(:field_id, :entity_id, :value, :date_updated)
+	ON CONFLICT (field_id, entity_id) DO UPDATE SET
+		"value" = EXCLUDED.value,
+		"date_updated" = EXCLUDED.date_updated`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, value); err != nil {
+		return fmt.Errorf("setting value fieldID[%s]: %w", value.FieldID, err)
+	}
+
+	return nil
+}
+
+// DeleteValue removes the value of a custom field for an entity from the
+// database.
+func (s Store) DeleteValue(ctx context.Context, fieldID, entityID string) error {
+	data := struct {
+		FieldID  string `db:"field_id"`
+		EntityID string `db:"entity_id"`
+	}{
+		FieldID:  fieldID,
+		EntityID: entityID,
+	}
+
+	const q = `
+	DELETE FROM
+		custom_field_values
+	WHERE
+		field_id = :field_id AND entity_id = :entity_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting value fieldID[%s]: %w", fieldID, err)
+	}
+
+	return nil
+}
+
+// QueryValues retrieves the custom field values of an entity from the
+// database.
+func (s Store) QueryValues(ctx context.Context, entityID string) ([]EntityValue, error) {
+	data := struct {
+		EntityID string `db:"entity_id"`
+	}{
+		EntityID: entityID,
+	}
+
+	const q = `
+	SELECT
+		f.field_id AS field_id,
+		f.name AS name,
+		f.kind AS kind,
+		v.value AS value
+	FROM
+		custom_field_values v
+	JOIN custom_fields f ON f.field_id = v.field_id
+	WHERE
+		v.entity_id = :entity_id
+	ORDER BY
+		f.name`
+
+	var values []EntityValue
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &values); err != nil {
+		return nil, fmt.Errorf("selecting values entityID[%q]: %w", entityID, err)
+	}
+
+	return values, nil
+}
+
+// QueryWorkspaceMember reports whether a user belongs to a workspace, as its
+// owner or as one of its users.
+func (s Store) QueryWorkspaceMember(ctx context.Context, workspaceID, userID string) (bool, error) {
+	data := struct {
+		WorkspaceID string `db:"workspace_id"`
+		UserID      string `db:"user_id"`
+	}{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+	}
+
+	const q = `
+	SELECT
+		(EXISTS (SELECT 1 FROM workspaces WHERE workspace_id = :workspace_id AND uid = :user_id)
+		OR EXISTS (SELECT 1 FROM workspace_users WHERE wid = :workspace_id AND uid = :user_id)) AS member`
+
+	var result struct {
+		Member bool `db:"member"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return false, fmt.Errorf("selecting workspace member[%s]: %w", userID, err)
+	}
+
+	return result.Member, nil
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Field represent the structure we need for moving data
+// between the app and the database.
+type Field struct {
+	ID          string         `db:"field_id"`
+	WID         string         `db:"wid"`
+	Entity      string         `db:"entity"`
+	Name        string         `db:"name"`
+	Kind        string         `db:"kind"`
+	Options     pq.StringArray `db:"options"`
+	Required    bool           `db:"required"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+// Value represent the structure we need for moving data
+// between the app and the database.
+type Value struct {
+	FieldID     string         `db:"field_id"`
+	EntityID    string         `db:"entity_id"`
+	Value       pq.StringArray `db:"value"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+// EntityValue represent the structure we need for moving data
+// between the app and the database.
+type EntityValue struct {
+	FieldID string         `db:"field_id"`
+	Name    string         `db:"name"`
+	Kind    string         `db:"kind"`
+	Value   pq.StringArray `db:"value"`
+}
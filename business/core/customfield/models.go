@@ -0,0 +1,102 @@
+package customfield
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/customfield/db"
+)
+
+// Set of entity types custom fields can be defined for.
+const (
+	EntityProject   = "project"
+	EntityTask      = "task"
+	EntityTimeEntry = "time_entry"
+)
+
+// Set of kinds of custom fields.
+const (
+	KindText        = "text"
+	KindNumber      = "number"
+	KindDate        = "date"
+	KindSelect      = "select"
+	KindMultiSelect = "multi_select"
+	KindUser        = "user"
+)
+
+// Field represents a typed custom field of an entity type of a workspace.
+// Select and multi select fields take their values from Options, user fields
+// hold the ID of a member of the workspace and date fields are formatted as
+// 2006-01-02. Required fields must be set on every project and task, and on
+// every billable time entry.
+type Field struct {
+	ID          string    `json:"id"`
+	WID         string    `json:"wid"`
+	Entity      string    `json:"entity"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Options     []string  `json:"options"`
+	Required    bool      `json:"required"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+// NewField contains information needed to define a new custom field.
+type NewField struct {
+	Entity   string   `json:"entity" validate:"required,oneof=project task time_entry"`
+	Name     string   `json:"name" validate:"required"`
+	Kind     string   `json:"kind" validate:"required,oneof=text number date select multi_select user"`
+	Options  []string `json:"options" validate:"unique,dive,required"`
+	Required bool     `json:"required"`
+}
+
+// UpdateField defines what information may be provided to modify an existing
+// custom field. The entity type and kind of a field can not change.
+type UpdateField struct {
+	Name     *string  `json:"name"`
+	Options  []string `json:"options" validate:"omitempty,unique,dive,required"`
+	Required *bool    `json:"required"`
+}
+
+// Value represents the value of a custom field of an entity. Fields that are
+// not multi select hold a single value.
+type Value struct {
+	FieldID string   `json:"field_id"`
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Values  []string `json:"values"`
+}
+
+// SetValues contains the values to set on an entity keyed by field ID. An
+// empty list clears the value of the field.
+type SetValues struct {
+	Values map[string][]string `json:"values" validate:"required"`
+}
+
+// =============================================================================
+
+func toField(dbField db.Field) Field {
+	pu := (*Field)(unsafe.Pointer(&dbField))
+	return *pu
+}
+
+func toFieldSlice(dbFields []db.Field) []Field {
+	fields := make([]Field, len(dbFields))
+	for i, dbField := range dbFields {
+		fields[i] = toField(dbField)
+	}
+	return fields
+}
+
+func toValue(dbValue db.EntityValue) Value {
+	pu := (*Value)(unsafe.Pointer(&dbValue))
+	return *pu
+}
+
+func toValueSlice(dbValues []db.EntityValue) []Value {
+	values := make([]Value, len(dbValues))
+	for i, dbValue := range dbValues {
+		values[i] = toValue(dbValue)
+	}
+	return values
+}
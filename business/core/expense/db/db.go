@@ -0,0 +1,163 @@
+// Package db contains expense related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for expense access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new expense into the database.
+func (s Store) Create(ctx context.Context, expense Expense) error {
+	const q = `
+	INSERT INTO expenses
+		(expense_id, wid, pid, tid, uid, description, category, amount, currency, date, billable, invoiced, receipt_url, date_created, date_updated)
+	VALUES
+		(:expense_id, :wid, :pid, :tid, :uid, :description, :category, :amount, :currency, :date, :billable, :invoiced, :receipt_url, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, expense); err != nil {
+		return fmt.Errorf("inserting expense: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an expense document in the database.
+func (s Store) Update(ctx context.Context, expense Expense) error {
+	const q = `
+	UPDATE
+		expenses
+	SET
+		"tid" = :tid,
+		"description" = :description,
+		"category" = :category,
+		"amount" = :amount,
+		"currency" = :currency,
+		"date" = :date,
+		"billable" = :billable,
+		"invoiced" = :invoiced,
+		"receipt_url" = :receipt_url,
+		"date_updated" = :date_updated
+	WHERE
+		expense_id = :expense_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, expense); err != nil {
+		return fmt.Errorf("updating expenseID[%s]: %w", expense.ID, err)
+	}
+
+	return nil
+}
+
+// Delete removes an expense from the database.
+func (s Store) Delete(ctx context.Context, expenseID string) error {
+	data := struct {
+		ExpenseID string `db:"expense_id"`
+	}{
+		ExpenseID: expenseID,
+	}
+
+	const q = `
+	DELETE FROM
+		expenses
+	WHERE
+		expense_id = :expense_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting expenseID[%s]: %w", expenseID, err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified expense from the database.
+func (s Store) QueryByID(ctx context.Context, expenseID string) (Expense, error) {
+	data := struct {
+		ExpenseID string `db:"expense_id"`
+	}{
+		ExpenseID: expenseID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		expenses
+	WHERE
+		expense_id = :expense_id`
+
+	var expense Expense
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &expense); err != nil {
+		return Expense{}, fmt.Errorf("selecting expenseID[%q]: %w", expenseID, err)
+	}
+
+	return expense, nil
+}
+
+// QueryProjectExpenses retrieves a list of existing project expenses from the database.
+func (s Store) QueryProjectExpenses(ctx context.Context, projectID string, pageNumber, rowsPerPage int) ([]Expense, error) {
+	data := struct {
+		Offset      int    `db:"offset"`
+		RowsPerPage int    `db:"rows_per_page"`
+		ProjectID   string `db:"project_id"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+		ProjectID:   projectID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		expenses
+	WHERE
+		pid = :project_id
+	ORDER BY
+		date DESC, expense_id
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var expenses []Expense
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &expenses); err != nil {
+		return nil, fmt.Errorf("selecting expenses: %w", err)
+	}
+
+	return expenses, nil
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+)
+
+// Expense represent the structure we need for moving data
+// between the app and the database.
+type Expense struct {
+	ID          string          `db:"expense_id"`
+	WID         string          `db:"wid"`
+	PID         string          `db:"pid"`
+	TID         database.NullID `db:"tid"`
+	UID         string          `db:"uid"`
+	Description string          `db:"description"`
+	Category    string          `db:"category"`
+	Amount      float64         `db:"amount"`
+	Currency    string          `db:"currency"`
+	Date        time.Time       `db:"date"`
+	Billable    bool            `db:"billable"`
+	Invoiced    bool            `db:"invoiced"`
+	ReceiptURL  string          `db:"receipt_url"`
+	DateCreated time.Time       `db:"date_created"`
+	DateUpdated time.Time       `db:"date_updated"`
+}
@@ -0,0 +1,252 @@
+// Package expense provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package expense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/expense/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound  = errors.New("expense not found")
+	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrInvoiced  = errors.New("expense is already invoiced")
+
+	ErrReferenceNotFound = errors.New("referenced project or task not found")
+)
+
+// Core manages the set of APIs for expense access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for expense api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// Create inserts a new expense into the database.
+func (c Core) Create(ctx context.Context, workspaceID, userID string, ne NewExpense, now time.Time) (Expense, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Expense{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(userID); err != nil {
+		return Expense{}, ErrInvalidID
+	}
+
+	if err := validate.Check(ne); err != nil {
+		return Expense{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbExpense := db.Expense{
+		ID:          validate.GenerateID(),
+		WID:         workspaceID,
+		PID:         ne.PID,
+		TID:         database.NullID(ne.TID),
+		UID:         userID,
+		Description: ne.Description,
+		Category:    ne.Category,
+		Amount:      ne.Amount,
+		Currency:    strings.ToUpper(ne.Currency),
+		Date:        ne.Date,
+		Billable:    ne.Billable,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if dbExpense.TID == "" {
+		dbExpense.TID = database.NoID
+	}
+
+	if err := c.store.Create(ctx, dbExpense); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return Expense{}, ErrReferenceNotFound
+		}
+		return Expense{}, fmt.Errorf("create: %w", err)
+	}
+
+	return toExpense(dbExpense), nil
+}
+
+// Update replaces an expense document in the database.
+func (c Core) Update(ctx context.Context, expenseID string, ue UpdateExpense, now time.Time) error {
+	if err := validate.CheckID(expenseID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(ue); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbExpense, err := c.store.QueryByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating expense expenseID[%s]: %w", expenseID, err)
+	}
+
+	if dbExpense.Invoiced {
+		return ErrInvoiced
+	}
+
+	if ue.TID != nil {
+		dbExpense.TID = database.NullID(*ue.TID)
+		if dbExpense.TID == "" {
+			dbExpense.TID = database.NoID
+		}
+	}
+	if ue.Description != nil {
+		dbExpense.Description = *ue.Description
+	}
+	if ue.Category != nil {
+		dbExpense.Category = *ue.Category
+	}
+	if ue.Amount != nil {
+		dbExpense.Amount = *ue.Amount
+	}
+	if ue.Currency != nil {
+		dbExpense.Currency = strings.ToUpper(*ue.Currency)
+	}
+	if ue.Date != nil {
+		dbExpense.Date = *ue.Date
+	}
+	if ue.Billable != nil {
+		dbExpense.Billable = *ue.Billable
+	}
+	dbExpense.DateUpdated = now
+
+	if err := c.store.Update(ctx, dbExpense); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return ErrReferenceNotFound
+		}
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateReceipt attaches an uploaded receipt to an expense.
+func (c Core) UpdateReceipt(ctx context.Context, expenseID string, ur UpdateReceipt, now time.Time) error {
+	if err := validate.CheckID(expenseID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(ur); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbExpense, err := c.store.QueryByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating expense expenseID[%s]: %w", expenseID, err)
+	}
+
+	dbExpense.ReceiptURL = ur.ReceiptName
+	dbExpense.DateUpdated = now
+
+	if err := c.store.Update(ctx, dbExpense); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return ErrReferenceNotFound
+		}
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// Invoice marks a billable expense as added to a client invoice so it
+// is not billed twice.
+func (c Core) Invoice(ctx context.Context, expenseID string, now time.Time) error {
+	if err := validate.CheckID(expenseID); err != nil {
+		return ErrInvalidID
+	}
+
+	dbExpense, err := c.store.QueryByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("invoicing expense expenseID[%s]: %w", expenseID, err)
+	}
+
+	if !dbExpense.Billable {
+		return fmt.Errorf("expense expenseID[%s] is not billable", expenseID)
+	}
+
+	if dbExpense.Invoiced {
+		return ErrInvoiced
+	}
+
+	dbExpense.Invoiced = true
+	dbExpense.DateUpdated = now
+
+	if err := c.store.Update(ctx, dbExpense); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return ErrReferenceNotFound
+		}
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an expense from the database.
+func (c Core) Delete(ctx context.Context, expenseID string) error {
+	if err := validate.CheckID(expenseID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.Delete(ctx, expenseID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified expense from the database.
+func (c Core) QueryByID(ctx context.Context, expenseID string) (Expense, error) {
+	if err := validate.CheckID(expenseID); err != nil {
+		return Expense{}, ErrInvalidID
+	}
+
+	dbExpense, err := c.store.QueryByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Expense{}, ErrNotFound
+		}
+		return Expense{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toExpense(dbExpense), nil
+}
+
+// QueryProjectExpenses retrieves a list of existing project expenses from the database.
+func (c Core) QueryProjectExpenses(ctx context.Context, projectID string, pageNumber, rowsPerPage int) ([]Expense, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return []Expense{}, ErrInvalidID
+	}
+
+	dbExpenses, err := c.store.QueryProjectExpenses(ctx, projectID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toExpenseSlice(dbExpenses), nil
+}
@@ -0,0 +1,166 @@
+package expense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbschema"
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestExpense(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testexpense")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to work with Expense records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a single Expense.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			ne := NewExpense{
+				PID:         "45cf87a3-5915-4079-a9af-6c559239ddbf",
+				Description: "Flight to client",
+				Category:    "travel",
+				Amount:      450,
+				Currency:    "usd",
+				Date:        time.Date(2021, time.September, 1, 0, 0, 0, 0, time.UTC),
+				Billable:    true,
+			}
+
+			expense, err := core.Create(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "5cf37266-3473-4006-984f-9325122678b7", ne, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create Expense.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, expense.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve Expense by ID: %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve Expense by ID.", dbtest.Success, testID)
+
+			if diff := cmp.Diff(expense, saved); diff != "" {
+				t.Errorf("\t%s\tTest %d:\tShould get back the same Expense. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get back the same Expense.", dbtest.Success, testID)
+
+			upd := UpdateExpense{
+				Amount: dbtest.Float64Pointer(500),
+			}
+
+			if err := core.Update(ctx, expense.ID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to update Expense.", dbtest.Success, testID)
+
+			saved, err = core.QueryByID(ctx, expense.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve updated Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve updated Expense.", dbtest.Success, testID)
+
+			if saved.Amount != *upd.Amount {
+				t.Errorf("\t%s\tTest %d:\tShould be able to see updates to Amount.", dbtest.Failed, testID)
+				t.Logf("\t\tTest %d:\tGot: %v", testID, saved.Amount)
+				t.Logf("\t\tTest %d:\tExp: %v", testID, *upd.Amount)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould be able to see updates to Amount.", dbtest.Success, testID)
+			}
+
+			if err := core.Invoice(ctx, expense.ID, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to invoice Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to invoice Expense.", dbtest.Success, testID)
+
+			if err := core.Update(ctx, expense.ID, upd, now); !errors.Is(err, ErrInvoiced) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to update an invoiced Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to update an invoiced Expense.", dbtest.Success, testID)
+
+			if err := core.Delete(ctx, expense.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to delete Expense.", dbtest.Success, testID)
+
+			_, err = core.QueryByID(ctx, expense.ID)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to retrieve Expense.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestPagingExpense(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testpaging")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to page through Expense records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen paging through 2 Expenses.", testID)
+		{
+			ctx := context.Background()
+
+			expenses1, err := core.QueryProjectExpenses(ctx, "45cf87a3-5915-4079-a9af-6c559239ddbf", 1, 1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve Expenses for page 1 : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve Expenses for page 1.", dbtest.Success, testID)
+
+			if len(expenses1) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single Expense.", dbtest.Success, testID)
+
+			expenses2, err := core.QueryProjectExpenses(ctx, "45cf87a3-5915-4079-a9af-6c559239ddbf", 2, 1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve Expenses for page 2 : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve Expenses for page 2.", dbtest.Success, testID)
+
+			if len(expenses2) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single Expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single Expense.", dbtest.Success, testID)
+
+			if expenses1[0].ID == expenses2[0].ID {
+				t.Logf("\t\tTest %d:\tExpense1: %v", testID, expenses1[0].ID)
+				t.Logf("\t\tTest %d:\tExpense2: %v", testID, expenses2[0].ID)
+				t.Fatalf("\t%s\tTest %d:\tShould have different Expenses : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have different Expenses.", dbtest.Success, testID)
+		}
+	}
+}
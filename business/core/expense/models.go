@@ -0,0 +1,75 @@
+package expense
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/expense/db"
+)
+
+// Expense represents an individual expense.
+type Expense struct {
+	ID          string    `json:"id"`
+	WID         string    `json:"wid"`
+	PID         string    `json:"pid"`
+	TID         string    `json:"tid"`
+	UID         string    `json:"uid"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Date        time.Time `json:"date"`
+	Billable    bool      `json:"billable"`
+	Invoiced    bool      `json:"invoiced"`
+	ReceiptURL  string    `json:"receipt_url"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+// NewExpense contains information needed to create a new expense.
+type NewExpense struct {
+	PID         string    `json:"pid" validate:"required"`
+	TID         string    `json:"tid"`
+	Description string    `json:"description"`
+	Category    string    `json:"category" validate:"required"`
+	Amount      float64   `json:"amount" validate:"gt=0"`
+	Currency    string    `json:"currency" validate:"required,len=3"`
+	Date        time.Time `json:"date" validate:"required"`
+	Billable    bool      `json:"billable"`
+}
+
+// UpdateExpense defines what information may be provided to modify an existing
+// expense. All fields are optional so expenses can send just the fields they want
+// changed. It uses pointer fields ,so we can differentiate between a field that
+// was not provided and a field that was provided as explicitly blank. Normally
+// we do not want to use pointers to basic types ,but we make exceptions around
+// marshalling/unmarshalling.
+type UpdateExpense struct {
+	TID         *string    `json:"tid"`
+	Description *string    `json:"description"`
+	Category    *string    `json:"category"`
+	Amount      *float64   `json:"amount" validate:"omitempty,gt=0"`
+	Currency    *string    `json:"currency" validate:"omitempty,len=3"`
+	Date        *time.Time `json:"date"`
+	Billable    *bool      `json:"billable"`
+}
+
+// UpdateReceipt contains information needed to attach a receipt to an expense.
+type UpdateReceipt struct {
+	ReceiptName string `json:"receipt_name" validate:"required"`
+}
+
+// =============================================================================
+
+func toExpense(dbExpense db.Expense) Expense {
+	pu := (*Expense)(unsafe.Pointer(&dbExpense))
+	return *pu
+}
+
+func toExpenseSlice(dbExpenses []db.Expense) []Expense {
+	expenses := make([]Expense, len(dbExpenses))
+	for i, dbExpense := range dbExpenses {
+		expenses[i] = toExpense(dbExpense)
+	}
+	return expenses
+}
@@ -0,0 +1,206 @@
+// Package db contains export related functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for export access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// SaveFormat inserts or replaces the export format of a workspace in the database.
+func (s Store) SaveFormat(ctx context.Context, format Format) error {
+	const q = `
+	INSERT INTO export_formats
+		(export_format_id, wid, kind, columns, headers, pay_period, period_anchor, overtime_hours, date_created, date_updated)
+	VALUES
+		(:export_format_id, :wid, :kind, :columns, :headers, :pay_period, :period_anchor, :overtime_hours, :date_created, :date_updated)
+	ON CONFLICT (wid, kind) DO UPDATE SET
+		"columns" = EXCLUDED.columns,
+		"headers" = EXCLUDED.headers,
+		"pay_period" = EXCLUDED.pay_period,
+		"period_anchor" = EXCLUDED.period_anchor,
+		"overtime_hours" = EXCLUDED.overtime_hours,
+		"date_updated" = EXCLUDED.date_updated`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, format); err != nil {
+		return fmt.Errorf("saving export format: %w", err)
+	}
+
+	return nil
+}
+
+// QueryFormat gets the export format of a kind of a workspace from the database.
+func (s Store) QueryFormat(ctx context.Context, workspaceID, kind string) (Format, error) {
+	data := struct {
+		WorkspaceID string `db:"workspace_id"`
+		Kind        string `db:"kind"`
+	}{
+		WorkspaceID: workspaceID,
+		Kind:        kind,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		export_formats
+	WHERE
+		wid = :workspace_id AND kind = :kind`
+
+	var format Format
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &format); err != nil {
+		return Format{}, fmt.Errorf("selecting export format kind[%q]: %w", kind, err)
+	}
+
+	return format, nil
+}
+
+// fieldOrder sorts the custom field values of a ledger entry so the values of
+// the time entry come before the ones of its task and then of its project.
+const fieldOrder = `CASE v.entity_id WHEN te.time_entry_id THEN 0 WHEN te.tid THEN 1 ELSE 2 END, f.name`
+
+// QueryLedgerEntries retrieves the finished, billable time entries of a
+// workspace in the date range with their account code and billable rate from
+// the database. A project account code wins over the account code of the
+// client, and the rate is resolved like in the reports. The custom field
+// values of the entry, its task and its project come along with their names,
+// multiple values joined by semicolons.
+func (s Store) QueryLedgerEntries(ctx context.Context, workspaceID string, start, end time.Time) ([]LedgerEntry, error) {
+	data := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		Start       time.Time `db:"start"`
+		End         time.Time `db:"end"`
+	}{
+		WorkspaceID: workspaceID,
+		Start:       start,
+		End:         end,
+	}
+
+	const q = `
+	SELECT
+		te.time_entry_id AS id,
+		te.start AS start,
+		COALESCE(
+			(SELECT ac.code FROM account_codes ac WHERE ac.wid = te.wid AND ac.pid = te.pid LIMIT 1),
+			(SELECT ac.code FROM account_codes ac WHERE ac.wid = te.wid AND ac.cid = p.cid LIMIT 1),
+			'') AS account_code,
+		COALESCE(c.name, '') AS client_name,
+		COALESCE(p.name, '') AS project_name,
+		COALESCE(t.name, '') AS task_name,
+		COALESCE(u.full_name, '') AS user_name,
+		COALESCE(u.email, '') AS email,
+		COALESCE(te.description, '') AS description,
+		CAST(EXTRACT(EPOCH FROM (te.stop - te.start)) / 3600 AS double precision) AS hours,
+		COALESCE(
+			(SELECT tm.rate FROM project_members tm WHERE tm.pid = te.pid AND tm.uid = te.uid AND tm.rate > 0 ORDER BY tm.direct DESC LIMIT 1),
+			NULLIF(p.rate, 0),
+			w.default_hourly_rate,
+			0) AS rate,
+		COALESCE(w.default_currency, '') AS currency,
+		ARRAY(
+			SELECT f.name FROM custom_field_values v JOIN custom_fields f ON f.field_id = v.field_id
+			WHERE v.entity_id IN (te.time_entry_id, te.tid, te.pid)
+			ORDER BY ` + fieldOrder + `) AS field_names,
+		ARRAY(
+			SELECT array_to_string(v.value, ';') FROM custom_field_values v JOIN custom_fields f ON f.field_id = v.field_id
+			WHERE v.entity_id IN (te.time_entry_id, te.tid, te.pid)
+			ORDER BY ` + fieldOrder + `) AS field_values
+	FROM
+		time_entries te
+	JOIN projects p ON p.project_id = te.pid
+	JOIN workspaces w ON w.workspace_id = te.wid
+	LEFT JOIN clients c ON c.client_id = p.cid
+	LEFT JOIN tasks t ON t.task_id = te.tid
+	LEFT JOIN users u ON u.user_id = te.uid
+	WHERE
+		te.wid = :workspace_id
+		AND te.billable = true AND p.billable = true
+		AND te.start >= :start AND te.start <= :end
+		AND te.stop > te.start
+	ORDER BY
+		te.start, te.time_entry_id`
+
+	var entries []LedgerEntry
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &entries); err != nil {
+		return nil, fmt.Errorf("selecting ledger entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// QueryPayrollEntries retrieves the finished time entries of a workspace in
+// the date range from the database, ordered by user and start.
+func (s Store) QueryPayrollEntries(ctx context.Context, workspaceID string, start, end time.Time) ([]PayrollEntry, error) {
+	data := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		Start       time.Time `db:"start"`
+		End         time.Time `db:"end"`
+	}{
+		WorkspaceID: workspaceID,
+		Start:       start,
+		End:         end,
+	}
+
+	const q = `
+	SELECT
+		te.uid AS uid,
+		COALESCE(u.full_name, '') AS user_name,
+		COALESCE(u.email, '') AS email,
+		te.start AS start,
+		CAST(EXTRACT(EPOCH FROM (te.stop - te.start)) / 3600 AS double precision) AS hours
+	FROM
+		time_entries te
+	LEFT JOIN users u ON u.user_id = te.uid
+	WHERE
+		te.wid = :workspace_id
+		AND te.start >= :start AND te.start <= :end
+		AND te.stop > te.start
+	ORDER BY
+		te.uid, te.start`
+
+	var entries []PayrollEntry
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &entries); err != nil {
+		return nil, fmt.Errorf("selecting payroll entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Format represent the structure we need for moving data
+// between the app and the database.
+type Format struct {
+	ID            string         `db:"export_format_id"`
+	WID           string         `db:"wid"`
+	Kind          string         `db:"kind"`
+	Columns       pq.StringArray `db:"columns"`
+	Headers       pq.StringArray `db:"headers"`
+	PayPeriod     string         `db:"pay_period"`
+	PeriodAnchor  time.Time      `db:"period_anchor"`
+	OvertimeHours float64        `db:"overtime_hours"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+}
+
+// LedgerEntry represent the structure we need for moving data
+// between the app and the database.
+type LedgerEntry struct {
+	ID          string         `db:"id"`
+	Start       time.Time      `db:"start"`
+	AccountCode string         `db:"account_code"`
+	ClientName  string         `db:"client_name"`
+	ProjectName string         `db:"project_name"`
+	TaskName    string         `db:"task_name"`
+	UserName    string         `db:"user_name"`
+	Email       string         `db:"email"`
+	Description string         `db:"description"`
+	Hours       float64        `db:"hours"`
+	Rate        float64        `db:"rate"`
+	Currency    string         `db:"currency"`
+	FieldNames  pq.StringArray `db:"field_names"`
+	FieldValues pq.StringArray `db:"field_values"`
+}
+
+// PayrollEntry represent the structure we need for moving data
+// between the app and the database.
+type PayrollEntry struct {
+	UID      string    `db:"uid"`
+	UserName string    `db:"user_name"`
+	Email    string    `db:"email"`
+	Start    time.Time `db:"start"`
+	Hours    float64   `db:"hours"`
+}
@@ -0,0 +1,204 @@
+// Package export provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/export/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for export operations.
+var (
+	ErrInvalidID      = errors.New("ID is not in its proper form")
+	ErrInvalidKind    = errors.New("export kind must be ledger or payroll")
+	ErrInvalidColumn  = errors.New("column is not supported by the export")
+	ErrInvalidHeaders = errors.New("headers must match the columns")
+	ErrInvalidRange   = errors.New("end date must be after start date")
+)
+
+// Core manages the set of APIs for export access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for export api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// SaveFormat configures the export format of a kind of a workspace.
+func (c Core) SaveFormat(ctx context.Context, workspaceID, kind string, nf NewFormat, now time.Time) (Format, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Format{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nf); err != nil {
+		return Format{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	for _, column := range nf.Columns {
+		var known bool
+		switch kind {
+		case KindLedger:
+			_, known = ledgerColumns[column]
+			if name := strings.TrimPrefix(column, fieldColumn); name != column {
+				known = name != ""
+			}
+		case KindPayroll:
+			_, known = payrollColumns[column]
+		default:
+			return Format{}, ErrInvalidKind
+		}
+		if !known {
+			return Format{}, fmt.Errorf("column[%s]: %w", column, ErrInvalidColumn)
+		}
+	}
+
+	if len(nf.Headers) != 0 && len(nf.Headers) != len(nf.Columns) {
+		return Format{}, ErrInvalidHeaders
+	}
+
+	dbFormat := db.Format{
+		ID:            validate.GenerateID(),
+		WID:           workspaceID,
+		Kind:          kind,
+		Columns:       nf.Columns,
+		Headers:       nf.Headers,
+		PayPeriod:     nf.PayPeriod,
+		PeriodAnchor:  nf.PeriodAnchor,
+		OvertimeHours: nf.OvertimeHours,
+		DateCreated:   now,
+		DateUpdated:   now,
+	}
+
+	if dbFormat.Headers == nil {
+		dbFormat.Headers = []string{}
+	}
+	if dbFormat.PayPeriod == "" {
+		dbFormat.PayPeriod = PeriodWeekly
+	}
+	if dbFormat.PeriodAnchor.IsZero() {
+		dbFormat.PeriodAnchor = defaultAnchor
+	}
+
+	if err := c.store.SaveFormat(ctx, dbFormat); err != nil {
+		return Format{}, fmt.Errorf("save: %w", err)
+	}
+
+	return c.QueryFormat(ctx, workspaceID, kind)
+}
+
+// QueryFormat gets the export format of a kind of a workspace. Workspaces that
+// did not configure the format get the default one.
+func (c Core) QueryFormat(ctx context.Context, workspaceID, kind string) (Format, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Format{}, ErrInvalidID
+	}
+
+	if kind != KindLedger && kind != KindPayroll {
+		return Format{}, ErrInvalidKind
+	}
+
+	dbFormat, err := c.store.QueryFormat(ctx, workspaceID, kind)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return defaultFormat(workspaceID, kind), nil
+		}
+		return Format{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toFormat(dbFormat), nil
+}
+
+// Ledger builds the rows of a ledger export of the billable hours of a
+// workspace between start and end, with one row per time entry. The first row
+// holds the headers. Custom field columns take the value set on the time
+// entry, or else on its task or its project.
+func (c Core) Ledger(ctx context.Context, workspaceID string, start, end time.Time) ([][]string, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidRange
+	}
+
+	format, err := c.QueryFormat(ctx, workspaceID, KindLedger)
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntries, err := c.store.QueryLedgerEntries(ctx, workspaceID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	records := [][]string{format.header()}
+	for _, dbEntry := range dbEntries {
+		record := make([]string, len(format.Columns))
+		for i, column := range format.Columns {
+			if value, exists := ledgerColumns[column]; exists {
+				record[i] = value(dbEntry)
+			}
+			if name := strings.TrimPrefix(column, fieldColumn); name != column {
+				record[i] = fieldValue(dbEntry, name)
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// Payroll builds the rows of a payroll export of the hours of a workspace
+// between start and end, with one row per user and pay period split into
+// regular and overtime hours. The first row holds the headers.
+func (c Core) Payroll(ctx context.Context, workspaceID string, start, end time.Time) ([][]string, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidRange
+	}
+
+	format, err := c.QueryFormat(ctx, workspaceID, KindPayroll)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overtime is counted per week, so the hours of the week the range starts
+	// in are needed even when they are before start.
+	weekStart, _ := grid(start, format.PeriodAnchor, 7)
+
+	dbEntries, err := c.store.QueryPayrollEntries(ctx, workspaceID, weekStart, end)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	rows := payroll(dbEntries, format, start)
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].periodStart.Equal(rows[j].periodStart) {
+			return rows[i].periodStart.Before(rows[j].periodStart)
+		}
+		return rows[i].userName < rows[j].userName
+	})
+
+	records := [][]string{format.header()}
+	for _, row := range rows {
+		record := make([]string, len(format.Columns))
+		for i, column := range format.Columns {
+			if value, exists := payrollColumns[column]; exists {
+				record[i] = value(row)
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
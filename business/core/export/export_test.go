@@ -0,0 +1,143 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestLedger(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testledger")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to export billable hours to a ledger.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling two billable hours on a project with an account code.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			start := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2021, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+			const q = `
+			INSERT INTO time_entries
+				(time_entry_id, description, uid, wid, pid, tid, billable, start, stop, duration, created_with, tags, dur_only, date_created, date_updated)
+			VALUES
+				('e1b3c5d7-2f4a-4b6c-8d0e-1f3a5b7c9d12', 'ledger', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '346efd40-6d6e-46d5-b60b-5db9fc171779', true, '2021-10-01 10:00:00', '2021-10-01 12:00:00', 7200,
+				 'test', '{}', false, '2021-10-01 12:00:00', '2021-10-01 12:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert time entry : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert time entry.", dbtest.Success, testID)
+
+			nf := NewFormat{
+				Columns: []string{"date", "account_code", "hours", "amount"},
+				Headers: []string{"Date", "Account", "Quantity", "Amount"},
+			}
+
+			if _, err := core.SaveFormat(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", KindLedger, nf, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to save ledger format : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to save ledger format.", dbtest.Success, testID)
+
+			records, err := core.Ledger(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", start, end)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to build ledger : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to build ledger.", dbtest.Success, testID)
+
+			exp := [][]string{
+				{"Date", "Account", "Quantity", "Amount"},
+				{"2021-10-01", "4100", "2.00", "160.00"},
+			}
+			if diff := cmp.Diff(exp, records); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the ledger rows. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the ledger rows.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestPayroll(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testpayroll")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to export hours to payroll.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a week of 45 hours.", testID)
+		{
+			ctx := context.Background()
+			start := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2021, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+			const q = `
+			INSERT INTO time_entries
+				(time_entry_id, description, uid, wid, pid, tid, billable, start, stop, duration, created_with, tags, dur_only, date_created, date_updated)
+			VALUES
+				('f2c4d6e8-3a5b-4c7d-9e1f-2a4b6c8d0e13', 'payroll', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '346efd40-6d6e-46d5-b60b-5db9fc171779', false, '2021-10-04 06:00:00', '2021-10-04 21:00:00', 54000,
+				 'test', '{}', false, '2021-10-04 21:00:00', '2021-10-04 21:00:00'),
+				('a3d5e7f9-4b6c-4d8e-8f2a-3b5c7d9e1f24', 'payroll', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '346efd40-6d6e-46d5-b60b-5db9fc171779', false, '2021-10-05 06:00:00', '2021-10-05 21:00:00', 54000,
+				 'test', '{}', false, '2021-10-05 21:00:00', '2021-10-05 21:00:00'),
+				('b4e6f8a0-5c7d-4e9f-9a3b-4c6d8e0f2a35', 'payroll', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '346efd40-6d6e-46d5-b60b-5db9fc171779', false, '2021-10-06 06:00:00', '2021-10-06 21:00:00', 54000,
+				 'test', '{}', false, '2021-10-06 21:00:00', '2021-10-06 21:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert time entries : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert time entries.", dbtest.Success, testID)
+
+			records, err := core.Payroll(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", start, end)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to build payroll : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to build payroll.", dbtest.Success, testID)
+
+			if len(records) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a header and a single row : %v.", dbtest.Failed, testID, records)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a header and a single row.", dbtest.Success, testID)
+
+			row := records[1]
+			exp := []string{"2021-10-04", "2021-10-10", "40.00", "5.00", "45.00"}
+			got := []string{row[0], row[1], row[4], row[5], row[6]}
+			if diff := cmp.Diff(exp, got); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould split regular and overtime hours. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould split regular and overtime hours.", dbtest.Success, testID)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package export
+
+import (
+	"math"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/export/db"
+)
+
+// Set of export kinds.
+const (
+	KindLedger  = "ledger"
+	KindPayroll = "payroll"
+)
+
+// Set of pay periods of the payroll export.
+const (
+	PeriodWeekly      = "weekly"
+	PeriodBiweekly    = "biweekly"
+	PeriodSemimonthly = "semimonthly"
+	PeriodMonthly     = "monthly"
+)
+
+// ledgerColumns maps the columns a ledger export may contain to their values.
+var ledgerColumns = map[string]func(e db.LedgerEntry) string{
+	"entry_id":     func(e db.LedgerEntry) string { return e.ID },
+	"date":         func(e db.LedgerEntry) string { return e.Start.Format("2006-01-02") },
+	"account_code": func(e db.LedgerEntry) string { return e.AccountCode },
+	"client":       func(e db.LedgerEntry) string { return e.ClientName },
+	"project":      func(e db.LedgerEntry) string { return e.ProjectName },
+	"task":         func(e db.LedgerEntry) string { return e.TaskName },
+	"user":         func(e db.LedgerEntry) string { return e.UserName },
+	"email":        func(e db.LedgerEntry) string { return e.Email },
+	"description":  func(e db.LedgerEntry) string { return e.Description },
+	"hours":        func(e db.LedgerEntry) string { return formatFloat(e.Hours) },
+	"rate":         func(e db.LedgerEntry) string { return formatFloat(e.Rate) },
+	"amount":       func(e db.LedgerEntry) string { return formatFloat(e.Hours * e.Rate) },
+	"currency":     func(e db.LedgerEntry) string { return e.Currency },
+}
+
+// fieldColumn prefixes the ledger columns holding the value of the custom
+// field named after it.
+const fieldColumn = "field:"
+
+// fieldValue returns the value of the named custom field of a ledger entry,
+// the first one found when the time entry, its task or its project share it.
+func fieldValue(e db.LedgerEntry, name string) string {
+	for i, fieldName := range e.FieldNames {
+		if fieldName == name && i < len(e.FieldValues) {
+			return e.FieldValues[i]
+		}
+	}
+	return ""
+}
+
+// payrollColumns maps the columns a payroll export may contain to their values.
+var payrollColumns = map[string]func(r payrollRow) string{
+	"period_start":   func(r payrollRow) string { return r.periodStart.Format("2006-01-02") },
+	"period_end":     func(r payrollRow) string { return r.periodEnd.AddDate(0, 0, -1).Format("2006-01-02") },
+	"uid":            func(r payrollRow) string { return r.uid },
+	"user":           func(r payrollRow) string { return r.userName },
+	"email":          func(r payrollRow) string { return r.email },
+	"regular_hours":  func(r payrollRow) string { return formatFloat(r.regular) },
+	"overtime_hours": func(r payrollRow) string { return formatFloat(r.overtime) },
+	"total_hours":    func(r payrollRow) string { return formatFloat(r.regular + r.overtime) },
+}
+
+// Format represents the configured columns of an export of a workspace.
+// Headers rename the columns in the first row of the file. The pay period,
+// its anchor and the weekly overtime threshold only apply to payroll exports.
+// Ledger columns named field:<name> hold the value of that custom field.
+type Format struct {
+	ID            string    `json:"id"`
+	WID           string    `json:"wid"`
+	Kind          string    `json:"kind"`
+	Columns       []string  `json:"columns"`
+	Headers       []string  `json:"headers"`
+	PayPeriod     string    `json:"pay_period"`
+	PeriodAnchor  time.Time `json:"period_anchor"`
+	OvertimeHours float64   `json:"overtime_hours"`
+	DateCreated   time.Time `json:"date_created"`
+	DateUpdated   time.Time `json:"date_updated"`
+}
+
+// NewFormat contains information needed to configure an export format.
+type NewFormat struct {
+	Columns       []string  `json:"columns" validate:"required,min=1"`
+	Headers       []string  `json:"headers"`
+	PayPeriod     string    `json:"pay_period" validate:"omitempty,oneof=weekly biweekly semimonthly monthly"`
+	PeriodAnchor  time.Time `json:"period_anchor"`
+	OvertimeHours float64   `json:"overtime_hours" validate:"gte=0"`
+}
+
+// =============================================================================
+
+// defaultAnchor is the Monday the weekly periods start from when a workspace
+// did not configure an anchor.
+var defaultAnchor = time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// defaultFormat returns the format used when a workspace did not configure one.
+func defaultFormat(workspaceID, kind string) Format {
+	format := Format{
+		WID:          workspaceID,
+		Kind:         kind,
+		PayPeriod:    PeriodWeekly,
+		PeriodAnchor: defaultAnchor,
+	}
+
+	switch kind {
+	case KindLedger:
+		format.Columns = []string{"date", "account_code", "client", "project", "user", "description", "hours", "rate", "amount", "currency"}
+	case KindPayroll:
+		format.Columns = []string{"period_start", "period_end", "user", "email", "regular_hours", "overtime_hours", "total_hours"}
+		format.OvertimeHours = 40
+	}
+
+	return format
+}
+
+// header returns the first row of an export.
+func (f Format) header() []string {
+	if len(f.Headers) == len(f.Columns) {
+		return f.Headers
+	}
+	return f.Columns
+}
+
+// payrollRow holds the hours of a user in a pay period.
+type payrollRow struct {
+	uid         string
+	userName    string
+	email       string
+	periodStart time.Time
+	periodEnd   time.Time
+	regular     float64
+	overtime    float64
+}
+
+// payroll splits the hours of the entries into regular and overtime hours per
+// user and pay period. Overtime is the part of the hours of a week above the
+// overtime threshold, where weeks start on the weekday of the anchor. Entries
+// before start only count towards the week they are in.
+func payroll(entries []db.PayrollEntry, f Format, start time.Time) []payrollRow {
+	type key struct {
+		uid   string
+		start time.Time
+	}
+
+	var rows []*payrollRow
+	index := make(map[key]*payrollRow)
+	weeks := make(map[key]float64)
+
+	for _, e := range entries {
+		week, _ := grid(e.Start, f.PeriodAnchor, 7)
+		before := weeks[key{e.UID, week}]
+		after := before + e.Hours
+		weeks[key{e.UID, week}] = after
+
+		if e.Start.Before(start) {
+			continue
+		}
+
+		var overtime float64
+		if f.OvertimeHours > 0 {
+			overtime = math.Max(0, after-math.Max(before, f.OvertimeHours))
+		}
+
+		periodStart, periodEnd := period(e.Start, f)
+		row, exists := index[key{e.UID, periodStart}]
+		if !exists {
+			row = &payrollRow{
+				uid:         e.UID,
+				userName:    e.UserName,
+				email:       e.Email,
+				periodStart: periodStart,
+				periodEnd:   periodEnd,
+			}
+			index[key{e.UID, periodStart}] = row
+			rows = append(rows, row)
+		}
+		row.regular += e.Hours - overtime
+		row.overtime += overtime
+	}
+
+	result := make([]payrollRow, len(rows))
+	for i, row := range rows {
+		result[i] = *row
+	}
+
+	return result
+}
+
+// period returns the pay period t falls in. The end is exclusive.
+func period(t time.Time, f Format) (time.Time, time.Time) {
+	t = t.UTC()
+	year, month, day := t.Date()
+
+	switch f.PayPeriod {
+	case PeriodBiweekly:
+		return grid(t, f.PeriodAnchor, 14)
+	case PeriodSemimonthly:
+		if day <= 15 {
+			return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), time.Date(year, month, 16, 0, 0, 0, 0, time.UTC)
+		}
+		return time.Date(year, month, 16, 0, 0, 0, 0, time.UTC), time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+	case PeriodMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return grid(t, f.PeriodAnchor, 7)
+	}
+}
+
+// grid returns the period of the given number of days counted from the anchor
+// that t falls in. The end is exclusive.
+func grid(t time.Time, anchor time.Time, days int) (time.Time, time.Time) {
+	if anchor.IsZero() {
+		anchor = defaultAnchor
+	}
+	anchor = time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, time.UTC)
+
+	n := int(math.Floor(t.UTC().Sub(anchor).Hours() / 24 / float64(days)))
+	periodStart := anchor.AddDate(0, 0, n*days)
+
+	return periodStart, periodStart.AddDate(0, 0, days)
+}
+
+// formatFloat formats money and hours with two decimal places.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', 2, 64)
+}
+
+func toFormat(dbFormat db.Format) Format {
+	pf := (*Format)(unsafe.Pointer(&dbFormat))
+	return *pf
+}
@@ -153,3 +153,213 @@ func (s Store) QueryWorkspaceGroups(ctx context.Context, workspaceID string, pag
 
 	return groups, nil
 }
+
+// AddMember inserts a user into a group, unless the user is already a member.
+func (s Store) AddMember(ctx context.Context, member Member) error {
+	const q = `
+	INSERT INTO group_members
+		(group_id, uid, date_created)
+	VALUES
+		(:group_id, :uid, :date_created)
+	ON CONFLICT DO NOTHING`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, member); err != nil {
+		return fmt.Errorf("inserting member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a group.
+func (s Store) RemoveMember(ctx context.Context, groupID, userID string) error {
+	data := struct {
+		GroupID string `db:"group_id"`
+		UserID  string `db:"user_id"`
+	}{
+		GroupID: groupID,
+		UserID:  userID,
+	}
+
+	const q = `
+	DELETE FROM
+		group_members
+	WHERE
+		group_id = :group_id AND
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting member[%s] of groupID[%s]: %w", userID, groupID, err)
+	}
+
+	return nil
+}
+
+// DeleteMembers removes every member of a group.
+func (s Store) DeleteMembers(ctx context.Context, groupID string) error {
+	data := struct {
+		GroupID string `db:"group_id"`
+	}{
+		GroupID: groupID,
+	}
+
+	const q = `
+	DELETE FROM
+		group_members
+	WHERE
+		group_id = :group_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting members of groupID[%s]: %w", groupID, err)
+	}
+
+	return nil
+}
+
+// QueryMembers retrieves the members of a group from the database.
+func (s Store) QueryMembers(ctx context.Context, groupID string, pageNumber, rowsPerPage int) ([]Member, error) {
+	data := struct {
+		Offset      int    `db:"offset"`
+		RowsPerPage int    `db:"rows_per_page"`
+		GroupID     string `db:"group_id"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+		GroupID:     groupID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		group_members
+	WHERE
+		group_id = :group_id
+	ORDER BY
+		uid
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var members []Member
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &members); err != nil {
+		return nil, fmt.Errorf("selecting members: %w", err)
+	}
+
+	return members, nil
+}
+
+// QueryWorkspaceMember reports whether a user belongs to a workspace, as its
+// owner or as one of its users.
+func (s Store) QueryWorkspaceMember(ctx context.Context, workspaceID, userID string) (bool, error) {
+	data := struct {
+		WorkspaceID string `db:"workspace_id"`
+		UserID      string `db:"user_id"`
+	}{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+	}
+
+	const q = `
+	SELECT
+		(EXISTS (SELECT 1 FROM workspaces WHERE workspace_id = :workspace_id AND uid = :user_id)
+		OR EXISTS (SELECT 1 FROM workspace_users WHERE wid = :workspace_id AND uid = :user_id)) AS member`
+
+	var result struct {
+		Member bool `db:"member"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return false, fmt.Errorf("selecting workspace member[%s]: %w", userID, err)
+	}
+
+	return result.Member, nil
+}
+
+// AddProject assigns a group to a project, or updates the membership settings
+// of a group already assigned to it.
+func (s Store) AddProject(ctx context.Context, pg ProjectGroup) error {
+	const q = `
+	INSERT INTO project_groups
+		(pid, group_id, wid, manager, rate, date_created, date_updated)
+	VALUES
+		(:pid, :group_id, :wid, :manager, :rate, :date_created, :date_updated)
+	ON CONFLICT (pid, group_id) DO UPDATE SET
+		manager = EXCLUDED.manager,
+		rate = EXCLUDED.rate,
+		date_updated = EXCLUDED.date_updated`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, pg); err != nil {
+		return fmt.Errorf("inserting project group: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveProject removes a group from a project.
+func (s Store) RemoveProject(ctx context.Context, groupID, projectID string) error {
+	data := struct {
+		GroupID   string `db:"group_id"`
+		ProjectID string `db:"project_id"`
+	}{
+		GroupID:   groupID,
+		ProjectID: projectID,
+	}
+
+	const q = `
+	DELETE FROM
+		project_groups
+	WHERE
+		group_id = :group_id AND
+		pid = :project_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting project[%s] of groupID[%s]: %w", projectID, groupID, err)
+	}
+
+	return nil
+}
+
+// DeleteProjects removes a group from every project.
+func (s Store) DeleteProjects(ctx context.Context, groupID string) error {
+	data := struct {
+		GroupID string `db:"group_id"`
+	}{
+		GroupID: groupID,
+	}
+
+	const q = `
+	DELETE FROM
+		project_groups
+	WHERE
+		group_id = :group_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting projects of groupID[%s]: %w", groupID, err)
+	}
+
+	return nil
+}
+
+// QueryProjects retrieves the projects a group is assigned to from the
+// database.
+func (s Store) QueryProjects(ctx context.Context, groupID string) ([]ProjectGroup, error) {
+	data := struct {
+		GroupID string `db:"group_id"`
+	}{
+		GroupID: groupID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		project_groups
+	WHERE
+		group_id = :group_id
+	ORDER BY
+		pid`
+
+	var pgs []ProjectGroup
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &pgs); err != nil {
+		return nil, fmt.Errorf("selecting projects: %w", err)
+	}
+
+	return pgs, nil
+}
@@ -14,3 +14,23 @@ type Group struct {
 	DateCreated time.Time `db:"date_created"`
 	DateUpdated time.Time `db:"date_updated"`
 }
+
+// Member represent the structure we need for moving data
+// between the app and the database.
+type Member struct {
+	GroupID     string    `db:"group_id"`
+	UID         string    `db:"uid"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+// ProjectGroup represent the structure we need for moving data
+// between the app and the database.
+type ProjectGroup struct {
+	PID         string    `db:"pid"`
+	GroupID     string    `db:"group_id"`
+	WID         string    `db:"wid"`
+	Manager     bool      `db:"manager"`
+	Rate        float64   `db:"rate"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/AhmedShaef/wakt/business/core/group/db"
+	dbp "github.com/AhmedShaef/wakt/business/core/project/db"
 	"github.com/AhmedShaef/wakt/business/sys/database"
 	"github.com/AhmedShaef/wakt/business/sys/validate"
 	"github.com/jmoiron/sqlx"
@@ -18,19 +19,24 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound  = errors.New("user not found")
-	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrNotFound           = errors.New("user not found")
+	ErrInvalidID          = errors.New("ID is not in its proper form")
+	ErrNotWorkspaceMember = errors.New("user is not a member of the workspace")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrOtherWorkspace     = errors.New("project belongs to another workspace")
 )
 
 // Core manages the set of APIs for user access.
 type Core struct {
-	store db.Store
+	store    db.Store
+	projects dbp.Store
 }
 
 // NewCore constructs a core for user api access.
 func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
 	return Core{
-		store: db.NewStore(log, sqlxDB),
+		store:    db.NewStore(log, sqlxDB),
+		projects: dbp.NewStore(log, sqlxDB),
 	}
 }
 
@@ -95,8 +101,22 @@ func (c Core) Delete(ctx context.Context, groupID string) error {
 		return ErrInvalidID
 	}
 
-	if err := c.store.Delete(ctx, groupID); err != nil {
-		return fmt.Errorf("delete: %w", err)
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.DeleteProjects(ctx, groupID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		if err := store.DeleteMembers(ctx, groupID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		if err := store.Delete(ctx, groupID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
@@ -131,3 +151,173 @@ func (c Core) QueryWorkspaceGroups(ctx context.Context, workspaceID string, page
 	}
 	return toGroupSlice(dbGroups), nil
 }
+
+// AddMembers adds users of the workspace to a group. Users already in the
+// group are left untouched.
+func (c Core) AddMembers(ctx context.Context, groupID string, nm NewMembers, now time.Time) ([]Member, error) {
+	if err := validate.CheckID(groupID); err != nil {
+		return []Member{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nm); err != nil {
+		return []Member{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbgrop, err := c.store.QueryByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return []Member{}, ErrNotFound
+		}
+		return []Member{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbmembers := make([]db.Member, len(nm.UIDs))
+	for i, uid := range nm.UIDs {
+		member, err := c.store.QueryWorkspaceMember(ctx, dbgrop.WID, uid)
+		if err != nil {
+			return []Member{}, fmt.Errorf("query: %w", err)
+		}
+		if !member {
+			return []Member{}, ErrNotWorkspaceMember
+		}
+		dbmembers[i] = db.Member{
+			GroupID:     groupID,
+			UID:         uid,
+			DateCreated: now,
+		}
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		for _, dbmember := range dbmembers {
+			if err := store.AddMember(ctx, dbmember); err != nil {
+				return fmt.Errorf("create: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return []Member{}, fmt.Errorf("tran: %w", err)
+	}
+
+	return toMemberSlice(dbmembers), nil
+}
+
+// RemoveMembers removes users from a group.
+func (c Core) RemoveMembers(ctx context.Context, groupID string, userIDs []string) error {
+	if err := validate.CheckID(groupID); err != nil {
+		return ErrInvalidID
+	}
+	for _, uid := range userIDs {
+		if err := validate.CheckID(uid); err != nil {
+			return ErrInvalidID
+		}
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		for _, uid := range userIDs {
+			if err := store.RemoveMember(ctx, groupID, uid); err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMembers retrieves the members of a group from the database.
+func (c Core) QueryMembers(ctx context.Context, groupID string, pageNumber, rowsPerPage int) ([]Member, error) {
+	if err := validate.CheckID(groupID); err != nil {
+		return []Member{}, ErrInvalidID
+	}
+
+	dbMembers, err := c.store.QueryMembers(ctx, groupID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return toMemberSlice(dbMembers), nil
+}
+
+// AssignProject makes every member of a group, present and future, a team
+// member of a project of the same workspace.
+func (c Core) AssignProject(ctx context.Context, groupID string, np NewProjectGroup, now time.Time) (ProjectGroup, error) {
+	if err := validate.CheckID(groupID); err != nil {
+		return ProjectGroup{}, ErrInvalidID
+	}
+
+	if err := validate.Check(np); err != nil {
+		return ProjectGroup{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbgrop, err := c.store.QueryByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ProjectGroup{}, ErrNotFound
+		}
+		return ProjectGroup{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbprojct, err := c.projects.QueryByID(ctx, np.PID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ProjectGroup{}, ErrProjectNotFound
+		}
+		return ProjectGroup{}, fmt.Errorf("query project: %w", err)
+	}
+	if dbprojct.WID != dbgrop.WID {
+		return ProjectGroup{}, ErrOtherWorkspace
+	}
+
+	dbpg := db.ProjectGroup{
+		PID:         np.PID,
+		GroupID:     groupID,
+		WID:         dbgrop.WID,
+		Manager:     np.Manager,
+		Rate:        np.Rate,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.store.AddProject(ctx, dbpg); err != nil {
+		return ProjectGroup{}, fmt.Errorf("create: %w", err)
+	}
+
+	return toProjectGroup(dbpg), nil
+}
+
+// UnassignProject removes a group from the team of a project.
+func (c Core) UnassignProject(ctx context.Context, groupID, projectID string) error {
+	if err := validate.CheckID(groupID); err != nil {
+		return ErrInvalidID
+	}
+	if err := validate.CheckID(projectID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.RemoveProject(ctx, groupID, projectID); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryProjects retrieves the projects a group is assigned to from the
+// database.
+func (c Core) QueryProjects(ctx context.Context, groupID string) ([]ProjectGroup, error) {
+	if err := validate.CheckID(groupID); err != nil {
+		return []ProjectGroup{}, ErrInvalidID
+	}
+
+	dbpgs, err := c.store.QueryProjects(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return toProjectGroupSlice(dbpgs), nil
+}
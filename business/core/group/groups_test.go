@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/AhmedShaef/wakt/business/core/project"
 	"github.com/AhmedShaef/wakt/business/data/dbschema"
 	"github.com/AhmedShaef/wakt/business/data/dbtest"
 	"github.com/AhmedShaef/wakt/foundation/docker"
@@ -142,3 +143,90 @@ func TestPagingGroup(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupMembers(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testgroupmembers")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+	projectCore := project.NewCore(log, db)
+
+	const (
+		workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+		projectID   = "45cf87a3-5915-4079-a9af-6c559239ddbf"
+		adminID     = "5cf37266-3473-4006-984f-9325122678b7"
+		memberID    = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+	)
+
+	t.Log("Given the need to give a group access to a private project.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen assigning a group to the default project.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			upd := project.UpdateProject{
+				IsPrivate: dbtest.BoolPointer(true),
+			}
+			if err := projectCore.Update(ctx, projectID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to make project private : %s.", dbtest.Failed, testID, err)
+			}
+
+			grp, err := core.Create(ctx, adminID, NewGroup{Name: "Developers", WID: workspaceID}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create group : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create group.", dbtest.Success, testID)
+
+			nm := NewMembers{
+				UIDs: []string{memberID},
+			}
+			if _, err := core.AddMembers(ctx, grp.ID, nm, now); !errors.Is(err, ErrNotWorkspaceMember) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to add a user outside the workspace : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to add a user outside the workspace.", dbtest.Success, testID)
+
+			const q = `
+			INSERT INTO workspace_users (workspace_user_id, uid, wid, admin, active, invite_key, date_created, date_updated)
+			VALUES ('9b1f3d6a-2c4e-4f8a-b0d2-6e8f1a3c5b7d', '45b5fbd3-755f-4379-8f07-a58d4a30fa2f',
+				'7da3ca14-6366-47cf-b953-f706226567d8', false, true, '', '2021-10-01 00:00:00', '2021-10-01 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to join the workspace : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.AddMembers(ctx, grp.ID, nm, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add members : %s.", dbtest.Failed, testID, err)
+			}
+			members, err := core.QueryMembers(ctx, grp.ID, 1, 10)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve members : %s.", dbtest.Failed, testID, err)
+			}
+			if len(members) != 1 || members[0].UID != memberID {
+				t.Fatalf("\t%s\tTest %d:\tShould have the added member : %+v.", dbtest.Failed, testID, members)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to add members.", dbtest.Success, testID)
+
+			if err := projectCore.CheckAccess(ctx, projectID, memberID); !errors.Is(err, project.ErrForbidden) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT give access before the group is assigned : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.AssignProject(ctx, grp.ID, NewProjectGroup{PID: projectID, Rate: 40}, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to assign the group to the project : %s.", dbtest.Failed, testID, err)
+			}
+			if err := projectCore.CheckAccess(ctx, projectID, memberID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould give access to a group member : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould give access to a group member.", dbtest.Success, testID)
+
+			if err := core.RemoveMembers(ctx, grp.ID, []string{memberID}); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to remove members : %s.", dbtest.Failed, testID, err)
+			}
+			if err := projectCore.CheckAccess(ctx, projectID, memberID); !errors.Is(err, project.ErrForbidden) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT give access to a removed member : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT give access to a removed member.", dbtest.Success, testID)
+		}
+	}
+}
@@ -33,6 +33,36 @@ type UpdateGroup struct {
 	Name *string `json:"name"`
 }
 
+// Member represents a user of a Group.
+type Member struct {
+	GroupID     string    `json:"group_id"`
+	UID         string    `json:"uid"`
+	DateCreated time.Time `json:"date_created"`
+}
+
+// NewMembers contains the users to be added to a Group.
+type NewMembers struct {
+	UIDs []string `json:"uids" validate:"required,min=1,unique,dive,uuid"`
+}
+
+// ProjectGroup represents a Group assigned to a project as team members.
+type ProjectGroup struct {
+	PID         string    `json:"pid"`
+	GroupID     string    `json:"group_id"`
+	WID         string    `json:"wid"`
+	Manager     bool      `json:"manager"`
+	Rate        float64   `json:"rate"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+// NewProjectGroup contains information needed to assign a Group to a project.
+type NewProjectGroup struct {
+	PID     string  `json:"pid" validate:"required,uuid"`
+	Manager bool    `json:"manager"`
+	Rate    float64 `json:"rate" validate:"gte=0"`
+}
+
 // =============================================================================
 
 func toGroup(dbGroup db.Group) Group {
@@ -47,3 +77,29 @@ func toGroupSlice(dbGroup []db.Group) []Group {
 	}
 	return groups
 }
+
+func toMember(dbMember db.Member) Member {
+	pu := (*Member)(unsafe.Pointer(&dbMember))
+	return *pu
+}
+
+func toMemberSlice(dbMembers []db.Member) []Member {
+	members := make([]Member, len(dbMembers))
+	for i, dbMember := range dbMembers {
+		members[i] = toMember(dbMember)
+	}
+	return members
+}
+
+func toProjectGroup(dbProjectGroup db.ProjectGroup) ProjectGroup {
+	pu := (*ProjectGroup)(unsafe.Pointer(&dbProjectGroup))
+	return *pu
+}
+
+func toProjectGroupSlice(dbProjectGroups []db.ProjectGroup) []ProjectGroup {
+	pgs := make([]ProjectGroup, len(dbProjectGroups))
+	for i, dbProjectGroup := range dbProjectGroups {
+		pgs[i] = toProjectGroup(dbProjectGroup)
+	}
+	return pgs
+}
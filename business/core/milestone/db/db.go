@@ -0,0 +1,290 @@
+// Package db contains milestone related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for milestone access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new milestone into the database.
+func (s Store) Create(ctx context.Context, milestone Milestone) error {
+	const q = `
+	INSERT INTO milestones
+		(milestone_id, pid, wid, name, target_date, budget, date_created, date_updated)
+	VALUES
+		(:milestone_id, :pid, :wid, :name, :target_date, :budget, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, milestone); err != nil {
+		return fmt.Errorf("inserting milestone: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a milestone document in the database.
+func (s Store) Update(ctx context.Context, milestone Milestone) error {
+	const q = `
+	UPDATE
+		milestones
+	SET
+		"name" = :name,
+		"target_date" = :target_date,
+		"budget" = :budget,
+		"date_updated" = :date_updated
+	WHERE
+		milestone_id = :milestone_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, milestone); err != nil {
+		return fmt.Errorf("updating milestoneID[%s]: %w", milestone.ID, err)
+	}
+
+	return nil
+}
+
+// Delete removes a milestone from the database.
+func (s Store) Delete(ctx context.Context, milestoneID string) error {
+	data := struct {
+		MilestoneID string `db:"milestone_id"`
+	}{
+		MilestoneID: milestoneID,
+	}
+
+	const q = `
+	DELETE FROM
+		milestones
+	WHERE
+		milestone_id = :milestone_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting milestoneID[%s]: %w", milestoneID, err)
+	}
+
+	return nil
+}
+
+// CreateTask inserts a task at its position in a milestone into the database.
+func (s Store) CreateTask(ctx context.Context, milestoneTask MilestoneTask) error {
+	const q = `
+	INSERT INTO milestone_tasks
+		(task_id, milestone_id, position)
+	VALUES
+		(:task_id, :milestone_id, :position)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, milestoneTask); err != nil {
+		return fmt.Errorf("inserting milestone task: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTasks removes the tasks of a milestone from the database, together
+// with the given tasks wherever milestone they belong to, since a task is
+// part of one milestone at most.
+func (s Store) DeleteTasks(ctx context.Context, milestoneID string, taskIDs []string) error {
+	data := struct {
+		MilestoneID string         `db:"milestone_id"`
+		TaskIDs     pq.StringArray `db:"task_ids"`
+	}{
+		MilestoneID: milestoneID,
+		TaskIDs:     taskIDs,
+	}
+
+	const q = `
+	DELETE FROM
+		milestone_tasks
+	WHERE
+		milestone_id = :milestone_id OR CAST(task_id AS text) = ANY(:task_ids)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting milestone tasks milestoneID[%s]: %w", milestoneID, err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified milestone from the database.
+func (s Store) QueryByID(ctx context.Context, milestoneID string) (Milestone, error) {
+	data := struct {
+		MilestoneID string `db:"milestone_id"`
+	}{
+		MilestoneID: milestoneID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		milestones
+	WHERE
+		milestone_id = :milestone_id`
+
+	var milestone Milestone
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &milestone); err != nil {
+		return Milestone{}, fmt.Errorf("selecting milestoneID[%q]: %w", milestoneID, err)
+	}
+
+	return milestone, nil
+}
+
+// QueryProjectMilestones retrieves the milestones of a project ordered by
+// their target date from the database.
+func (s Store) QueryProjectMilestones(ctx context.Context, projectID string) ([]Milestone, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		milestones
+	WHERE
+		pid = :project_id
+	ORDER BY
+		target_date, name`
+
+	var milestones []Milestone
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &milestones); err != nil {
+		return nil, fmt.Errorf("selecting milestones: %w", err)
+	}
+
+	return milestones, nil
+}
+
+// QueryProjectTasks retrieves the tasks of every milestone of a project in
+// their position order from the database.
+func (s Store) QueryProjectTasks(ctx context.Context, projectID string) ([]MilestoneTask, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		mt.*
+	FROM
+		milestone_tasks AS mt
+	JOIN
+		milestones AS m ON m.milestone_id = mt.milestone_id
+	WHERE
+		m.pid = :project_id
+	ORDER BY
+		mt.milestone_id, mt.position`
+
+	var milestoneTasks []MilestoneTask
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &milestoneTasks); err != nil {
+		return nil, fmt.Errorf("selecting milestone tasks: %w", err)
+	}
+
+	return milestoneTasks, nil
+}
+
+// QueryProjectProgress retrieves the estimated and tracked time and the open
+// and done task counts of every milestone of a project from the database. A
+// task is done once it reaches the last status of its workspace workflow.
+func (s Store) QueryProjectProgress(ctx context.Context, projectID string) ([]Progress, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		m.milestone_id,
+		COALESCE(SUM(t.estimated_seconds), 0) AS estimated,
+		COALESCE(SUM(t.tracked_seconds), 0) AS tracked,
+		COUNT(t.task_id) FILTER (WHERE t.status <> COALESCE(w.task_statuses[array_length(w.task_statuses, 1)], 'done')) AS open_tasks,
+		COUNT(t.task_id) FILTER (WHERE t.status = COALESCE(w.task_statuses[array_length(w.task_statuses, 1)], 'done')) AS done_tasks
+	FROM
+		milestones AS m
+	LEFT JOIN
+		milestone_tasks AS mt ON mt.milestone_id = m.milestone_id
+	LEFT JOIN
+		tasks AS t ON t.task_id = mt.task_id
+	LEFT JOIN
+		workspaces AS w ON w.workspace_id = m.wid
+	WHERE
+		m.pid = :project_id
+	GROUP BY
+		m.milestone_id`
+
+	var progress []Progress
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &progress); err != nil {
+		return nil, fmt.Errorf("selecting milestone progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// CountProjectTasks counts how many of the given tasks belong to the project
+// in the database.
+func (s Store) CountProjectTasks(ctx context.Context, projectID string, taskIDs []string) (int, error) {
+	data := struct {
+		ProjectID string         `db:"project_id"`
+		TaskIDs   pq.StringArray `db:"task_ids"`
+	}{
+		ProjectID: projectID,
+		TaskIDs:   taskIDs,
+	}
+
+	const q = `
+	SELECT
+		COUNT(*) AS count
+	FROM
+		tasks
+	WHERE
+		pid = :project_id AND CAST(task_id AS text) = ANY(:task_ids)`
+
+	var result struct {
+		Count int `db:"count"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return 0, fmt.Errorf("counting project tasks projectID[%q]: %w", projectID, err)
+	}
+
+	return result.Count, nil
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Milestone represent the structure we need for moving data
+// between the app and the database.
+type Milestone struct {
+	ID          string         `db:"milestone_id"`
+	PID         string         `db:"pid"`
+	WID         string         `db:"wid"`
+	Name        string         `db:"name"`
+	TargetDate  time.Time      `db:"target_date"`
+	Budget      float64        `db:"budget"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+	Tasks       pq.StringArray `db:"-"`
+}
+
+// MilestoneTask represent the structure we need for moving data
+// between the app and the database.
+type MilestoneTask struct {
+	TaskID      string `db:"task_id"`
+	MilestoneID string `db:"milestone_id"`
+	Position    int    `db:"position"`
+}
+
+// Progress represent the structure we need for moving data
+// between the app and the database.
+type Progress struct {
+	MilestoneID string        `db:"milestone_id"`
+	Estimated   time.Duration `db:"estimated"`
+	Tracked     time.Duration `db:"tracked"`
+	OpenTasks   int           `db:"open_tasks"`
+	DoneTasks   int           `db:"done_tasks"`
+}
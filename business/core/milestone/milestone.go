@@ -0,0 +1,313 @@
+// Package milestone provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package milestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/milestone/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound    = errors.New("milestone not found")
+	ErrInvalidID   = errors.New("ID is not in its proper form")
+	ErrInvalidTask = errors.New("task does not belong to the milestone project")
+)
+
+// Core manages the set of APIs for milestone access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for milestone api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// Create inserts a new milestone of a project into the database.
+func (c Core) Create(ctx context.Context, workspaceID string, nm NewMilestone, now time.Time) (Milestone, error) {
+	if err := validate.Check(nm); err != nil {
+		return Milestone{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Milestone{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(nm.PID); err != nil {
+		return Milestone{}, ErrInvalidID
+	}
+
+	if err := c.checkTasks(ctx, nm.PID, nm.Tasks); err != nil {
+		return Milestone{}, err
+	}
+
+	dbMilestone := db.Milestone{
+		ID:          validate.GenerateID(),
+		PID:         nm.PID,
+		WID:         workspaceID,
+		Name:        nm.Name,
+		TargetDate:  nm.TargetDate,
+		Budget:      nm.Budget,
+		DateCreated: now,
+		DateUpdated: now,
+		Tasks:       append([]string{}, nm.Tasks...),
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		if err := store.Create(ctx, dbMilestone); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return setTasks(ctx, store, dbMilestone.ID, dbMilestone.Tasks)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return Milestone{}, fmt.Errorf("tran: %w", err)
+	}
+
+	return toMilestone(dbMilestone), nil
+}
+
+// Update replaces a milestone document in the database. When tasks are given
+// they replace the ordered task list of the milestone.
+func (c Core) Update(ctx context.Context, milestoneID string, um UpdateMilestone, now time.Time) error {
+	if err := validate.CheckID(milestoneID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(um); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbMilestone, err := c.store.QueryByID(ctx, milestoneID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("updating milestone milestoneID[%s]: %w", milestoneID, err)
+	}
+
+	if um.Tasks != nil {
+		if err := c.checkTasks(ctx, dbMilestone.PID, um.Tasks); err != nil {
+			return err
+		}
+	}
+
+	if um.Name != nil {
+		dbMilestone.Name = *um.Name
+	}
+	if um.TargetDate != nil {
+		dbMilestone.TargetDate = *um.TargetDate
+	}
+	if um.Budget != nil {
+		dbMilestone.Budget = *um.Budget
+	}
+	dbMilestone.DateUpdated = now
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		if err := store.Update(ctx, dbMilestone); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+
+		if um.Tasks == nil {
+			return nil
+		}
+
+		return setTasks(ctx, store, dbMilestone.ID, um.Tasks)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a milestone from the database. Its tasks are kept.
+func (c Core) Delete(ctx context.Context, milestoneID string) error {
+	if err := validate.CheckID(milestoneID); err != nil {
+		return ErrInvalidID
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		if err := store.DeleteTasks(ctx, milestoneID, nil); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if err := store.Delete(ctx, milestoneID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified milestone with its ordered tasks from the
+// database.
+func (c Core) QueryByID(ctx context.Context, milestoneID string) (Milestone, error) {
+	if err := validate.CheckID(milestoneID); err != nil {
+		return Milestone{}, ErrInvalidID
+	}
+
+	dbMilestone, err := c.store.QueryByID(ctx, milestoneID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Milestone{}, ErrNotFound
+		}
+		return Milestone{}, fmt.Errorf("query: %w", err)
+	}
+
+	milestones, err := c.withTasks(ctx, dbMilestone.PID, []db.Milestone{dbMilestone})
+	if err != nil {
+		return Milestone{}, err
+	}
+
+	return milestones[0], nil
+}
+
+// QueryProgress gets the progress of the specified milestone.
+func (c Core) QueryProgress(ctx context.Context, milestoneID string) (Progress, error) {
+	mls, err := c.QueryByID(ctx, milestoneID)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	dbProgress, err := c.store.QueryProjectProgress(ctx, mls.PID)
+	if err != nil {
+		return Progress{}, fmt.Errorf("query: %w", err)
+	}
+
+	for _, p := range dbProgress {
+		if p.MilestoneID == mls.ID {
+			return toProgress(mls, p), nil
+		}
+	}
+
+	return toProgress(mls, db.Progress{}), nil
+}
+
+// QueryTimeline retrieves the milestones of a project ordered by their target
+// date together with their progress.
+func (c Core) QueryTimeline(ctx context.Context, projectID string) ([]Progress, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	dbMilestones, err := c.store.QueryProjectMilestones(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	milestones, err := c.withTasks(ctx, projectID, dbMilestones)
+	if err != nil {
+		return nil, err
+	}
+
+	dbProgress, err := c.store.QueryProjectProgress(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	byID := make(map[string]db.Progress, len(dbProgress))
+	for _, p := range dbProgress {
+		byID[p.MilestoneID] = p
+	}
+
+	timeline := make([]Progress, len(milestones))
+	for i, mls := range milestones {
+		timeline[i] = toProgress(mls, byID[mls.ID])
+	}
+
+	return timeline, nil
+}
+
+// =============================================================================
+
+// checkTasks makes sure every task belongs to the project of the milestone.
+func (c Core) checkTasks(ctx context.Context, projectID string, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	for _, taskID := range taskIDs {
+		if err := validate.CheckID(taskID); err != nil {
+			return ErrInvalidID
+		}
+	}
+
+	count, err := c.store.CountProjectTasks(ctx, projectID, taskIDs)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if count != len(taskIDs) {
+		return ErrInvalidTask
+	}
+
+	return nil
+}
+
+// withTasks loads the ordered tasks of the milestones of a project.
+func (c Core) withTasks(ctx context.Context, projectID string, dbMilestones []db.Milestone) ([]Milestone, error) {
+	dbTasks, err := c.store.QueryProjectTasks(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	tasks := make(map[string][]string)
+	for _, dbTask := range dbTasks {
+		tasks[dbTask.MilestoneID] = append(tasks[dbTask.MilestoneID], dbTask.TaskID)
+	}
+
+	for i := range dbMilestones {
+		dbMilestones[i].Tasks = append([]string{}, tasks[dbMilestones[i].ID]...)
+	}
+
+	return toMilestoneSlice(dbMilestones), nil
+}
+
+// setTasks replaces the tasks of a milestone keeping their order. Tasks that
+// were part of another milestone are moved to this one.
+func setTasks(ctx context.Context, store db.Store, milestoneID string, taskIDs []string) error {
+	if err := store.DeleteTasks(ctx, milestoneID, taskIDs); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	for i, taskID := range taskIDs {
+		dbTask := db.MilestoneTask{
+			TaskID:      taskID,
+			MilestoneID: milestoneID,
+			Position:    i,
+		}
+
+		if err := store.CreateTask(ctx, dbTask); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package milestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestMilestone(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testmilestone")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	const (
+		workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+		projectID   = "45cf87a3-5915-4079-a9af-6c559239ddbf"
+		task1       = "346efd40-6d6e-46d5-b60b-5db9fc171779"
+		task2       = "4ea20d73-a11e-4e83-b95c-ba8b4b5ff6c1"
+	)
+
+	t.Log("Given the need to work with Milestone records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a single Milestone.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			nm := NewMilestone{
+				PID:        projectID,
+				Name:       "Alpha",
+				TargetDate: time.Date(2021, time.November, 1, 0, 0, 0, 0, time.UTC),
+				Budget:     500,
+				Tasks:      []string{task1, task2},
+			}
+
+			mls, err := core.Create(ctx, workspaceID, nm, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create Milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create Milestone.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, mls.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve Milestone by ID: %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve Milestone by ID.", dbtest.Success, testID)
+
+			if diff := cmp.Diff(mls, saved); diff != "" {
+				t.Errorf("\t%s\tTest %d:\tShould get back the same Milestone. Diff:\n%s", dbtest.Failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get back the same Milestone.", dbtest.Success, testID)
+
+			upd := UpdateMilestone{
+				Name:  dbtest.StringPointer("Beta"),
+				Tasks: []string{task2, task1},
+			}
+
+			if err := core.Update(ctx, mls.ID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update Milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to update Milestone.", dbtest.Success, testID)
+
+			saved, err = core.QueryByID(ctx, mls.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve updated Milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve updated Milestone.", dbtest.Success, testID)
+
+			if saved.Name != *upd.Name {
+				t.Errorf("\t%s\tTest %d:\tShould be able to see updates to Name.", dbtest.Failed, testID)
+				t.Logf("\t\tTest %d:\tGot: %v", testID, saved.Name)
+				t.Logf("\t\tTest %d:\tExp: %v", testID, *upd.Name)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould be able to see updates to Name.", dbtest.Success, testID)
+			}
+
+			if diff := cmp.Diff(upd.Tasks, saved.Tasks); diff != "" {
+				t.Errorf("\t%s\tTest %d:\tShould keep the order of the tasks. Diff:\n%s", dbtest.Failed, testID, diff)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould keep the order of the tasks.", dbtest.Success, testID)
+			}
+
+			bad := UpdateMilestone{
+				Tasks: []string{"0c1b8c6a-2f33-4e1c-a6a7-3a0c9d6f5e11"},
+			}
+			if err := core.Update(ctx, mls.ID, bad, now); !errors.Is(err, ErrInvalidTask) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to add a task of another project : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to add a task of another project.", dbtest.Success, testID)
+
+			if err := core.Delete(ctx, mls.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete Milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to delete Milestone.", dbtest.Success, testID)
+
+			_, err = core.QueryByID(ctx, mls.ID)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve Milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to retrieve Milestone.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testtimeline")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	const (
+		workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+		projectID   = "45cf87a3-5915-4079-a9af-6c559239ddbf"
+		task1       = "346efd40-6d6e-46d5-b60b-5db9fc171779"
+		task2       = "4ea20d73-a11e-4e83-b95c-ba8b4b5ff6c1"
+	)
+
+	t.Log("Given the need to follow the progress of a project.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a done and an open task in two milestones.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const q = `
+			UPDATE tasks SET status = 'done', estimated_seconds = 600000000, tracked_seconds = 900000000
+			WHERE task_id = '346efd40-6d6e-46d5-b60b-5db9fc171779';
+			UPDATE tasks SET status = 'todo', estimated_seconds = 1200000000, tracked_seconds = 300000000
+			WHERE task_id = '4ea20d73-a11e-4e83-b95c-ba8b4b5ff6c1'`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update tasks : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to update tasks.", dbtest.Success, testID)
+
+			later, err := core.Create(ctx, workspaceID, NewMilestone{
+				PID:        projectID,
+				Name:       "Launch",
+				TargetDate: time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC),
+				Tasks:      []string{task1, task2},
+			}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create Milestone : %s.", dbtest.Failed, testID, err)
+			}
+
+			sooner, err := core.Create(ctx, workspaceID, NewMilestone{
+				PID:        projectID,
+				Name:       "Design",
+				TargetDate: time.Date(2021, time.November, 1, 0, 0, 0, 0, time.UTC),
+				Tasks:      []string{task1},
+			}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create Milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create Milestones.", dbtest.Success, testID)
+
+			progress, err := core.QueryProgress(ctx, later.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve progress : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve progress.", dbtest.Success, testID)
+
+			if progress.OpenTasks != 1 || progress.DoneTasks != 0 || progress.Estimated != 1200*time.Millisecond || progress.Tracked != 300*time.Millisecond {
+				t.Errorf("\t%s\tTest %d:\tShould move the task out of the other milestone : %+v.", dbtest.Failed, testID, progress)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould move the task out of the other milestone.", dbtest.Success, testID)
+			}
+
+			timeline, err := core.QueryTimeline(ctx, projectID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve timeline : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve timeline.", dbtest.Success, testID)
+
+			if len(timeline) != 2 || timeline[0].ID != sooner.ID || timeline[1].ID != later.ID {
+				t.Fatalf("\t%s\tTest %d:\tShould order the milestones by target date : %+v.", dbtest.Failed, testID, timeline)
+			}
+			t.Logf("\t%s\tTest %d:\tShould order the milestones by target date.", dbtest.Success, testID)
+
+			if timeline[0].DoneTasks != 1 || timeline[0].OpenTasks != 0 || timeline[0].Tracked != 900*time.Millisecond {
+				t.Errorf("\t%s\tTest %d:\tShould count the done task : %+v.", dbtest.Failed, testID, timeline[0])
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould count the done task.", dbtest.Success, testID)
+			}
+		}
+	}
+}
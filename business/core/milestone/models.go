@@ -0,0 +1,79 @@
+package milestone
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/milestone/db"
+)
+
+// Milestone represents a phase of a project with a target date, an optional
+// budget and an ordered list of tasks.
+type Milestone struct {
+	ID          string    `json:"id"`
+	PID         string    `json:"pid"`
+	WID         string    `json:"wid"`
+	Name        string    `json:"name"`
+	TargetDate  time.Time `json:"target_date"`
+	Budget      float64   `json:"budget"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+	Tasks       []string  `json:"tasks"`
+}
+
+// NewMilestone contains information needed to create a new milestone.
+type NewMilestone struct {
+	PID        string    `json:"pid" validate:"required"`
+	Name       string    `json:"name" validate:"required"`
+	TargetDate time.Time `json:"target_date" validate:"required"`
+	Budget     float64   `json:"budget" validate:"gte=0"`
+	Tasks      []string  `json:"tasks" validate:"unique"`
+}
+
+// UpdateMilestone defines what information may be provided to modify an existing
+// milestone. All fields are optional so milestones can send just the fields they want
+// changed. It uses pointer fields ,so we can differentiate between a field that
+// was not provided and a field that was provided as explicitly blank. Normally
+// we do not want to use pointers to basic types ,but we make exceptions around
+// marshalling/unmarshalling. Tasks replaces the ordered task list when given.
+type UpdateMilestone struct {
+	Name       *string    `json:"name"`
+	TargetDate *time.Time `json:"target_date"`
+	Budget     *float64   `json:"budget" validate:"omitempty,gte=0"`
+	Tasks      []string   `json:"tasks" validate:"omitempty,unique"`
+}
+
+// Progress represents how far a milestone is, comparing the time tracked on
+// its tasks with their estimates and counting its open and done tasks.
+type Progress struct {
+	Milestone
+	Estimated time.Duration `json:"estimated"`
+	Tracked   time.Duration `json:"tracked"`
+	OpenTasks int           `json:"open_tasks"`
+	DoneTasks int           `json:"done_tasks"`
+}
+
+// =============================================================================
+
+func toMilestone(dbMilestone db.Milestone) Milestone {
+	pu := (*Milestone)(unsafe.Pointer(&dbMilestone))
+	return *pu
+}
+
+func toMilestoneSlice(dbMilestones []db.Milestone) []Milestone {
+	milestones := make([]Milestone, len(dbMilestones))
+	for i, dbMilestone := range dbMilestones {
+		milestones[i] = toMilestone(dbMilestone)
+	}
+	return milestones
+}
+
+func toProgress(milestone Milestone, dbProgress db.Progress) Progress {
+	return Progress{
+		Milestone: milestone,
+		Estimated: dbProgress.Estimated,
+		Tracked:   dbProgress.Tracked,
+		OpenTasks: dbProgress.OpenTasks,
+		DoneTasks: dbProgress.DoneTasks,
+	}
+}
@@ -44,13 +44,34 @@ func (s Store) Tran(tx sqlx.ExtContext) Store {
 	}
 }
 
+// visible filters the projects a user may see. Private projects are only
+// visible to the members of their team, directly or through a group, and to the
+// workspace admins.
+const visible = `(
+		projects.is_private IS NOT TRUE
+		OR EXISTS (SELECT 1 FROM project_members WHERE project_members.pid = projects.project_id AND project_members.uid = :user_id)
+		OR EXISTS (SELECT 1 FROM workspace_users WHERE workspace_users.wid = projects.wid AND workspace_users.uid = :user_id AND workspace_users.admin)
+	)`
+
+// VisibleThrough returns the filter of the rows of another table whose
+// project, referenced by column, the user may see. Rows without a project are
+// always visible.
+func VisibleThrough(column string) string {
+	return `NOT EXISTS (
+		SELECT 1 FROM projects
+		WHERE projects.project_id = ` + column + ` AND NOT ` + visible + `
+	)`
+}
+
 // Create inserts a new project into the database.
 func (s Store) Create(ctx context.Context, project Project) error {
 	const q = `
 	INSERT INTO projects
-		(project_id, name, wid, cid, uid, active, is_private, billable, auto_estimates, estimated_hours, date_updated, rate, date_created, hex_color)
+		(project_id, name, wid, cid, uid, active, is_private, billable, auto_estimates, estimated_hours, date_updated, rate, date_created, hex_color, budget,
+		 billing_model, fixed_fee, retainer_fee, retainer_hours, overage_rate, rollover, status)
 	VALUES
-		(:project_id, :name, :wid, :cid, :uid, :active, :is_private, :billable, :auto_estimates, :estimated_hours, :date_updated, :rate, :date_created, :hex_color)`
+		(:project_id, :name, :wid, :cid, :uid, :active, :is_private, :billable, :auto_estimates, :estimated_hours, :date_updated, :rate, :date_created, :hex_color, :budget,
+		 :billing_model, :fixed_fee, :retainer_fee, :retainer_hours, :overage_rate, :rollover, :status)`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, project); err != nil {
 		return fmt.Errorf("inserting project: %w", err)
@@ -59,7 +80,8 @@ func (s Store) Create(ctx context.Context, project Project) error {
 	return nil
 }
 
-// Update replaces a project document in the database.
+// Update replaces a project document in the database. A project whose status
+// changes no longer follows its client out of the archive.
 func (s Store) Update(ctx context.Context, project Project) error {
 	const q = `
 	UPDATE
@@ -73,7 +95,16 @@ func (s Store) Update(ctx context.Context, project Project) error {
 		"estimated_hours" = :estimated_hours,
 		"rate" = :rate,
 		"date_updated" = :date_updated,
-		"hex_color" = :hex_color
+		"hex_color" = :hex_color,
+		"budget" = :budget,
+		"billing_model" = :billing_model,
+		"fixed_fee" = :fixed_fee,
+		"retainer_fee" = :retainer_fee,
+		"retainer_hours" = :retainer_hours,
+		"overage_rate" = :overage_rate,
+		"rollover" = :rollover,
+		"status" = :status,
+		"previous_status" = CASE WHEN status = :status THEN previous_status END
 	WHERE
 		project_id = :project_id`
 
@@ -84,6 +115,72 @@ func (s Store) Update(ctx context.Context, project Project) error {
 	return nil
 }
 
+// UpdateStatus changes the lifecycle state of a project in the database. The
+// project no longer follows its client out of the archive.
+func (s Store) UpdateStatus(ctx context.Context, project Project) error {
+	const q = `
+	UPDATE
+		projects
+	SET
+		"status" = :status,
+		"active" = :active,
+		"previous_status" = NULL,
+		"date_updated" = :date_updated
+	WHERE
+		project_id = :project_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, project); err != nil {
+		return fmt.Errorf("updating status projectID[%s]: %w", project.ID, err)
+	}
+
+	return nil
+}
+
+// Move reassigns a project to its workspace and client in the database. The
+// tasks, team, milestones, account codes and expenses of the project follow it
+// into the workspace, the groups of another workspace are unassigned.
+func (s Store) Move(ctx context.Context, project Project) error {
+	const q = `
+	UPDATE
+		projects
+	SET
+		"wid" = :wid,
+		"cid" = :cid,
+		"date_updated" = :date_updated
+	WHERE
+		project_id = :project_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, project); err != nil {
+		return fmt.Errorf("moving projectID[%s]: %w", project.ID, err)
+	}
+
+	for _, table := range []string{"tasks", "teams", "milestones", "account_codes", "expenses"} {
+		q := `
+		UPDATE
+			` + table + `
+		SET
+			"wid" = :wid
+		WHERE
+			pid = :project_id`
+
+		if err := database.NamedExecContext(ctx, s.log, s.db, q, project); err != nil {
+			return fmt.Errorf("moving %s of projectID[%s]: %w", table, project.ID, err)
+		}
+	}
+
+	const qg = `
+	DELETE FROM
+		project_groups
+	WHERE
+		pid = :project_id AND wid <> :wid`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, qg, project); err != nil {
+		return fmt.Errorf("unassigning groups of projectID[%s]: %w", project.ID, err)
+	}
+
+	return nil
+}
+
 // Delete removes a project from the database.
 func (s Store) Delete(ctx context.Context, projectID string) error {
 	data := struct {
@@ -129,44 +226,82 @@ func (s Store) QueryByID(ctx context.Context, projectID string) (Project, error)
 	return projct, nil
 }
 
-// QueryUnique gets the specified project from the database.
-func (s Store) QueryUnique(ctx context.Context, name, column, id string) string {
+// QueryAccess reports whether the user may see and use the specified project.
+func (s Store) QueryAccess(ctx context.Context, projectID, userID string) (bool, error) {
 	data := struct {
-		Name   string `db:"name"`
-		Column string `db:"column"`
-		ID     string `db:"id"`
+		ProjectID string `db:"project_id"`
+		UserID    string `db:"user_id"`
 	}{
-		Name:   name,
-		Column: column,
-		ID:     id,
+		ProjectID: projectID,
+		UserID:    userID,
 	}
 
 	const q = `
+	SELECT
+		` + visible + ` AS access
+	FROM
+		projects
+	WHERE
+		project_id = :project_id`
+
+	var result struct {
+		Access bool `db:"access"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return false, fmt.Errorf("selecting access projectID[%q]: %w", projectID, err)
+	}
+
+	return result.Access, nil
+}
+
+// QueryUnique gets the name of the project with the given name in the
+// workspace or client of the column. The column is only ever one of "wid" or
+// "cid", so it goes into the query text.
+func (s Store) QueryUnique(ctx context.Context, name, column, id string) string {
+	if column != "wid" && column != "cid" {
+		return ""
+	}
+
+	data := struct {
+		Name string `db:"name"`
+		ID   string `db:"id"`
+	}{
+		Name: name,
+		ID:   id,
+	}
+
+	q := `
 	SELECT
 		name
 	FROM
 		projects
 	WHERE 
-		:column = :id AND name = :name`
+		` + column + ` = :id AND name = :name`
 
-	var nam string
-	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &nam); err != nil {
+	var result struct {
+		Name string `db:"name"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
 		return ""
 	}
 
-	return nam
+	return result.Name
 }
 
 // QueryClientProjects retrieves a list of existing projects from the database.
-func (s Store) QueryClientProjects(ctx context.Context, clientID string, pageNumber, rowsPerPage int) ([]Project, error) {
+func (s Store) QueryClientProjects(ctx context.Context, clientID, userID string, includeArchived bool, pageNumber, rowsPerPage int) ([]Project, error) {
 	data := struct {
-		Offset      int    `db:"offset"`
-		RowsPerPage int    `db:"rows_per_page"`
-		ClientID    string `db:"client_id"`
+		Offset          int    `db:"offset"`
+		RowsPerPage     int    `db:"rows_per_page"`
+		ClientID        string `db:"client_id"`
+		UserID          string `db:"user_id"`
+		IncludeArchived bool   `db:"include_archived"`
 	}{
-		Offset:      (pageNumber - 1) * rowsPerPage,
-		RowsPerPage: rowsPerPage,
-		ClientID:    clientID,
+		Offset:          (pageNumber - 1) * rowsPerPage,
+		RowsPerPage:     rowsPerPage,
+		ClientID:        clientID,
+		UserID:          userID,
+		IncludeArchived: includeArchived,
 	}
 
 	const q = `
@@ -175,7 +310,7 @@ func (s Store) QueryClientProjects(ctx context.Context, clientID string, pageNum
 	FROM
 		projects
 	WHERE 
-		cid = :client_id
+		cid = :client_id AND (:include_archived OR status <> 'archived') AND ` + visible + `
 	ORDER BY
 		name
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -189,15 +324,19 @@ func (s Store) QueryClientProjects(ctx context.Context, clientID string, pageNum
 }
 
 // QueryWorkspaceProjects retrieves a list of existing project from the database.
-func (s Store) QueryWorkspaceProjects(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Project, error) {
+func (s Store) QueryWorkspaceProjects(ctx context.Context, workspaceID, userID string, includeArchived bool, pageNumber, rowsPerPage int) ([]Project, error) {
 	data := struct {
-		Offset      int    `db:"offset"`
-		RowsPerPage int    `db:"rows_per_page"`
-		WorkspaceID string `db:"workspace_id"`
+		Offset          int    `db:"offset"`
+		RowsPerPage     int    `db:"rows_per_page"`
+		WorkspaceID     string `db:"workspace_id"`
+		UserID          string `db:"user_id"`
+		IncludeArchived bool   `db:"include_archived"`
 	}{
-		Offset:      (pageNumber - 1) * rowsPerPage,
-		RowsPerPage: rowsPerPage,
-		WorkspaceID: workspaceID,
+		Offset:          (pageNumber - 1) * rowsPerPage,
+		RowsPerPage:     rowsPerPage,
+		WorkspaceID:     workspaceID,
+		UserID:          userID,
+		IncludeArchived: includeArchived,
 	}
 
 	const q = `
@@ -206,7 +345,7 @@ func (s Store) QueryWorkspaceProjects(ctx context.Context, workspaceID string, p
 	FROM
 		projects
 	WHERE
-		wid = :workspace_id and is_private = false
+		wid = :workspace_id AND (:include_archived OR status <> 'archived') AND ` + visible + `
 	ORDER BY
 		project_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -220,15 +359,17 @@ func (s Store) QueryWorkspaceProjects(ctx context.Context, workspaceID string, p
 }
 
 // QueryUserProjects retrieves a list of existing projects from the database.
-func (s Store) QueryUserProjects(ctx context.Context, userID string, pageNumber, rowsPerPage int) ([]Project, error) {
+func (s Store) QueryUserProjects(ctx context.Context, userID string, includeArchived bool, pageNumber, rowsPerPage int) ([]Project, error) {
 	data := struct {
-		Offset      int    `db:"offset"`
-		RowsPerPage int    `db:"rows_per_page"`
-		UserID      string `db:"user_id"`
+		Offset          int    `db:"offset"`
+		RowsPerPage     int    `db:"rows_per_page"`
+		UserID          string `db:"user_id"`
+		IncludeArchived bool   `db:"include_archived"`
 	}{
-		Offset:      (pageNumber - 1) * rowsPerPage,
-		RowsPerPage: rowsPerPage,
-		UserID:      userID,
+		Offset:          (pageNumber - 1) * rowsPerPage,
+		RowsPerPage:     rowsPerPage,
+		UserID:          userID,
+		IncludeArchived: includeArchived,
 	}
 
 	const q = `
@@ -237,7 +378,7 @@ func (s Store) QueryUserProjects(ctx context.Context, userID string, pageNumber,
 	FROM
 		projects
 	WHERE 
-		uid = :user_id
+		uid = :user_id AND (:include_archived OR status <> 'archived') AND ` + visible + `
 	ORDER BY
 		project_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -249,3 +390,177 @@ func (s Store) QueryUserProjects(ctx context.Context, userID string, pageNumber,
 
 	return projcts, nil
 }
+
+// CreateTemplate inserts a new project template into the database.
+func (s Store) CreateTemplate(ctx context.Context, template Template) error {
+	const q = `
+	INSERT INTO project_templates
+		(template_id, wid, uid, name, is_private, billable, auto_estimates, estimated_hours, rate, hex_color, budget,
+		 billing_model, fixed_fee, retainer_fee, retainer_hours, overage_rate, rollover, date_created, date_updated)
+	VALUES
+		(:template_id, :wid, :uid, :name, :is_private, :billable, :auto_estimates, :estimated_hours, :rate, :hex_color, :budget,
+		 :billing_model, :fixed_fee, :retainer_fee, :retainer_hours, :overage_rate, :rollover, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, template); err != nil {
+		return fmt.Errorf("inserting template: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTemplateTask inserts a new template task into the database.
+func (s Store) CreateTemplateTask(ctx context.Context, task TemplateTask) error {
+	const q = `
+	INSERT INTO template_tasks
+		(template_task_id, template_id, name, estimated_seconds)
+	VALUES
+		(:template_task_id, :template_id, :name, :estimated_seconds)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, task); err != nil {
+		return fmt.Errorf("inserting template task: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTemplateMember inserts a new template member into the database.
+func (s Store) CreateTemplateMember(ctx context.Context, member TemplateMember) error {
+	const q = `
+	INSERT INTO template_members
+		(template_member_id, template_id, uid, manager, rate)
+	VALUES
+		(:template_member_id, :template_id, :uid, :manager, :rate)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, member); err != nil {
+		return fmt.Errorf("inserting template member: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTemplate removes a project template with its tasks and members from
+// the database.
+func (s Store) DeleteTemplate(ctx context.Context, templateID string) error {
+	data := struct {
+		TemplateID string `db:"template_id"`
+	}{
+		TemplateID: templateID,
+	}
+
+	const qt = `
+	DELETE FROM
+		template_tasks
+	WHERE
+		template_id = :template_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, qt, data); err != nil {
+		return fmt.Errorf("deleting tasks of templateID[%s]: %w", templateID, err)
+	}
+
+	const qm = `
+	DELETE FROM
+		template_members
+	WHERE
+		template_id = :template_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, qm, data); err != nil {
+		return fmt.Errorf("deleting members of templateID[%s]: %w", templateID, err)
+	}
+
+	const q = `
+	DELETE FROM
+		project_templates
+	WHERE
+		template_id = :template_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting templateID[%s]: %w", templateID, err)
+	}
+
+	return nil
+}
+
+// QueryTemplateByID gets the specified project template with its tasks and
+// members from the database.
+func (s Store) QueryTemplateByID(ctx context.Context, templateID string) (Template, error) {
+	data := struct {
+		TemplateID string `db:"template_id"`
+	}{
+		TemplateID: templateID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		project_templates
+	WHERE
+		template_id = :template_id`
+
+	var template Template
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &template); err != nil {
+		return Template{}, fmt.Errorf("selecting templateID[%q]: %w", templateID, err)
+	}
+
+	const qt = `
+	SELECT
+		*
+	FROM
+		template_tasks
+	WHERE
+		template_id = :template_id
+	ORDER BY
+		name`
+
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, qt, data, &template.Tasks); err != nil {
+		return Template{}, fmt.Errorf("selecting tasks of templateID[%q]: %w", templateID, err)
+	}
+
+	const qm = `
+	SELECT
+		*
+	FROM
+		template_members
+	WHERE
+		template_id = :template_id
+	ORDER BY
+		template_member_id`
+
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, qm, data, &template.Members); err != nil {
+		return Template{}, fmt.Errorf("selecting members of templateID[%q]: %w", templateID, err)
+	}
+
+	return template, nil
+}
+
+// QueryWorkspaceTemplates retrieves a list of existing project templates from
+// the database.
+func (s Store) QueryWorkspaceTemplates(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Template, error) {
+	data := struct {
+		Offset      int    `db:"offset"`
+		RowsPerPage int    `db:"rows_per_page"`
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+		WorkspaceID: workspaceID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		project_templates
+	WHERE
+		wid = :workspace_id
+	ORDER BY
+		name
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var templates []Template
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &templates); err != nil {
+		return nil, fmt.Errorf("selecting templates: %w", err)
+	}
+
+	return templates, nil
+}
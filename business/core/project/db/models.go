@@ -1,22 +1,80 @@
 package db
 
-import "time"
+import (
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+)
 
 // Project represent the structure we need for moving data
 // between the app and the database.
 type Project struct {
-	ID             string        `db:"project_id"`
-	Name           string        `db:"name"`
-	WID            string        `db:"wid"`
-	CID            string        `db:"cid"`
-	UID            string        `db:"uid"`
-	Active         bool          `db:"active"`
-	IsPrivate      bool          `db:"is_private"`
-	Billable       bool          `db:"billable"`
-	AutoEstimates  bool          `db:"auto_estimates"`
-	EstimatedHours time.Duration `db:"estimated_hours"`
-	DateCreated    time.Time     `db:"date_created"`
-	DateUpdated    time.Time     `db:"date_updated"`
-	Rate           float32       `db:"rate"`
-	HexColor       string        `db:"hex_color"`
+	ID             string          `db:"project_id"`
+	Name           string          `db:"name"`
+	WID            string          `db:"wid"`
+	CID            database.NullID `db:"cid"`
+	UID            string          `db:"uid"`
+	Active         bool            `db:"active"`
+	IsPrivate      bool            `db:"is_private"`
+	Billable       bool            `db:"billable"`
+	AutoEstimates  bool            `db:"auto_estimates"`
+	EstimatedHours time.Duration   `db:"estimated_hours"`
+	DateCreated    time.Time       `db:"date_created"`
+	DateUpdated    time.Time       `db:"date_updated"`
+	Rate           float32         `db:"rate"`
+	HexColor       string          `db:"hex_color"`
+	Budget         float64         `db:"budget"`
+	BillingModel   string          `db:"billing_model"`
+	FixedFee       float64         `db:"fixed_fee"`
+	RetainerFee    float64         `db:"retainer_fee"`
+	RetainerHours  float64         `db:"retainer_hours"`
+	OverageRate    float64         `db:"overage_rate"`
+	Rollover       bool            `db:"rollover"`
+	Status         string          `db:"status"`
+	PreviousStatus *string         `db:"previous_status"`
+}
+
+// Template represent the structure we need for moving data
+// between the app and the database.
+type Template struct {
+	ID             string           `db:"template_id"`
+	WID            string           `db:"wid"`
+	UID            string           `db:"uid"`
+	Name           string           `db:"name"`
+	IsPrivate      bool             `db:"is_private"`
+	Billable       bool             `db:"billable"`
+	AutoEstimates  bool             `db:"auto_estimates"`
+	EstimatedHours time.Duration    `db:"estimated_hours"`
+	Rate           float32          `db:"rate"`
+	HexColor       string           `db:"hex_color"`
+	Budget         float64          `db:"budget"`
+	BillingModel   string           `db:"billing_model"`
+	FixedFee       float64          `db:"fixed_fee"`
+	RetainerFee    float64          `db:"retainer_fee"`
+	RetainerHours  float64          `db:"retainer_hours"`
+	OverageRate    float64          `db:"overage_rate"`
+	Rollover       bool             `db:"rollover"`
+	DateCreated    time.Time        `db:"date_created"`
+	DateUpdated    time.Time        `db:"date_updated"`
+	Tasks          []TemplateTask   `db:"-"`
+	Members        []TemplateMember `db:"-"`
+}
+
+// TemplateTask represent the structure we need for moving data
+// between the app and the database.
+type TemplateTask struct {
+	ID         string        `db:"template_task_id"`
+	TemplateID string        `db:"template_id"`
+	Name       string        `db:"name"`
+	Estimated  time.Duration `db:"estimated_seconds"`
+}
+
+// TemplateMember represent the structure we need for moving data
+// between the app and the database.
+type TemplateMember struct {
+	ID         string  `db:"template_member_id"`
+	TemplateID string  `db:"template_id"`
+	UID        string  `db:"uid"`
+	Manager    bool    `db:"manager"`
+	Rate       float64 `db:"rate"`
 }
@@ -6,7 +6,30 @@ import (
 	"unsafe"
 )
 
-// Project represents an individual project.
+// Set of billing models a project can use.
+const (
+	BillingHourly   = "hourly"
+	BillingFixedFee = "fixed_fee"
+	BillingRetainer = "retainer"
+)
+
+// Set of lifecycle states a project can be in.
+const (
+	StatusPlanned   = "planned"
+	StatusActive    = "active"
+	StatusOnHold    = "on_hold"
+	StatusCompleted = "completed"
+	StatusArchived  = "archived"
+)
+
+// Project represents an individual project. Hourly projects bill the tracked
+// time by rate. Fixed fee projects bill FixedFee as the estimated hours are
+// used up. Retainer projects bill RetainerFee every month for RetainerHours and
+// the extra hours at OverageRate. Unused hours move to the next month when
+// Rollover is set and are forfeited otherwise. Status holds the lifecycle state
+// of the project and Active is set only while the project is active. Archived
+// projects are hidden from the project lists and take no new time entries.
+// Projects of an archived client are archived as well.
 type Project struct {
 	ID             string        `json:"id"`
 	Name           string        `json:"name"`
@@ -22,6 +45,14 @@ type Project struct {
 	DateUpdated    time.Time     `json:"date_updated"`
 	Rate           float32       `json:"rate"`
 	HexColor       string        `json:"hex_color"`
+	Budget         float64       `json:"budget"`
+	BillingModel   string        `json:"billing_model"`
+	FixedFee       float64       `json:"fixed_fee"`
+	RetainerFee    float64       `json:"retainer_fee"`
+	RetainerHours  float64       `json:"retainer_hours"`
+	OverageRate    float64       `json:"overage_rate"`
+	Rollover       bool          `json:"rollover"`
+	Status         string        `json:"status"`
 }
 
 // NewProject contains information needed to create a new project.
@@ -29,13 +60,20 @@ type NewProject struct {
 	Name           string        `json:"name" validate:"required"`
 	WID            string        `json:"wid"`
 	CID            string        `json:"cid"`
-	Active         bool          `json:"active"`
+	Status         string        `json:"status" validate:"omitempty,oneof=planned active on_hold completed archived"`
 	IsPrivate      bool          `json:"is_private"`
 	AutoEstimates  bool          `json:"auto_estimates"`
 	EstimatedHours time.Duration `json:"estimated_hours"`
 	Billable       bool          `json:"billable"`
 	Rate           float32       `json:"rate"`
 	HexColor       string        `json:"hex_color"`
+	Budget         float64       `json:"budget" validate:"gte=0"`
+	BillingModel   string        `json:"billing_model" validate:"omitempty,oneof=hourly fixed_fee retainer"`
+	FixedFee       float64       `json:"fixed_fee" validate:"gte=0"`
+	RetainerFee    float64       `json:"retainer_fee" validate:"gte=0"`
+	RetainerHours  float64       `json:"retainer_hours" validate:"gte=0"`
+	OverageRate    float64       `json:"overage_rate" validate:"gte=0"`
+	Rollover       bool          `json:"rollover"`
 }
 
 // UpdateProject defines what information may be provided to modify an existing
@@ -47,12 +85,105 @@ type NewProject struct {
 type UpdateProject struct {
 	Name           *string        `json:"name"`
 	Active         *bool          `json:"active"`
+	Status         *string        `json:"status" validate:"omitempty,oneof=planned active on_hold completed archived"`
 	IsPrivate      *bool          `json:"is_private"`
 	AutoEstimates  *bool          `json:"auto_estimates"`
 	EstimatedHours *time.Duration `json:"estimated_hours"`
 	Billable       *bool          `json:"billable"`
 	Rate           *float32       `json:"rate"`
 	HexColor       *string        `json:"hex_color"`
+	Budget         *float64       `json:"budget" validate:"omitempty,gte=0"`
+	BillingModel   *string        `json:"billing_model" validate:"omitempty,oneof=hourly fixed_fee retainer"`
+	FixedFee       *float64       `json:"fixed_fee" validate:"omitempty,gte=0"`
+	RetainerFee    *float64       `json:"retainer_fee" validate:"omitempty,gte=0"`
+	RetainerHours  *float64       `json:"retainer_hours" validate:"omitempty,gte=0"`
+	OverageRate    *float64       `json:"overage_rate" validate:"omitempty,gte=0"`
+	Rollover       *bool          `json:"rollover"`
+}
+
+// Template represents a saved project setup that new projects can be created
+// from. It keeps the tasks with their estimates, the team with its rates and
+// the billing settings of the project it was saved from.
+type Template struct {
+	ID             string           `json:"id"`
+	WID            string           `json:"wid"`
+	UID            string           `json:"uid"`
+	Name           string           `json:"name"`
+	IsPrivate      bool             `json:"is_private"`
+	Billable       bool             `json:"billable"`
+	AutoEstimates  bool             `json:"auto_estimates"`
+	EstimatedHours time.Duration    `json:"estimated_hours"`
+	Rate           float32          `json:"rate"`
+	HexColor       string           `json:"hex_color"`
+	Budget         float64          `json:"budget"`
+	BillingModel   string           `json:"billing_model"`
+	FixedFee       float64          `json:"fixed_fee"`
+	RetainerFee    float64          `json:"retainer_fee"`
+	RetainerHours  float64          `json:"retainer_hours"`
+	OverageRate    float64          `json:"overage_rate"`
+	Rollover       bool             `json:"rollover"`
+	DateCreated    time.Time        `json:"date_created"`
+	DateUpdated    time.Time        `json:"date_updated"`
+	Tasks          []TemplateTask   `json:"tasks"`
+	Members        []TemplateMember `json:"members"`
+}
+
+// TemplateTask represents a task of a project template.
+type TemplateTask struct {
+	ID         string        `json:"id"`
+	TemplateID string        `json:"template_id"`
+	Name       string        `json:"name"`
+	Estimated  time.Duration `json:"estimated_seconds"`
+}
+
+// TemplateMember represents a team member of a project template.
+type TemplateMember struct {
+	ID         string  `json:"id"`
+	TemplateID string  `json:"template_id"`
+	UID        string  `json:"uid"`
+	Manager    bool    `json:"manager"`
+	Rate       float64 `json:"rate"`
+}
+
+// NewTemplate contains information needed to save a project as a template.
+type NewTemplate struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// NewFromTemplate contains information needed to create a project from a
+// template.
+type NewFromTemplate struct {
+	Name string `json:"name" validate:"required"`
+	CID  string `json:"cid" validate:"omitempty,uuid"`
+}
+
+// CloneProject contains information needed to deep copy a project. The copy
+// stays in the workspace and client of the project unless WID or CID is set.
+type CloneProject struct {
+	Name string `json:"name" validate:"required"`
+	WID  string `json:"wid" validate:"omitempty,uuid"`
+	CID  string `json:"cid" validate:"omitempty,uuid"`
+}
+
+// MoveProject contains information needed to move a project to another client
+// or to another workspace of the same owner. A move to another workspace
+// leaves the project without a client unless CID is set. The time entries of
+// the project go along when TimeEntries is set and otherwise stay behind
+// without the project. Confirm commits a move that changes rates or currency.
+type MoveProject struct {
+	WID         string `json:"wid" validate:"omitempty,uuid"`
+	CID         string `json:"cid" validate:"omitempty,uuid"`
+	TimeEntries bool   `json:"time_entries"`
+	Confirm     bool   `json:"confirm"`
+}
+
+// MoveReport describes the outcome of a project move. Mismatches lists the
+// rate and currency changes of the move, which is only committed when there
+// are none or when it was confirmed.
+type MoveReport struct {
+	Project    Project  `json:"project"`
+	Mismatches []string `json:"mismatches"`
+	Moved      bool     `json:"moved"`
 }
 
 // =============================================================================
@@ -69,3 +200,16 @@ func toProjectsSlice(dbProject []db.Project) []Project {
 	}
 	return projects
 }
+
+func toTemplate(dbTemplate db.Template) Template {
+	pu := (*Template)(unsafe.Pointer(&dbTemplate))
+	return *pu
+}
+
+func toTemplatesSlice(dbTemplates []db.Template) []Template {
+	templates := make([]Template, len(dbTemplates))
+	for i, dbTemplate := range dbTemplates {
+		templates[i] = toTemplate(dbTemplate)
+	}
+	return templates
+}
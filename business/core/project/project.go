@@ -7,7 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	dbc "github.com/AhmedShaef/wakt/business/core/client/db"
 	"github.com/AhmedShaef/wakt/business/core/project/db"
+	dbtg "github.com/AhmedShaef/wakt/business/core/tag/db"
+	dbt "github.com/AhmedShaef/wakt/business/core/task/db"
+	dbtm "github.com/AhmedShaef/wakt/business/core/team/db"
+	dbte "github.com/AhmedShaef/wakt/business/core/timeentry/db"
+	dbw "github.com/AhmedShaef/wakt/business/core/workspace/db"
+	dbwu "github.com/AhmedShaef/wakt/business/core/workspaceuser/db"
 	"github.com/AhmedShaef/wakt/business/sys/database"
 	"github.com/AhmedShaef/wakt/business/sys/validate"
 	"github.com/jmoiron/sqlx"
@@ -19,17 +26,42 @@ import (
 var (
 	ErrNotFound  = errors.New("user not found")
 	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrArchived  = errors.New("project is archived")
+	ErrForbidden = errors.New("project is private")
+	ErrInUse     = errors.New("project still has tracked time or expenses")
+
+	ErrReferenceNotFound = errors.New("referenced client or workspace not found")
+	ErrOtherOwner        = errors.New("workspace belongs to another owner")
+	ErrOtherWorkspace    = errors.New("client belongs to another workspace")
+	ErrTemplateNotFound  = errors.New("template not found")
+	ErrNameTaken         = errors.New("project name is not unique")
+	ErrNotMember         = errors.New("team member is not a member of the workspace")
 )
 
+// noParent is the reference of a task without a parent task.
+const noParent = database.NoID
+
 // Core manages the set of APIs for user access.
 type Core struct {
-	store db.Store
+	store      db.Store
+	tasks      dbt.Store
+	teams      dbtm.Store
+	entries    dbte.Store
+	clients    dbc.Store
+	workspaces dbw.Store
+	members    dbwu.Store
 }
 
 // NewCore constructs a core for user api access.
 func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
 	return Core{
-		store: db.NewStore(log, sqlxDB),
+		store:      db.NewStore(log, sqlxDB),
+		tasks:      dbt.NewStore(log, sqlxDB),
+		teams:      dbtm.NewStore(log, sqlxDB),
+		entries:    dbte.NewStore(log, sqlxDB),
+		clients:    dbc.NewStore(log, sqlxDB),
+		workspaces: dbw.NewStore(log, sqlxDB),
+		members:    dbwu.NewStore(log, sqlxDB),
 	}
 }
 
@@ -43,14 +75,8 @@ func (c Core) Create(ctx context.Context, userID string, np NewProject, now time
 		return Project{}, fmt.Errorf("validating data: %w", err)
 	}
 
-	nameInWorkspace := c.store.QueryUnique(ctx, np.Name, "wid", np.WID)
-	if nameInWorkspace != "" {
-		return Project{}, fmt.Errorf("project name is not unique for workspace")
-	}
-
-	nameInClient := c.store.QueryUnique(ctx, np.Name, "cid", np.CID)
-	if nameInClient != "" {
-		return Project{}, fmt.Errorf("project name is not unique for client")
+	if err := c.checkName(ctx, np.Name, np.WID, np.CID); err != nil {
+		return Project{}, err
 	}
 
 	// Set values from NewProject
@@ -58,9 +84,9 @@ func (c Core) Create(ctx context.Context, userID string, np NewProject, now time
 		ID:             validate.GenerateID(),
 		Name:           np.Name,
 		WID:            np.WID,
-		CID:            np.CID,
+		CID:            database.NullID(np.CID),
 		UID:            userID,
-		Active:         false,
+		Status:         np.Status,
 		IsPrivate:      np.IsPrivate,
 		Billable:       np.Billable,
 		AutoEstimates:  np.AutoEstimates,
@@ -69,13 +95,32 @@ func (c Core) Create(ctx context.Context, userID string, np NewProject, now time
 		DateUpdated:    now,
 		Rate:           np.Rate,
 		HexColor:       np.HexColor,
+		Budget:         np.Budget,
+		BillingModel:   np.BillingModel,
+		FixedFee:       np.FixedFee,
+		RetainerFee:    np.RetainerFee,
+		RetainerHours:  np.RetainerHours,
+		OverageRate:    np.OverageRate,
+		Rollover:       np.Rollover,
 	}
 
+	if dbprojct.BillingModel == "" {
+		dbprojct.BillingModel = BillingHourly
+	}
+
+	if dbprojct.Status == "" {
+		dbprojct.Status = StatusActive
+	}
+	dbprojct.Active = dbprojct.Status == StatusActive
+
 	if dbprojct.CID == "" {
-		dbprojct.CID = "00000000-0000-0000-0000-000000000000"
+		dbprojct.CID = database.NoID
 	}
 
 	if err := c.store.Create(ctx, dbprojct); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return Project{}, ErrReferenceNotFound
+		}
 		return Project{}, fmt.Errorf("create: %w", err)
 	}
 
@@ -103,9 +148,15 @@ func (c Core) Update(ctx context.Context, projectID string, up UpdateProject, no
 	if up.Name != nil {
 		dbprojct.Name = *up.Name
 	}
-	if up.Active != nil {
-		dbprojct.Active = *up.Active
+	switch {
+	case up.Status != nil:
+		dbprojct.Status = *up.Status
+	case up.Active != nil && *up.Active:
+		dbprojct.Status = StatusActive
+	case up.Active != nil && dbprojct.Status == StatusActive:
+		dbprojct.Status = StatusOnHold
 	}
+	dbprojct.Active = dbprojct.Status == StatusActive
 	if up.IsPrivate != nil {
 		dbprojct.IsPrivate = *up.IsPrivate
 	}
@@ -126,9 +177,69 @@ func (c Core) Update(ctx context.Context, projectID string, up UpdateProject, no
 	if up.HexColor != nil {
 		dbprojct.HexColor = *up.HexColor
 	}
+	if up.Budget != nil {
+		dbprojct.Budget = *up.Budget
+	}
+	if up.BillingModel != nil {
+		dbprojct.BillingModel = *up.BillingModel
+	}
+	if up.FixedFee != nil {
+		dbprojct.FixedFee = *up.FixedFee
+	}
+	if up.RetainerFee != nil {
+		dbprojct.RetainerFee = *up.RetainerFee
+	}
+	if up.RetainerHours != nil {
+		dbprojct.RetainerHours = *up.RetainerHours
+	}
+	if up.OverageRate != nil {
+		dbprojct.OverageRate = *up.OverageRate
+	}
+	if up.Rollover != nil {
+		dbprojct.Rollover = *up.Rollover
+	}
 	dbprojct.DateUpdated = now
 
 	if err := c.store.Update(ctx, dbprojct); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return ErrReferenceNotFound
+		}
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	return nil
+}
+
+// Archive moves a project to the archived state so it is hidden from the
+// project lists and takes no new time entries.
+func (c Core) Archive(ctx context.Context, projectID string, now time.Time) error {
+	return c.setStatus(ctx, projectID, StatusArchived, now)
+}
+
+// Restore brings an archived project back to the active state.
+func (c Core) Restore(ctx context.Context, projectID string, now time.Time) error {
+	return c.setStatus(ctx, projectID, StatusActive, now)
+}
+
+// setStatus moves a project to the specified lifecycle state.
+func (c Core) setStatus(ctx context.Context, projectID, status string, now time.Time) error {
+	if err := validate.CheckID(projectID); err != nil {
+		return ErrInvalidID
+	}
+
+	dbprojct, err := c.store.QueryByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	dbprojct.Status = status
+	dbprojct.Active = status == StatusActive
+	dbprojct.DateUpdated = now
+
+	if err := c.store.UpdateStatus(ctx, dbprojct); err != nil {
 		return fmt.Errorf("udpate: %w", err)
 	}
 
@@ -142,6 +253,9 @@ func (c Core) Delete(ctx context.Context, projectID string) error {
 	}
 
 	if err := c.store.Delete(ctx, projectID); err != nil {
+		if errors.Is(err, database.ErrDBReferenced) {
+			return ErrInUse
+		}
 		return fmt.Errorf("delete: %w", err)
 	}
 
@@ -165,12 +279,51 @@ func (c Core) QueryByID(ctx context.Context, projectID string) (Project, error)
 	return toProject(dbprojct), nil
 }
 
+// CheckAccess makes sure the user may see and use the specified project.
+// Private projects are reserved to the members of their team and to the
+// workspace admins.
+func (c Core) CheckAccess(ctx context.Context, projectID, userID string) error {
+	if err := validate.CheckID(projectID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.CheckID(userID); err != nil {
+		return ErrInvalidID
+	}
+
+	access, err := c.store.QueryAccess(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if !access {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// CheckOptionalAccess is CheckAccess for the entries that may have no project,
+// which are accessible then.
+func (c Core) CheckOptionalAccess(ctx context.Context, projectID, userID string) error {
+	if projectID == "" || projectID == database.NoID {
+		return nil
+	}
+
+	return c.CheckAccess(ctx, projectID, userID)
+}
+
 // QueryClientProjects retrieves a list of existing projects from the database.
-func (c Core) QueryClientProjects(ctx context.Context, clientID string, pageNumber, rowsPerPage int) ([]Project, error) {
+// Archived projects are left out unless includeArchived is set, and private
+// projects the user may not see are always left out.
+func (c Core) QueryClientProjects(ctx context.Context, clientID, userID string, includeArchived bool, pageNumber, rowsPerPage int) ([]Project, error) {
 	if err := validate.CheckID(clientID); err != nil {
 		return []Project{}, ErrInvalidID
 	}
-	dbprojects, err := c.store.QueryClientProjects(ctx, clientID, pageNumber, rowsPerPage)
+	dbprojects, err := c.store.QueryClientProjects(ctx, clientID, userID, includeArchived, pageNumber, rowsPerPage)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -179,11 +332,13 @@ func (c Core) QueryClientProjects(ctx context.Context, clientID string, pageNumb
 }
 
 // QueryWorkspaceProjects retrieves a list of existing workspace from the database.
-func (c Core) QueryWorkspaceProjects(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Project, error) {
+// Archived projects are left out unless includeArchived is set, and private
+// projects the user may not see are always left out.
+func (c Core) QueryWorkspaceProjects(ctx context.Context, workspaceID, userID string, includeArchived bool, pageNumber, rowsPerPage int) ([]Project, error) {
 	if err := validate.CheckID(workspaceID); err != nil {
 		return []Project{}, ErrInvalidID
 	}
-	dbProjects, err := c.store.QueryWorkspaceProjects(ctx, workspaceID, pageNumber, rowsPerPage)
+	dbProjects, err := c.store.QueryWorkspaceProjects(ctx, workspaceID, userID, includeArchived, pageNumber, rowsPerPage)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -191,14 +346,639 @@ func (c Core) QueryWorkspaceProjects(ctx context.Context, workspaceID string, pa
 }
 
 // QueryUserProjects retrieves a list of existing projects from the database.
-func (c Core) QueryUserProjects(ctx context.Context, userID string, pageNumber, rowsPerPage int) ([]Project, error) {
+// Archived projects are left out unless includeArchived is set, and private
+// projects the user may not see are always left out.
+func (c Core) QueryUserProjects(ctx context.Context, userID string, includeArchived bool, pageNumber, rowsPerPage int) ([]Project, error) {
 	if err := validate.CheckID(userID); err != nil {
 		return []Project{}, ErrInvalidID
 	}
-	dbprojects, err := c.store.QueryUserProjects(ctx, userID, pageNumber, rowsPerPage)
+	dbprojects, err := c.store.QueryUserProjects(ctx, userID, includeArchived, pageNumber, rowsPerPage)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 
 	return toProjectsSlice(dbprojects), nil
 }
+
+// SaveTemplate saves a project with its tasks, team and billing settings as a
+// template new projects can be created from.
+func (c Core) SaveTemplate(ctx context.Context, projectID, userID string, nt NewTemplate, now time.Time) (Template, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return Template{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(userID); err != nil {
+		return Template{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nt); err != nil {
+		return Template{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbprojct, err := c.store.QueryByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Template{}, ErrNotFound
+		}
+		return Template{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbtasks, err := c.tasks.QueryByProject(ctx, projectID)
+	if err != nil {
+		return Template{}, fmt.Errorf("query tasks: %w", err)
+	}
+
+	dbteams, err := c.teams.QueryByProject(ctx, projectID)
+	if err != nil {
+		return Template{}, fmt.Errorf("query team: %w", err)
+	}
+
+	dbtemplate := db.Template{
+		ID:             validate.GenerateID(),
+		WID:            dbprojct.WID,
+		UID:            userID,
+		Name:           nt.Name,
+		IsPrivate:      dbprojct.IsPrivate,
+		Billable:       dbprojct.Billable,
+		AutoEstimates:  dbprojct.AutoEstimates,
+		EstimatedHours: dbprojct.EstimatedHours,
+		Rate:           dbprojct.Rate,
+		HexColor:       dbprojct.HexColor,
+		Budget:         dbprojct.Budget,
+		BillingModel:   dbprojct.BillingModel,
+		FixedFee:       dbprojct.FixedFee,
+		RetainerFee:    dbprojct.RetainerFee,
+		RetainerHours:  dbprojct.RetainerHours,
+		OverageRate:    dbprojct.OverageRate,
+		Rollover:       dbprojct.Rollover,
+		DateCreated:    now,
+		DateUpdated:    now,
+		Tasks:          make([]db.TemplateTask, len(dbtasks)),
+		Members:        make([]db.TemplateMember, len(dbteams)),
+	}
+
+	for i, dbtask := range dbtasks {
+		dbtemplate.Tasks[i] = db.TemplateTask{
+			ID:         validate.GenerateID(),
+			TemplateID: dbtemplate.ID,
+			Name:       dbtask.Name,
+			Estimated:  dbtask.Estimated,
+		}
+	}
+
+	for i, dbteam := range dbteams {
+		dbtemplate.Members[i] = db.TemplateMember{
+			ID:         validate.GenerateID(),
+			TemplateID: dbtemplate.ID,
+			UID:        dbteam.UID,
+			Manager:    dbteam.Manager,
+			Rate:       dbteam.Rate,
+		}
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.CreateTemplate(ctx, dbtemplate); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+		for _, dbtask := range dbtemplate.Tasks {
+			if err := store.CreateTemplateTask(ctx, dbtask); err != nil {
+				return fmt.Errorf("create task: %w", err)
+			}
+		}
+		for _, dbmember := range dbtemplate.Members {
+			if err := store.CreateTemplateMember(ctx, dbmember); err != nil {
+				return fmt.Errorf("create member: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return Template{}, fmt.Errorf("tran: %w", err)
+	}
+
+	return toTemplate(dbtemplate), nil
+}
+
+// CreateFromTemplate creates a new project with the tasks, team and billing
+// settings of a template.
+func (c Core) CreateFromTemplate(ctx context.Context, templateID, userID string, nf NewFromTemplate, now time.Time) (Project, error) {
+	if err := validate.CheckID(templateID); err != nil {
+		return Project{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(userID); err != nil {
+		return Project{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nf); err != nil {
+		return Project{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbtemplate, err := c.store.QueryTemplateByID(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Project{}, ErrTemplateNotFound
+		}
+		return Project{}, fmt.Errorf("query: %w", err)
+	}
+
+	if nf.CID != "" {
+		if _, err := c.queryClient(ctx, nf.CID, dbtemplate.WID); err != nil {
+			return Project{}, err
+		}
+	}
+
+	dbprojct := db.Project{
+		ID:             validate.GenerateID(),
+		Name:           nf.Name,
+		WID:            dbtemplate.WID,
+		CID:            database.NullID(nf.CID),
+		UID:            userID,
+		Active:         true,
+		IsPrivate:      dbtemplate.IsPrivate,
+		Billable:       dbtemplate.Billable,
+		AutoEstimates:  dbtemplate.AutoEstimates,
+		EstimatedHours: dbtemplate.EstimatedHours,
+		DateCreated:    now,
+		DateUpdated:    now,
+		Rate:           dbtemplate.Rate,
+		HexColor:       dbtemplate.HexColor,
+		Budget:         dbtemplate.Budget,
+		BillingModel:   dbtemplate.BillingModel,
+		FixedFee:       dbtemplate.FixedFee,
+		RetainerFee:    dbtemplate.RetainerFee,
+		RetainerHours:  dbtemplate.RetainerHours,
+		OverageRate:    dbtemplate.OverageRate,
+		Rollover:       dbtemplate.Rollover,
+		Status:         StatusActive,
+	}
+
+	dbtasks := make([]dbt.Task, len(dbtemplate.Tasks))
+	for i, task := range dbtemplate.Tasks {
+		dbtasks[i] = dbt.Task{
+			Name:      task.Name,
+			Estimated: task.Estimated,
+		}
+	}
+
+	dbteams := make([]dbtm.Team, len(dbtemplate.Members))
+	for i, member := range dbtemplate.Members {
+		dbteams[i] = dbtm.Team{
+			UID:     member.UID,
+			Manager: member.Manager,
+			Rate:    member.Rate,
+		}
+	}
+
+	if err := c.createCopy(ctx, dbprojct, dbtasks, dbteams); err != nil {
+		return Project{}, err
+	}
+
+	return toProject(dbprojct), nil
+}
+
+// Clone deep copies a project with its tasks and team, optionally into another
+// workspace or client. The team is only copied within the same workspace, in
+// another workspace the user becomes the manager of the copy.
+func (c Core) Clone(ctx context.Context, projectID, userID string, cp CloneProject, now time.Time) (Project, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return Project{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(userID); err != nil {
+		return Project{}, ErrInvalidID
+	}
+
+	if err := validate.Check(cp); err != nil {
+		return Project{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbprojct, err := c.store.QueryByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Project{}, ErrNotFound
+		}
+		return Project{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbtasks, err := c.tasks.QueryByProject(ctx, projectID)
+	if err != nil {
+		return Project{}, fmt.Errorf("query tasks: %w", err)
+	}
+
+	dbteams, err := c.teams.QueryByProject(ctx, projectID)
+	if err != nil {
+		return Project{}, fmt.Errorf("query team: %w", err)
+	}
+
+	if cp.WID != "" && cp.WID != dbprojct.WID {
+		dbprojct.WID = cp.WID
+		dbprojct.CID = ""
+		dbteams = nil
+	}
+	if cp.CID != "" {
+		if _, err := c.queryClient(ctx, cp.CID, dbprojct.WID); err != nil {
+			return Project{}, err
+		}
+		dbprojct.CID = database.NullID(cp.CID)
+	}
+
+	dbprojct.ID = validate.GenerateID()
+	dbprojct.Name = cp.Name
+	dbprojct.UID = userID
+	dbprojct.Status = StatusActive
+	dbprojct.Active = true
+	dbprojct.DateCreated = now
+	dbprojct.DateUpdated = now
+	if dbprojct.AutoEstimates {
+		dbprojct.EstimatedHours = 0
+	}
+
+	for i := range dbtasks {
+		dbtasks[i].Tracked = 0
+	}
+
+	if err := c.createCopy(ctx, dbprojct, dbtasks, dbteams); err != nil {
+		return Project{}, err
+	}
+
+	return toProject(dbprojct), nil
+}
+
+// queryClient gets the client a project is linked to, which has to belong to
+// the workspace of the project.
+func (c Core) queryClient(ctx context.Context, clientID, workspaceID string) (dbc.Client, error) {
+	dbclient, err := c.clients.QueryByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return dbc.Client{}, ErrReferenceNotFound
+		}
+		return dbc.Client{}, fmt.Errorf("query client: %w", err)
+	}
+
+	if dbclient.WID != workspaceID {
+		return dbc.Client{}, ErrOtherWorkspace
+	}
+
+	return dbclient, nil
+}
+
+// createCopy inserts a copied project with its tasks and team in a single
+// transaction. The tasks and team members get new ids and are moved into the
+// project, the tasks start over in the first status of the workflow without
+// assignees. The user of the project manages it when no team is given.
+func (c Core) createCopy(ctx context.Context, dbprojct db.Project, dbtasks []dbt.Task, dbteams []dbtm.Team) error {
+	if dbprojct.CID == "" {
+		dbprojct.CID = database.NoID
+	}
+
+	if err := c.checkName(ctx, dbprojct.Name, dbprojct.WID, string(dbprojct.CID)); err != nil {
+		return err
+	}
+
+	if len(dbteams) == 0 {
+		dbteams = []dbtm.Team{{UID: dbprojct.UID, Manager: true}}
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		if err := c.store.Tran(tx).Create(ctx, dbprojct); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		workflow, err := c.tasks.Tran(tx).QueryWorkflow(ctx, dbprojct.ID)
+		if err != nil {
+			return fmt.Errorf("query workflow: %w", err)
+		}
+
+		// Subtasks keep their parent within the copy and are created after it.
+		ids := make(map[string]string, len(dbtasks))
+		for _, dbtask := range dbtasks {
+			ids[dbtask.ID] = validate.GenerateID()
+		}
+		for _, dbtask := range orderTasks(dbtasks) {
+			parentID, ok := ids[string(dbtask.ParentID)]
+			if !ok {
+				parentID = noParent
+			}
+			dbtask.ID = ids[dbtask.ID]
+			dbtask.ParentID = database.NullID(parentID)
+			dbtask.Status = workflow[0]
+			dbtask.Assignees = []string{}
+			dbtask.DueDate = nil
+			dbtask.PID = dbprojct.ID
+			dbtask.WID = dbprojct.WID
+			dbtask.UID = dbprojct.UID
+			dbtask.Active = true
+			dbtask.DateCreated = dbprojct.DateCreated
+			dbtask.DateUpdated = dbprojct.DateUpdated
+			if err := c.tasks.Tran(tx).Create(ctx, dbtask); err != nil {
+				return fmt.Errorf("create task: %w", err)
+			}
+		}
+
+		for _, dbteam := range dbteams {
+			dbteam.ID = validate.GenerateID()
+			dbteam.PID = dbprojct.ID
+			dbteam.WID = dbprojct.WID
+			dbteam.DateCreated = dbprojct.DateCreated
+			dbteam.DateUpdated = dbprojct.DateUpdated
+			if err := c.teams.Tran(tx).Create(ctx, dbteam); err != nil {
+				return fmt.Errorf("create team: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// Move reassigns a project to another client or to another workspace of the
+// same owner. Its tasks, team, milestones and expenses go along, and so do its
+// time entries when asked, with their tags recreated by name in the workspace.
+// A project keeps a unique name in the workspace it moves to, and every member
+// of its team must already belong to that workspace. The rate and currency
+// changes of the move are reported and the move is only committed when there
+// are none or when it was confirmed.
+func (c Core) Move(ctx context.Context, projectID string, mp MoveProject, now time.Time) (MoveReport, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return MoveReport{}, ErrInvalidID
+	}
+
+	if err := validate.Check(mp); err != nil {
+		return MoveReport{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbprojct, err := c.store.QueryByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return MoveReport{}, ErrNotFound
+		}
+		return MoveReport{}, fmt.Errorf("query: %w", err)
+	}
+
+	from, err := c.workspaces.QueryByID(ctx, dbprojct.WID)
+	if err != nil {
+		return MoveReport{}, fmt.Errorf("query workspace: %w", err)
+	}
+
+	fromClient, err := c.clients.QueryByID(ctx, string(dbprojct.CID))
+	if err != nil && !errors.Is(err, database.ErrDBNotFound) {
+		return MoveReport{}, fmt.Errorf("query client: %w", err)
+	}
+
+	to, toClient := from, fromClient
+	if mp.WID != "" && mp.WID != dbprojct.WID {
+		to, err = c.workspaces.QueryByID(ctx, mp.WID)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return MoveReport{}, ErrReferenceNotFound
+			}
+			return MoveReport{}, fmt.Errorf("query workspace: %w", err)
+		}
+		if to.UID != from.UID {
+			return MoveReport{}, ErrOtherOwner
+		}
+		dbprojct.CID = database.NoID
+		toClient = dbc.Client{}
+	}
+
+	if mp.CID != "" {
+		if toClient, err = c.queryClient(ctx, mp.CID, to.ID); err != nil {
+			return MoveReport{}, err
+		}
+		dbprojct.CID = database.NullID(mp.CID)
+	}
+
+	if to.ID != from.ID {
+		if err := c.checkName(ctx, dbprojct.Name, to.ID, string(dbprojct.CID)); err != nil {
+			return MoveReport{}, err
+		}
+		if err := c.checkTeam(ctx, projectID, to.ID); err != nil {
+			return MoveReport{}, err
+		}
+	}
+
+	report := MoveReport{
+		Mismatches: mismatches(dbprojct.Rate, from, to, fromClient, toClient),
+	}
+
+	dbprojct.WID = to.ID
+	dbprojct.DateUpdated = now
+	report.Project = toProject(dbprojct)
+
+	if len(report.Mismatches) > 0 && !mp.Confirm {
+		return report, nil
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		if err := c.store.Tran(tx).Move(ctx, dbprojct); err != nil {
+			return fmt.Errorf("move: %w", err)
+		}
+
+		if to.ID == from.ID {
+			return nil
+		}
+
+		entries := c.entries.Tran(tx)
+		if !mp.TimeEntries {
+			if err := entries.DetachProject(ctx, projectID); err != nil {
+				return fmt.Errorf("detach time entries: %w", err)
+			}
+			return nil
+		}
+
+		tags, err := entries.QueryProjectTags(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("query tags: %w", err)
+		}
+		for _, name := range tags {
+			tag := dbtg.Tag{
+				ID:          validate.GenerateID(),
+				Name:        name,
+				WID:         to.ID,
+				DateCreated: now,
+				DateUpdated: now,
+			}
+			if err := entries.CreateTag(ctx, tag); err != nil {
+				return fmt.Errorf("create tag: %w", err)
+			}
+		}
+
+		if err := entries.MoveProject(ctx, projectID, to.ID); err != nil {
+			return fmt.Errorf("move time entries: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return MoveReport{}, ErrReferenceNotFound
+		}
+		return MoveReport{}, fmt.Errorf("tran: %w", err)
+	}
+
+	report.Moved = true
+	return report, nil
+}
+
+// checkTeam makes sure every member of the project team is a member of the
+// workspace the project moves to.
+func (c Core) checkTeam(ctx context.Context, projectID, workspaceID string) error {
+	teams, err := c.teams.QueryByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("query team: %w", err)
+	}
+
+	for _, team := range teams {
+		if _, err := c.members.QueryByuIDwID(ctx, workspaceID, team.UID); err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return fmt.Errorf("user[%s]: %w", team.UID, ErrNotMember)
+			}
+			return fmt.Errorf("query workspace user: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mismatches lists how the billing of a project changes when it moves between
+// workspaces and clients. The currency of a client takes precedence over the
+// one of its workspace, and the project bills the default rate of the
+// workspace when it has no rate of its own.
+func mismatches(rate float32, from, to dbw.Workspace, fromClient, toClient dbc.Client) []string {
+	list := []string{}
+
+	fromCurrency, toCurrency := from.DefaultCurrency, to.DefaultCurrency
+	if fromClient.Currency != "" {
+		fromCurrency = fromClient.Currency
+	}
+	if toClient.Currency != "" {
+		toCurrency = toClient.Currency
+	}
+	if fromCurrency != toCurrency {
+		list = append(list, fmt.Sprintf("currency changes from %q to %q", fromCurrency, toCurrency))
+	}
+
+	if rate == 0 && from.DefaultHourlyRate != to.DefaultHourlyRate {
+		list = append(list, fmt.Sprintf("workspace hourly rate changes from %v to %v", from.DefaultHourlyRate, to.DefaultHourlyRate))
+	}
+
+	if fromClient.Rate != toClient.Rate {
+		list = append(list, fmt.Sprintf("client rate changes from %v to %v", fromClient.Rate, toClient.Rate))
+	}
+
+	return list
+}
+
+// DeleteTemplate removes a project template from the database.
+func (c Core) DeleteTemplate(ctx context.Context, templateID string) error {
+	if err := validate.CheckID(templateID); err != nil {
+		return ErrInvalidID
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		if err := c.store.Tran(tx).DeleteTemplate(ctx, templateID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// QueryTemplateByID gets the specified project template from the database.
+func (c Core) QueryTemplateByID(ctx context.Context, templateID string) (Template, error) {
+	if err := validate.CheckID(templateID); err != nil {
+		return Template{}, ErrInvalidID
+	}
+
+	dbtemplate, err := c.store.QueryTemplateByID(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Template{}, ErrTemplateNotFound
+		}
+		return Template{}, fmt.Errorf("query: %w", err)
+	}
+
+	return toTemplate(dbtemplate), nil
+}
+
+// QueryWorkspaceTemplates retrieves a list of existing project templates from
+// the database.
+func (c Core) QueryWorkspaceTemplates(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Template, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []Template{}, ErrInvalidID
+	}
+
+	dbtemplates, err := c.store.QueryWorkspaceTemplates(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toTemplatesSlice(dbtemplates), nil
+}
+
+// orderTasks sorts the tasks so every parent comes before its subtasks.
+func orderTasks(dbtasks []dbt.Task) []dbt.Task {
+	ordered := make([]dbt.Task, 0, len(dbtasks))
+	placed := make(map[string]bool, len(dbtasks))
+	inSet := make(map[string]bool, len(dbtasks))
+	for _, dbtask := range dbtasks {
+		inSet[dbtask.ID] = true
+	}
+
+	for len(ordered) < len(dbtasks) {
+		progress := false
+		for _, dbtask := range dbtasks {
+			if placed[dbtask.ID] || (inSet[string(dbtask.ParentID)] && !placed[string(dbtask.ParentID)]) {
+				continue
+			}
+			ordered = append(ordered, dbtask)
+			placed[dbtask.ID] = true
+			progress = true
+		}
+
+		// Parents that loop back on themselves are placed as they come.
+		if !progress {
+			for _, dbtask := range dbtasks {
+				if !placed[dbtask.ID] {
+					ordered = append(ordered, dbtask)
+					placed[dbtask.ID] = true
+				}
+			}
+		}
+	}
+
+	return ordered
+}
+
+// checkName makes sure the project name is unique for its workspace and
+// client.
+func (c Core) checkName(ctx context.Context, name, workspaceID, clientID string) error {
+	nameInWorkspace := c.store.QueryUnique(ctx, name, "wid", workspaceID)
+	if nameInWorkspace != "" {
+		return fmt.Errorf("%w for workspace", ErrNameTaken)
+	}
+
+	if clientID == "" || clientID == database.NoID {
+		return nil
+	}
+
+	nameInClient := c.store.QueryUnique(ctx, name, "cid", clientID)
+	if nameInClient != "" {
+		return fmt.Errorf("%w for client", ErrNameTaken)
+	}
+
+	return nil
+}
@@ -112,7 +112,7 @@ func TestPagingProject(t *testing.T) {
 		{
 			ctx := context.Background()
 
-			projects1, err := core.QueryWorkspaceProjects(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 1, 1)
+			projects1, err := core.QueryWorkspaceProjects(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "5cf37266-3473-4006-984f-9325122678b7", false, 1, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve projects for page 1 : %s.", dbtest.Failed, testID, err)
 			}
@@ -123,7 +123,7 @@ func TestPagingProject(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould have a single project.", dbtest.Success, testID)
 
-			projects2, err := core.QueryWorkspaceProjects(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 2, 1)
+			projects2, err := core.QueryWorkspaceProjects(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "5cf37266-3473-4006-984f-9325122678b7", false, 2, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve projects for page 2 : %s.", dbtest.Failed, testID, err)
 			}
@@ -143,7 +143,7 @@ func TestPagingProject(t *testing.T) {
 
 			//=====================================================================================
 
-			projects3, err := core.QueryUserProjects(ctx, "5cf37266-3473-4006-984f-9325122678b7", 1, 1)
+			projects3, err := core.QueryUserProjects(ctx, "5cf37266-3473-4006-984f-9325122678b7", false, 1, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve projects for page 1 : %s.", dbtest.Failed, testID, err)
 			}
@@ -154,7 +154,7 @@ func TestPagingProject(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould have a single project.", dbtest.Success, testID)
 
-			projects4, err := core.QueryUserProjects(ctx, "5cf37266-3473-4006-984f-9325122678b7", 2, 1)
+			projects4, err := core.QueryUserProjects(ctx, "5cf37266-3473-4006-984f-9325122678b7", false, 2, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve projects for page 2 : %s.", dbtest.Failed, testID, err)
 			}
@@ -174,7 +174,7 @@ func TestPagingProject(t *testing.T) {
 
 			//=====================================================================================
 
-			projects5, err := core.QueryClientProjects(ctx, "c78db68e-e004-44f5-895b-ba562dc53d9d", 1, 1)
+			projects5, err := core.QueryClientProjects(ctx, "c78db68e-e004-44f5-895b-ba562dc53d9d", "5cf37266-3473-4006-984f-9325122678b7", false, 1, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve client projects for page 1 : %s.", dbtest.Failed, testID, err)
 			}
@@ -185,7 +185,7 @@ func TestPagingProject(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould have a single client project.", dbtest.Success, testID)
 
-			projects6, err := core.QueryClientProjects(ctx, "c78db68e-e004-44f5-895b-ba562dc53d9d", 2, 1)
+			projects6, err := core.QueryClientProjects(ctx, "c78db68e-e004-44f5-895b-ba562dc53d9d", "5cf37266-3473-4006-984f-9325122678b7", false, 2, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve client projects for page 2 : %s.", dbtest.Failed, testID, err)
 			}
@@ -206,3 +206,382 @@ func TestPagingProject(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectLifecycle(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testlifecycle")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to archive and restore a project.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen archiving the default project.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			workspaceID := "7da3ca14-6366-47cf-b953-f706226567d8"
+
+			if err := core.Archive(ctx, projectID, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to archive project : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to archive project.", dbtest.Success, testID)
+
+			projects, err := core.QueryWorkspaceProjects(ctx, workspaceID, "5cf37266-3473-4006-984f-9325122678b7", false, 1, 10)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve workspace projects : %s.", dbtest.Failed, testID, err)
+			}
+			for _, prj := range projects {
+				if prj.ID == projectID {
+					t.Fatalf("\t%s\tTest %d:\tShould hide the archived project.", dbtest.Failed, testID)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould hide the archived project.", dbtest.Success, testID)
+
+			projects, err = core.QueryWorkspaceProjects(ctx, workspaceID, "5cf37266-3473-4006-984f-9325122678b7", true, 1, 10)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve workspace projects : %s.", dbtest.Failed, testID, err)
+			}
+			var found bool
+			for _, prj := range projects {
+				if prj.ID == projectID {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("\t%s\tTest %d:\tShould list the archived project when asked to.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould list the archived project when asked to.", dbtest.Success, testID)
+
+			if err := core.Restore(ctx, projectID, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to restore project : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to restore project.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, projectID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve project by ID: %s.", dbtest.Failed, testID, err)
+			}
+			if saved.Status != StatusActive || !saved.Active {
+				t.Logf("\t\tTest %d:\tGot: %v %v", testID, saved.Status, saved.Active)
+				t.Fatalf("\t%s\tTest %d:\tShould see the project active again.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see the project active again.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestProjectTemplate(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testtemplate")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to set up projects from templates and copies.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen saving the default project as a template.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			userID := "5cf37266-3473-4006-984f-9325122678b7"
+
+			tpl, err := core.SaveTemplate(ctx, projectID, userID, NewTemplate{Name: "Onboarding"}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to save template : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to save template.", dbtest.Success, testID)
+
+			saved, err := core.QueryTemplateByID(ctx, tpl.ID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve template by ID: %s.", dbtest.Failed, testID, err)
+			}
+			if len(saved.Tasks) != 2 || len(saved.Members) != 1 {
+				t.Logf("\t\tTest %d:\tGot: %d tasks %d members", testID, len(saved.Tasks), len(saved.Members))
+				t.Fatalf("\t%s\tTest %d:\tShould keep the tasks and team of the project.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the tasks and team of the project.", dbtest.Success, testID)
+
+			prj, err := core.CreateFromTemplate(ctx, tpl.ID, userID, NewFromTemplate{Name: "New Customer"}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create project from template : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create project from template.", dbtest.Success, testID)
+
+			if prj.Rate != saved.Rate || prj.Billable != saved.Billable || prj.HexColor != saved.HexColor {
+				t.Fatalf("\t%s\tTest %d:\tShould copy the template settings.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould copy the template settings.", dbtest.Success, testID)
+
+			var tasks, members int
+			if err := db.GetContext(ctx, &tasks, `SELECT count(*) FROM tasks WHERE pid = $1`, prj.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count tasks : %s.", dbtest.Failed, testID, err)
+			}
+			if err := db.GetContext(ctx, &members, `SELECT count(*) FROM teams WHERE pid = $1`, prj.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count team : %s.", dbtest.Failed, testID, err)
+			}
+			if tasks != 2 || members != 1 {
+				t.Logf("\t\tTest %d:\tGot: %d tasks %d members", testID, tasks, members)
+				t.Fatalf("\t%s\tTest %d:\tShould create the tasks and team of the template.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould create the tasks and team of the template.", dbtest.Success, testID)
+
+			cln, err := core.Clone(ctx, projectID, userID, CloneProject{Name: "Copy"}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to clone project : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to clone project.", dbtest.Success, testID)
+
+			if err := db.GetContext(ctx, &tasks, `SELECT count(*) FROM tasks WHERE pid = $1`, cln.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count tasks : %s.", dbtest.Failed, testID, err)
+			}
+			if tasks != 2 {
+				t.Logf("\t\tTest %d:\tGot: %d tasks", testID, tasks)
+				t.Fatalf("\t%s\tTest %d:\tShould copy the tasks of the project.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould copy the tasks of the project.", dbtest.Success, testID)
+
+			const q = `
+			INSERT INTO clients
+				(client_id, name, uid, wid, notes, date_created, date_updated)
+			VALUES
+				('2f8c4b6d-1e3a-4d5f-8a7b-9c0d1e2f3a4b', 'Other Client', '5cf37266-3473-4006-984f-9325122678b7',
+				 '6fa2132c-9bdd-428a-b025-5f1a4d6ee683', '', '2021-10-01 00:00:00', '2021-10-01 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert client : %s.", dbtest.Failed, testID, err)
+			}
+
+			otherClient := NewFromTemplate{Name: "Other Customer", CID: "2f8c4b6d-1e3a-4d5f-8a7b-9c0d1e2f3a4b"}
+			if _, err := core.CreateFromTemplate(ctx, tpl.ID, userID, otherClient, now); !errors.Is(err, ErrOtherWorkspace) {
+				t.Fatalf("\t%s\tTest %d:\tShould not create a project for a client of another workspace : %v.", dbtest.Failed, testID, err)
+			}
+			if _, err := core.Clone(ctx, projectID, userID, CloneProject{Name: "Other Copy", CID: otherClient.CID}, now); !errors.Is(err, ErrOtherWorkspace) {
+				t.Fatalf("\t%s\tTest %d:\tShould not clone a project for a client of another workspace : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not link a project to a client of another workspace.", dbtest.Success, testID)
+
+			if err := core.DeleteTemplate(ctx, tpl.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete template : %s.", dbtest.Failed, testID, err)
+			}
+			if _, err := core.QueryTemplateByID(ctx, tpl.ID); !errors.Is(err, ErrTemplateNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve template : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to retrieve template.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestPrivateProject(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testprivate")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	const (
+		workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+		projectID   = "45cf87a3-5915-4079-a9af-6c559239ddbf"
+		adminID     = "5cf37266-3473-4006-984f-9325122678b7"
+		memberID    = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+	)
+
+	t.Log("Given the need to keep private projects to their team.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen making the default project private.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			upd := UpdateProject{
+				IsPrivate: dbtest.BoolPointer(true),
+			}
+			if err := core.Update(ctx, projectID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to make project private : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to make project private.", dbtest.Success, testID)
+
+			if err := core.CheckAccess(ctx, projectID, memberID); !errors.Is(err, ErrForbidden) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT give access to a user outside the team : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT give access to a user outside the team.", dbtest.Success, testID)
+
+			if err := core.CheckAccess(ctx, projectID, adminID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould give access to a workspace admin : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould give access to a workspace admin.", dbtest.Success, testID)
+
+			projects, err := core.QueryWorkspaceProjects(ctx, workspaceID, memberID, false, 1, 10)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve workspace projects : %s.", dbtest.Failed, testID, err)
+			}
+			for _, prj := range projects {
+				if prj.ID == projectID {
+					t.Fatalf("\t%s\tTest %d:\tShould hide the private project from a user outside the team.", dbtest.Failed, testID)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould hide the private project from a user outside the team.", dbtest.Success, testID)
+
+			const q = `
+			INSERT INTO teams (team_id, pid, uid, wid, manager, date_created, date_updated)
+			VALUES ('0f4b1c2d-8e3a-4d5b-9c6e-7f8a9b0c1d2e', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				'45b5fbd3-755f-4379-8f07-a58d4a30fa2f', '7da3ca14-6366-47cf-b953-f706226567d8', false,
+				'2021-10-01 00:00:00', '2021-10-01 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to join the team : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to join the team.", dbtest.Success, testID)
+
+			if err := core.CheckAccess(ctx, projectID, memberID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould give access to a team member : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould give access to a team member.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestMoveProject(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testmove")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to move projects between clients and workspaces.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen moving a project to another workspace with its time entries.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "d774cc57-e4a6-4be2-bca1-cb50610fb3f5"
+			workspaceID := "6fa2132c-9bdd-428a-b025-5f1a4d6ee683"
+
+			const project = `
+			INSERT INTO projects
+				(project_id, name, wid, cid, uid, active, is_private, billable, auto_estimates, estimated_hours,
+				 date_created, date_updated, rate, hex_color)
+			VALUES
+				('8b1d2e3f-4a5b-4c6d-9e7f-0a1b2c3d4e5f', 'User Project', '6fa2132c-9bdd-428a-b025-5f1a4d6ee683',
+				 '00000000-0000-0000-0000-000000000000', '5cf37266-3473-4006-984f-9325122678b7', 'true', 'false',
+				 'true', 'false', '0.0', '2021-10-01 00:00:00', '2021-10-01 00:00:00', '0', '#ffffff')`
+
+			if _, err := db.ExecContext(ctx, project); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert project : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.Move(ctx, projectID, MoveProject{WID: workspaceID, TimeEntries: true}, now); !errors.Is(err, ErrNameTaken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not move a project onto a name of the workspace : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not move a project onto a name of the workspace.", dbtest.Success, testID)
+
+			if _, err := db.ExecContext(ctx, `UPDATE projects SET name = 'Other Project' WHERE project_id = '8b1d2e3f-4a5b-4c6d-9e7f-0a1b2c3d4e5f'`); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to rename project : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.Move(ctx, projectID, MoveProject{WID: workspaceID, TimeEntries: true}, now); !errors.Is(err, ErrNotMember) {
+				t.Fatalf("\t%s\tTest %d:\tShould not move a team member out of their workspaces : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not move a team member out of their workspaces.", dbtest.Success, testID)
+
+			const member = `
+			INSERT INTO workspace_users
+				(workspace_user_id, uid, wid, admin, active, invite_key, date_created, date_updated)
+			VALUES
+				('9c2e3f4a-5b6c-4d7e-8f9a-1b2c3d4e5f6a', '45b5fbd3-755f-4379-8f07-a58d4a30fa2f',
+				 '6fa2132c-9bdd-428a-b025-5f1a4d6ee683', 'false', 'true', '', '2021-10-01 00:00:00', '2021-10-01 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, member); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add the team member to the workspace : %s.", dbtest.Failed, testID, err)
+			}
+
+			report, err := core.Move(ctx, projectID, MoveProject{WID: workspaceID, TimeEntries: true}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to move project : %s.", dbtest.Failed, testID, err)
+			}
+			if !report.Moved || len(report.Mismatches) != 0 {
+				t.Logf("\t\tTest %d:\tGot: %+v", testID, report)
+				t.Fatalf("\t%s\tTest %d:\tShould move the project without mismatches.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to move project.", dbtest.Success, testID)
+
+			saved, err := core.QueryByID(ctx, projectID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve project by ID: %s.", dbtest.Failed, testID, err)
+			}
+			if saved.WID != workspaceID {
+				t.Fatalf("\t%s\tTest %d:\tShould see the project in the workspace : %s.", dbtest.Failed, testID, saved.WID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see the project in the workspace.", dbtest.Success, testID)
+
+			var tags int
+			if err := db.GetContext(ctx, &tags, `SELECT count(*) FROM tags WHERE wid = $1 AND name IN ('tags1', 'tags2')`, workspaceID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count workspace tags : %s.", dbtest.Failed, testID, err)
+			}
+			if tags != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould recreate the time entry tags in the workspace : %d.", dbtest.Failed, testID, tags)
+			}
+			t.Logf("\t%s\tTest %d:\tShould recreate the time entry tags in the workspace.", dbtest.Success, testID)
+		}
+
+		testID = 1
+		t.Logf("\tTest %d:\tWhen moving a project to a client with another currency.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			clientID := "e3b7f1c2-5a4d-4c6e-9f8a-1b2c3d4e5f60"
+
+			const q = `
+			INSERT INTO clients
+				(client_id, name, uid, wid, notes, currency, date_created, date_updated)
+			VALUES
+				('e3b7f1c2-5a4d-4c6e-9f8a-1b2c3d4e5f60', 'Euro Client', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '', 'EUR', '2021-10-01 00:00:00', '2021-10-01 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert client : %s.", dbtest.Failed, testID, err)
+			}
+
+			report, err := core.Move(ctx, projectID, MoveProject{CID: clientID}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to check the move : %s.", dbtest.Failed, testID, err)
+			}
+			if report.Moved || len(report.Mismatches) != 1 {
+				t.Logf("\t\tTest %d:\tGot: %+v", testID, report)
+				t.Fatalf("\t%s\tTest %d:\tShould report the currency change before moving.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the currency change before moving.", dbtest.Success, testID)
+
+			report, err = core.Move(ctx, projectID, MoveProject{CID: clientID, Confirm: true}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to move project : %s.", dbtest.Failed, testID, err)
+			}
+			if !report.Moved {
+				t.Fatalf("\t%s\tTest %d:\tShould move the project once confirmed.", dbtest.Failed, testID)
+			}
+
+			saved, err := core.QueryByID(ctx, projectID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve project by ID: %s.", dbtest.Failed, testID, err)
+			}
+			if saved.CID != clientID {
+				t.Fatalf("\t%s\tTest %d:\tShould see the project under the client : %s.", dbtest.Failed, testID, saved.CID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould move the project once confirmed.", dbtest.Success, testID)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+// Package db contains report related query functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for report access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// entryHasField matches the time entries having the custom field value on
+// the entry, its task or its project. Every entry matches when no field is
+// given.
+const entryHasField = `(:field_id = '' OR EXISTS (
+				SELECT 1 FROM custom_field_values v
+				WHERE CAST(v.field_id AS text) = :field_id
+					AND v.entity_id IN (te.time_entry_id, te.tid, te.pid)
+					AND :field_value = ANY(v.value)
+			))`
+
+// expenseHasField matches the expenses having the custom field value on their
+// task or their project. Every expense matches when no field is given.
+const expenseHasField = `(:field_id = '' OR EXISTS (
+				SELECT 1 FROM custom_field_values v
+				WHERE CAST(v.field_id AS text) = :field_id
+					AND v.entity_id IN (tid, pid)
+					AND :field_value = ANY(v.value)
+			))`
+
+// totals selects the tracked hours, revenue, labor cost and expenses of every
+// project of a workspace in a date range. Time revenue comes from finished,
+// billable time entries priced with the team membership rate of the user on the
+// project, preferring a direct membership over one through a group, then the
+// project rate and finally the workspace default rate. Labor
+// cost uses the latest cost rate of the user that was effective at the start of
+// the entry. Prior hours are the hours tracked before the range, which fixed fee
+// projects need to know how much of the fee was recognized already. Time
+// entries and expenses are only counted when they, their task or their project
+// have the field value when a custom field is given.
+const totals = `
+	WITH entries AS (
+		SELECT
+			te.pid,
+			CAST(EXTRACT(EPOCH FROM (te.stop - te.start)) / 3600 AS double precision) AS hours,
+			CASE WHEN te.billable AND p.billable THEN
+				COALESCE(
+					(SELECT t.rate FROM project_members t WHERE t.pid = te.pid AND t.uid = te.uid AND t.rate > 0 ORDER BY t.direct DESC LIMIT 1),
+					NULLIF(p.rate, 0),
+					w.default_hourly_rate,
+					0)
+			ELSE 0 END AS bill_rate,
+			COALESCE(
+				(SELECT cr.rate FROM cost_rates cr
+				 WHERE cr.wid = te.wid AND cr.uid = te.uid AND cr.effective_date <= te.start
+				 ORDER BY cr.effective_date DESC LIMIT 1),
+				0) AS cost_rate
+		FROM
+			time_entries te
+		JOIN projects p ON p.project_id = te.pid
+		JOIN workspaces w ON w.workspace_id = te.wid
+		WHERE
+			te.wid = :workspace_id
+			AND te.start >= :start AND te.start <= :end
+			AND te.stop > te.start
+			AND ` + entryHasField + `
+	),
+	time_totals AS (
+		SELECT
+			pid,
+			SUM(hours) AS hours,
+			SUM(CASE WHEN bill_rate > 0 THEN hours ELSE 0 END) AS billable_hours,
+			SUM(hours * bill_rate) AS revenue,
+			SUM(hours * cost_rate) AS labor_cost
+		FROM
+			entries
+		GROUP BY
+			pid
+	),
+	prior_totals AS (
+		SELECT
+			te.pid,
+			SUM(CAST(EXTRACT(EPOCH FROM (te.stop - te.start)) / 3600 AS double precision)) AS hours
+		FROM
+			time_entries te
+		WHERE
+			te.wid = :workspace_id
+			AND te.start < :start
+			AND te.stop > te.start
+			AND ` + entryHasField + `
+		GROUP BY
+			te.pid
+	),
+	expense_totals AS (
+		SELECT
+			pid,
+			SUM(amount) AS expenses,
+			SUM(CASE WHEN billable THEN amount ELSE 0 END) AS billable_expenses
+		FROM
+			expenses
+		WHERE
+			wid = :workspace_id
+			AND date >= :start AND date <= :end
+			AND ` + expenseHasField + `
+		GROUP BY
+			pid
+	)
+	SELECT
+		p.project_id AS id,
+		p.name AS name,
+		p.cid AS cid,
+		COALESCE(c.name, '') AS client_name,
+		COALESCE(p.budget, 0) AS budget,
+		COALESCE(p.billing_model, 'hourly') AS billing_model,
+		COALESCE(p.estimated_hours, 0) AS estimated_hours,
+		COALESCE(p.fixed_fee, 0) AS fixed_fee,
+		COALESCE(p.retainer_fee, 0) AS retainer_fee,
+		COALESCE(p.retainer_hours, 0) AS retainer_hours,
+		COALESCE(p.overage_rate, 0) AS overage_rate,
+		COALESCE(p.rollover, false) AS rollover,
+		p.date_created AS date_created,
+		COALESCE(pt.hours, 0) AS prior_hours,
+		COALESCE(t.hours, 0) AS tracked_hours,
+		COALESCE(t.billable_hours, 0) AS billable_hours,
+		COALESCE(t.revenue, 0) AS revenue,
+		COALESCE(t.labor_cost, 0) AS labor_cost,
+		COALESCE(x.expenses, 0) AS expenses,
+		COALESCE(x.billable_expenses, 0) AS billable_expenses
+	FROM
+		projects p
+	LEFT JOIN clients c ON c.client_id = p.cid
+	LEFT JOIN time_totals t ON t.pid = p.project_id
+	LEFT JOIN prior_totals pt ON pt.pid = p.project_id
+	LEFT JOIN expense_totals x ON x.pid = p.project_id
+	WHERE
+		p.wid = :workspace_id`
+
+// QueryProjectTotals retrieves the totals of every project of a workspace that
+// has time or expenses in the date range, or runs on a retainer, from the
+// database. Only the time and expenses having the custom field value are
+// counted when a field is given, and retainers without any are left out.
+func (s Store) QueryProjectTotals(ctx context.Context, workspaceID string, start, end time.Time, fieldID, fieldValue string) ([]ProjectTotal, error) {
+	data := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		Start       time.Time `db:"start"`
+		End         time.Time `db:"end"`
+		FieldID     string    `db:"field_id"`
+		FieldValue  string    `db:"field_value"`
+	}{
+		WorkspaceID: workspaceID,
+		Start:       start,
+		End:         end,
+		FieldID:     fieldID,
+		FieldValue:  fieldValue,
+	}
+
+	const q = totals + `
+		AND (t.pid IS NOT NULL OR x.pid IS NOT NULL
+			OR (p.billing_model = 'retainer' AND p.date_created <= :end AND :field_id = ''))
+	ORDER BY
+		p.name`
+
+	var projectTotals []ProjectTotal
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &projectTotals); err != nil {
+		return nil, fmt.Errorf("selecting project totals: %w", err)
+	}
+
+	return projectTotals, nil
+}
+
+// QueryProjectTotal retrieves the totals of the specified project in the date
+// range from the database.
+func (s Store) QueryProjectTotal(ctx context.Context, workspaceID, projectID string, start, end time.Time) (ProjectTotal, error) {
+	data := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		ProjectID   string    `db:"project_id"`
+		Start       time.Time `db:"start"`
+		End         time.Time `db:"end"`
+		FieldID     string    `db:"field_id"`
+		FieldValue  string    `db:"field_value"`
+	}{
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		Start:       start,
+		End:         end,
+	}
+
+	const q = totals + `
+		AND p.project_id = :project_id`
+
+	var projectTotal ProjectTotal
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &projectTotal); err != nil {
+		return ProjectTotal{}, fmt.Errorf("selecting project total projectID[%q]: %w", projectID, err)
+	}
+
+	return projectTotal, nil
+}
+
+// QueryInvoiceExpenses retrieves the billable expenses of a client that were not
+// invoiced yet in the date range from the database.
+func (s Store) QueryInvoiceExpenses(ctx context.Context, clientID string, start, end time.Time) ([]InvoiceExpense, error) {
+	data := struct {
+		ClientID string    `db:"client_id"`
+		Start    time.Time `db:"start"`
+		End      time.Time `db:"end"`
+	}{
+		ClientID: clientID,
+		Start:    start,
+		End:      end,
+	}
+
+	const q = `
+	SELECT
+		e.expense_id, e.pid, e.description, e.category, e.amount, e.currency, e.date
+	FROM
+		expenses e
+	JOIN projects p ON p.project_id = e.pid
+	WHERE
+		p.cid = :client_id
+		AND e.billable = true AND e.invoiced = false
+		AND e.date >= :start AND e.date <= :end
+	ORDER BY
+		e.date, e.expense_id`
+
+	var expenses []InvoiceExpense
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &expenses); err != nil {
+		return nil, fmt.Errorf("selecting invoice expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+// QueryInvoiceCurrency retrieves the currency a client is billed in from the
+// database, which is the default currency of its workspace unless the client
+// has its own.
+func (s Store) QueryInvoiceCurrency(ctx context.Context, clientID string) (string, error) {
+	data := struct {
+		ClientID string `db:"client_id"`
+	}{
+		ClientID: clientID,
+	}
+
+	const q = `
+	SELECT
+		COALESCE(NULLIF(c.currency, ''), w.default_currency, '') AS currency
+	FROM
+		clients c
+	JOIN workspaces w ON w.workspace_id = c.wid
+	WHERE
+		c.client_id = :client_id`
+
+	var invoice struct {
+		Currency string `db:"currency"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &invoice); err != nil {
+		return "", fmt.Errorf("selecting invoice currency clientID[%q]: %w", clientID, err)
+	}
+
+	return invoice.Currency, nil
+}
+
+// QueryRetainerHours retrieves the billable hours of every retainer project of
+// a workspace per month up to end from the database.
+func (s Store) QueryRetainerHours(ctx context.Context, workspaceID string, end time.Time) ([]MonthlyHours, error) {
+	data := struct {
+		WorkspaceID string    `db:"workspace_id"`
+		End         time.Time `db:"end"`
+	}{
+		WorkspaceID: workspaceID,
+		End:         end,
+	}
+
+	const q = `
+	SELECT
+		te.pid AS pid,
+		date_trunc('month', te.start) AS month,
+		SUM(CAST(EXTRACT(EPOCH FROM (te.stop - te.start)) / 3600 AS double precision)) AS billable_hours
+	FROM
+		time_entries te
+	JOIN projects p ON p.project_id = te.pid
+	WHERE
+		te.wid = :workspace_id
+		AND p.billing_model = 'retainer'
+		AND te.billable = true
+		AND te.start <= :end
+		AND te.stop > te.start
+	GROUP BY
+		te.pid, date_trunc('month', te.start)
+	ORDER BY
+		te.pid, month`
+
+	var hours []MonthlyHours
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &hours); err != nil {
+		return nil, fmt.Errorf("selecting retainer hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+// QueryMilestoneTotals retrieves the hours and billable value of the finished
+// time entries up to end on the tasks of every milestone of a project from the
+// database. Entries are priced like in the project totals.
+func (s Store) QueryMilestoneTotals(ctx context.Context, projectID string, end time.Time) ([]MilestoneTotal, error) {
+	data := struct {
+		ProjectID string    `db:"project_id"`
+		End       time.Time `db:"end"`
+	}{
+		ProjectID: projectID,
+		End:       end,
+	}
+
+	const q = `
+	WITH entries AS (
+		SELECT
+			te.tid,
+			CAST(EXTRACT(EPOCH FROM (te.stop - te.start)) / 3600 AS double precision) AS hours,
+			CASE WHEN te.billable AND p.billable THEN
+				COALESCE(
+					(SELECT t.rate FROM project_members t WHERE t.pid = te.pid AND t.uid = te.uid AND t.rate > 0 ORDER BY t.direct DESC LIMIT 1),
+					NULLIF(p.rate, 0),
+					w.default_hourly_rate,
+					0)
+			ELSE 0 END AS bill_rate
+		FROM
+			time_entries te
+		JOIN projects p ON p.project_id = te.pid
+		JOIN workspaces w ON w.workspace_id = te.wid
+		WHERE
+			te.pid = :project_id
+			AND te.start <= :end
+			AND te.stop > te.start
+	)
+	SELECT
+		m.milestone_id AS id,
+		m.name AS name,
+		m.target_date AS target_date,
+		COALESCE(m.budget, 0) AS budget,
+		COALESCE(SUM(e.hours), 0) AS tracked_hours,
+		COALESCE(SUM(e.hours * e.bill_rate), 0) AS time_amount
+	FROM
+		milestones m
+	LEFT JOIN milestone_tasks mt ON mt.milestone_id = m.milestone_id
+	LEFT JOIN entries e ON e.tid = mt.task_id
+	WHERE
+		m.pid = :project_id
+	GROUP BY
+		m.milestone_id
+	ORDER BY
+		m.target_date, m.name`
+
+	var milestoneTotals []MilestoneTotal
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &milestoneTotals); err != nil {
+		return nil, fmt.Errorf("selecting milestone totals: %w", err)
+	}
+
+	return milestoneTotals, nil
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+)
+
+// ProjectTotal represent the structure we need for moving data
+// between the app and the database.
+type ProjectTotal struct {
+	ID               string          `db:"id"`
+	Name             string          `db:"name"`
+	CID              database.NullID `db:"cid"`
+	ClientName       string          `db:"client_name"`
+	Budget           float64         `db:"budget"`
+	BillingModel     string          `db:"billing_model"`
+	EstimatedHours   float64         `db:"estimated_hours"`
+	FixedFee         float64         `db:"fixed_fee"`
+	RetainerFee      float64         `db:"retainer_fee"`
+	RetainerHours    float64         `db:"retainer_hours"`
+	OverageRate      float64         `db:"overage_rate"`
+	Rollover         bool            `db:"rollover"`
+	DateCreated      time.Time       `db:"date_created"`
+	PriorHours       float64         `db:"prior_hours"`
+	TrackedHours     float64         `db:"tracked_hours"`
+	BillableHours    float64         `db:"billable_hours"`
+	Revenue          float64         `db:"revenue"`
+	LaborCost        float64         `db:"labor_cost"`
+	Expenses         float64         `db:"expenses"`
+	BillableExpenses float64         `db:"billable_expenses"`
+}
+
+// InvoiceExpense represent the structure we need for moving data
+// between the app and the database.
+type InvoiceExpense struct {
+	ID          string    `db:"expense_id"`
+	PID         string    `db:"pid"`
+	Description string    `db:"description"`
+	Category    string    `db:"category"`
+	Amount      float64   `db:"amount"`
+	Currency    string    `db:"currency"`
+	Date        time.Time `db:"date"`
+}
+
+// MonthlyHours represent the structure we need for moving data
+// between the app and the database.
+type MonthlyHours struct {
+	PID           string    `db:"pid"`
+	Month         time.Time `db:"month"`
+	BillableHours float64   `db:"billable_hours"`
+}
+
+// MilestoneTotal represent the structure we need for moving data
+// between the app and the database.
+type MilestoneTotal struct {
+	ID           string    `db:"id"`
+	Name         string    `db:"name"`
+	TargetDate   time.Time `db:"target_date"`
+	Budget       float64   `db:"budget"`
+	TrackedHours float64   `db:"tracked_hours"`
+	TimeAmount   float64   `db:"time_amount"`
+}
@@ -0,0 +1,250 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/report/db"
+)
+
+// Profitability represents the revenue, labor cost and expenses of a project
+// or a client over a date range. Billable expenses are part of the revenue.
+type Profitability struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	TrackedHours  float64 `json:"tracked_hours"`
+	Revenue       float64 `json:"revenue"`
+	LaborCost     float64 `json:"labor_cost"`
+	Expenses      float64 `json:"expenses"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+// Budget represents how much of a project budget is already spent. The spent
+// amount is the billable value of the tracked time plus all project expenses.
+type Budget struct {
+	PID          string            `json:"pid"`
+	Name         string            `json:"name"`
+	Budget       float64           `json:"budget"`
+	TrackedHours float64           `json:"tracked_hours"`
+	TimeAmount   float64           `json:"time_amount"`
+	Expenses     float64           `json:"expenses"`
+	Spent        float64           `json:"spent"`
+	Remaining    float64           `json:"remaining"`
+	SpentPercent float64           `json:"spent_percent"`
+	Milestones   []MilestoneBudget `json:"milestones"`
+}
+
+// MilestoneBudget represents how much of a milestone budget is spent by the
+// billable value of the time tracked on the tasks of the milestone.
+type MilestoneBudget struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	TargetDate   time.Time `json:"target_date"`
+	Budget       float64   `json:"budget"`
+	TrackedHours float64   `json:"tracked_hours"`
+	TimeAmount   float64   `json:"time_amount"`
+	Remaining    float64   `json:"remaining"`
+	SpentPercent float64   `json:"spent_percent"`
+}
+
+// Invoice represents a draft invoice of a client over a date range. The time
+// is billed in the currency of the invoice, expenses in their own currency,
+// so there is a total for every currency of the lines.
+type Invoice struct {
+	CID      string             `json:"cid"`
+	Start    time.Time          `json:"start"`
+	End      time.Time          `json:"end"`
+	Currency string             `json:"currency"`
+	Lines    []InvoiceLine      `json:"lines"`
+	Totals   map[string]float64 `json:"totals"`
+}
+
+// InvoiceLine represents a single line of a draft invoice. Time lines group
+// the billable time of a project, expense lines refer to a single billable
+// expense that was not invoiced yet.
+type InvoiceLine struct {
+	Kind        string  `json:"kind"`
+	RefID       string  `json:"ref_id"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+}
+
+// Set of invoice line kinds.
+const (
+	LineTime    = "time"
+	LineExpense = "expense"
+)
+
+// Set of project billing models.
+const (
+	BillingHourly   = "hourly"
+	BillingFixedFee = "fixed_fee"
+	BillingRetainer = "retainer"
+)
+
+// FieldFilter limits a report to the time entries and expenses having Value
+// in the custom field, set on them, their task or their project. An empty
+// FieldID counts them all.
+type FieldFilter struct {
+	FieldID string
+	Value   string
+}
+
+// =============================================================================
+
+// total holds the aggregated amounts that every report is built from.
+type total struct {
+	trackedHours float64
+	revenue      float64
+	laborCost    float64
+	expenses     float64
+}
+
+func toTotal(dbTotal db.ProjectTotal, revenue float64) total {
+	return total{
+		trackedHours: dbTotal.TrackedHours,
+		revenue:      revenue + dbTotal.BillableExpenses,
+		laborCost:    dbTotal.LaborCost,
+		expenses:     dbTotal.Expenses,
+	}
+}
+
+func (t total) add(o total) total {
+	return total{
+		trackedHours: t.trackedHours + o.trackedHours,
+		revenue:      t.revenue + o.revenue,
+		laborCost:    t.laborCost + o.laborCost,
+		expenses:     t.expenses + o.expenses,
+	}
+}
+
+func toProfitability(id, name string, t total) Profitability {
+	margin := t.revenue - t.laborCost - t.expenses
+
+	profit := Profitability{
+		ID:           id,
+		Name:         name,
+		TrackedHours: round(t.trackedHours),
+		Revenue:      round(t.revenue),
+		LaborCost:    round(t.laborCost),
+		Expenses:     round(t.expenses),
+		Margin:       round(margin),
+	}
+
+	if t.revenue != 0 {
+		profit.MarginPercent = round(margin / t.revenue * 100)
+	}
+
+	return profit
+}
+
+func toBudget(dbTotal db.ProjectTotal, revenue float64) Budget {
+	spent := revenue + dbTotal.Expenses
+
+	budget := Budget{
+		PID:          dbTotal.ID,
+		Name:         dbTotal.Name,
+		Budget:       round(dbTotal.Budget),
+		TrackedHours: round(dbTotal.TrackedHours),
+		TimeAmount:   round(revenue),
+		Expenses:     round(dbTotal.Expenses),
+		Spent:        round(spent),
+		Remaining:    round(dbTotal.Budget - spent),
+	}
+
+	if dbTotal.Budget != 0 {
+		budget.SpentPercent = round(spent / dbTotal.Budget * 100)
+	}
+
+	return budget
+}
+
+func toMilestoneBudgets(dbTotals []db.MilestoneTotal) []MilestoneBudget {
+	budgets := make([]MilestoneBudget, len(dbTotals))
+	for i, dbTotal := range dbTotals {
+		budgets[i] = MilestoneBudget{
+			ID:           dbTotal.ID,
+			Name:         dbTotal.Name,
+			TargetDate:   dbTotal.TargetDate,
+			Budget:       round(dbTotal.Budget),
+			TrackedHours: round(dbTotal.TrackedHours),
+			TimeAmount:   round(dbTotal.TimeAmount),
+			Remaining:    round(dbTotal.Budget - dbTotal.TimeAmount),
+		}
+
+		if dbTotal.Budget != 0 {
+			budgets[i].SpentPercent = round(dbTotal.TimeAmount / dbTotal.Budget * 100)
+		}
+	}
+	return budgets
+}
+
+// fixedFeeRevenue recognizes the fixed fee of a project as the estimated hours
+// are used up, so the fee is spread over the ranges the work was done in. A
+// project without an estimate recognizes the whole fee with its first tracked
+// hours.
+func fixedFeeRevenue(dbTotal db.ProjectTotal) float64 {
+	if dbTotal.EstimatedHours <= 0 {
+		if dbTotal.PriorHours == 0 && dbTotal.TrackedHours > 0 {
+			return dbTotal.FixedFee
+		}
+		return 0
+	}
+
+	before := math.Min(1, dbTotal.PriorHours/dbTotal.EstimatedHours)
+	after := math.Min(1, (dbTotal.PriorHours+dbTotal.TrackedHours)/dbTotal.EstimatedHours)
+
+	return dbTotal.FixedFee * (after - before)
+}
+
+// retainerRevenue walks the months of a retainer project from its creation up
+// to end. Every month bills the retainer fee plus the billable hours above the
+// included hours at the overage rate. At the end of a month the unused hours
+// roll over to the next month, or are forfeited when rollover is off. Only the
+// months that overlap the range are part of the revenue.
+func retainerRevenue(dbTotal db.ProjectTotal, hours map[time.Time]float64, start, end time.Time) float64 {
+	first := monthOf(dbTotal.DateCreated)
+	for month := range hours {
+		if first.IsZero() || month.Before(first) {
+			first = month
+		}
+	}
+
+	if first.IsZero() {
+		return 0
+	}
+
+	var revenue, carry float64
+	for month := first; !month.After(end); month = month.AddDate(0, 1, 0) {
+		available := dbTotal.RetainerHours + carry
+		used := hours[month]
+
+		carry = 0
+		if dbTotal.Rollover && available > used {
+			carry = available - used
+		}
+
+		if month.AddDate(0, 1, 0).After(start) {
+			revenue += dbTotal.RetainerFee + math.Max(0, used-available)*dbTotal.OverageRate
+		}
+	}
+
+	return revenue
+}
+
+// monthOf returns the first instant of the month of t.
+func monthOf(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// round rounds money and hours to two decimal places.
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
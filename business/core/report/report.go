@@ -0,0 +1,289 @@
+// Package report provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/report/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for report operations.
+var (
+	ErrNotFound     = errors.New("project not found")
+	ErrInvalidID    = errors.New("ID is not in its proper form")
+	ErrInvalidRange = errors.New("end date must be after start date")
+)
+
+// Core manages the set of APIs for report access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for report api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// QueryProjectProfitability retrieves the profitability of the workspace
+// projects between start and end. Only the time and expenses matching the
+// custom field filter are counted when it is set.
+func (c Core) QueryProjectProfitability(ctx context.Context, workspaceID string, start, end time.Time, ff FieldFilter) ([]Profitability, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []Profitability{}, ErrInvalidID
+	}
+
+	if ff.FieldID != "" {
+		if err := validate.CheckID(ff.FieldID); err != nil {
+			return []Profitability{}, ErrInvalidID
+		}
+	}
+
+	if !end.After(start) {
+		return []Profitability{}, ErrInvalidRange
+	}
+
+	dbTotals, err := c.store.QueryProjectTotals(ctx, workspaceID, start, end, ff.FieldID, ff.Value)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	revenues, err := c.revenues(ctx, workspaceID, dbTotals, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	profits := make([]Profitability, len(dbTotals))
+	for i, dbTotal := range dbTotals {
+		profits[i] = toProfitability(dbTotal.ID, dbTotal.Name, toTotal(dbTotal, revenues[i]))
+	}
+
+	return profits, nil
+}
+
+// QueryClientProfitability retrieves the profitability of the workspace
+// clients between start and end. Projects without a client are left out. Only
+// the time and expenses matching the custom field filter are counted when it
+// is set.
+func (c Core) QueryClientProfitability(ctx context.Context, workspaceID string, start, end time.Time, ff FieldFilter) ([]Profitability, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []Profitability{}, ErrInvalidID
+	}
+
+	if ff.FieldID != "" {
+		if err := validate.CheckID(ff.FieldID); err != nil {
+			return []Profitability{}, ErrInvalidID
+		}
+	}
+
+	if !end.After(start) {
+		return []Profitability{}, ErrInvalidRange
+	}
+
+	dbTotals, err := c.store.QueryProjectTotals(ctx, workspaceID, start, end, ff.FieldID, ff.Value)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	revenues, err := c.revenues(ctx, workspaceID, dbTotals, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	names := make(map[string]string)
+	totals := make(map[string]total)
+	for i, dbTotal := range dbTotals {
+		if dbTotal.CID == database.NoID {
+			continue
+		}
+		clientID := string(dbTotal.CID)
+		if _, exists := totals[clientID]; !exists {
+			order = append(order, clientID)
+			names[clientID] = dbTotal.ClientName
+		}
+		totals[clientID] = totals[clientID].add(toTotal(dbTotal, revenues[i]))
+	}
+
+	profits := make([]Profitability, len(order))
+	for i, clientID := range order {
+		profits[i] = toProfitability(clientID, names[clientID], totals[clientID])
+	}
+
+	return profits, nil
+}
+
+// QueryProjectBudget retrieves how much of the project budget is spent up
+// to now, along with the spending of every milestone of the project.
+func (c Core) QueryProjectBudget(ctx context.Context, workspaceID, projectID string, now time.Time) (Budget, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Budget{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(projectID); err != nil {
+		return Budget{}, ErrInvalidID
+	}
+
+	dbTotal, err := c.store.QueryProjectTotal(ctx, workspaceID, projectID, time.Time{}, now)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Budget{}, ErrNotFound
+		}
+		return Budget{}, fmt.Errorf("query: %w", err)
+	}
+
+	revenues, err := c.revenues(ctx, workspaceID, []db.ProjectTotal{dbTotal}, time.Time{}, now)
+	if err != nil {
+		return Budget{}, err
+	}
+
+	dbMilestones, err := c.store.QueryMilestoneTotals(ctx, projectID, now)
+	if err != nil {
+		return Budget{}, fmt.Errorf("query: %w", err)
+	}
+
+	budget := toBudget(dbTotal, revenues[0])
+	budget.Milestones = toMilestoneBudgets(dbMilestones)
+
+	return budget, nil
+}
+
+// QueryClientInvoice builds a draft invoice for the client between start and
+// end with one line per project for the billable time and one line per
+// billable expense that was not invoiced yet. The time is billed in the
+// currency of the client, or of the workspace when the client has none, and
+// the lines are totaled per currency.
+func (c Core) QueryClientInvoice(ctx context.Context, workspaceID, clientID string, start, end time.Time) (Invoice, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return Invoice{}, ErrInvalidID
+	}
+
+	if err := validate.CheckID(clientID); err != nil {
+		return Invoice{}, ErrInvalidID
+	}
+
+	if !end.After(start) {
+		return Invoice{}, ErrInvalidRange
+	}
+
+	dbTotals, err := c.store.QueryProjectTotals(ctx, workspaceID, start, end, "", "")
+	if err != nil {
+		return Invoice{}, fmt.Errorf("query: %w", err)
+	}
+
+	revenues, err := c.revenues(ctx, workspaceID, dbTotals, start, end)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	dbExpenses, err := c.store.QueryInvoiceExpenses(ctx, clientID, start, end)
+	if err != nil {
+		return Invoice{}, fmt.Errorf("query: %w", err)
+	}
+
+	currency, err := c.store.QueryInvoiceCurrency(ctx, clientID)
+	if err != nil {
+		return Invoice{}, fmt.Errorf("query: %w", err)
+	}
+
+	invoice := Invoice{
+		CID:      clientID,
+		Start:    start,
+		End:      end,
+		Currency: currency,
+		Lines:    []InvoiceLine{},
+		Totals:   make(map[string]float64),
+	}
+
+	for i, dbTotal := range dbTotals {
+		if string(dbTotal.CID) != clientID || revenues[i] == 0 {
+			continue
+		}
+		invoice.Lines = append(invoice.Lines, InvoiceLine{
+			Kind:        LineTime,
+			RefID:       dbTotal.ID,
+			Description: dbTotal.Name,
+			Quantity:    round(dbTotal.BillableHours),
+			Amount:      round(revenues[i]),
+			Currency:    currency,
+		})
+	}
+
+	for _, dbExpense := range dbExpenses {
+
+		// Expenses recorded without a currency are taken to be in the
+		// currency of the invoice.
+		expenseCurrency := dbExpense.Currency
+		if expenseCurrency == "" {
+			expenseCurrency = currency
+		}
+
+		invoice.Lines = append(invoice.Lines, InvoiceLine{
+			Kind:        LineExpense,
+			RefID:       dbExpense.ID,
+			Description: dbExpense.Category + ": " + dbExpense.Description,
+			Quantity:    1,
+			Amount:      round(dbExpense.Amount),
+			Currency:    expenseCurrency,
+		})
+	}
+
+	for _, line := range invoice.Lines {
+		invoice.Totals[line.Currency] += line.Amount
+	}
+	for cur, total := range invoice.Totals {
+		invoice.Totals[cur] = round(total)
+	}
+
+	return invoice, nil
+}
+
+// revenues computes the time revenue of every project total between start and
+// end according to the billing model of the project. The monthly hours of
+// retainer projects are only loaded when there is a retainer to walk.
+func (c Core) revenues(ctx context.Context, workspaceID string, dbTotals []db.ProjectTotal, start, end time.Time) ([]float64, error) {
+	var months map[string]map[time.Time]float64
+	for _, dbTotal := range dbTotals {
+		if dbTotal.BillingModel != BillingRetainer {
+			continue
+		}
+
+		dbHours, err := c.store.QueryRetainerHours(ctx, workspaceID, end)
+		if err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+
+		months = make(map[string]map[time.Time]float64)
+		for _, dbHour := range dbHours {
+			if _, exists := months[dbHour.PID]; !exists {
+				months[dbHour.PID] = make(map[time.Time]float64)
+			}
+			months[dbHour.PID][monthOf(dbHour.Month)] += dbHour.BillableHours
+		}
+		break
+	}
+
+	revenues := make([]float64, len(dbTotals))
+	for i, dbTotal := range dbTotals {
+		switch dbTotal.BillingModel {
+		case BillingFixedFee:
+			revenues[i] = fixedFeeRevenue(dbTotal)
+		case BillingRetainer:
+			revenues[i] = retainerRevenue(dbTotal, months[dbTotal.ID], start, end)
+		default:
+			revenues[i] = dbTotal.Revenue
+		}
+	}
+
+	return revenues, nil
+}
@@ -0,0 +1,271 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestProfitability(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testprofitability")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to report on profitability.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling two billable hours and an expense on a project.", testID)
+		{
+			ctx := context.Background()
+			start := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2021, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+			const q = `
+			INSERT INTO time_entries
+				(time_entry_id, description, uid, wid, pid, tid, billable, start, stop, duration, created_with, tags, dur_only, date_created, date_updated)
+			VALUES
+				('a0c7a6c4-4c1e-4a54-93f4-6e0c2a4f7d10', 'report', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '346efd40-6d6e-46d5-b60b-5db9fc171779', true, '2021-10-01 10:00:00', '2021-10-01 12:00:00', 7200,
+				 'test', '{}', false, '2021-10-01 12:00:00', '2021-10-01 12:00:00');
+			INSERT INTO expenses
+				(expense_id, wid, pid, tid, uid, description, category, amount, currency, date, billable, invoiced, receipt_url, date_created, date_updated)
+			VALUES
+				('5b0e8e56-0f59-4e59-a2a5-0f1d3c9f2b61', '7da3ca14-6366-47cf-b953-f706226567d8',
+				 '45cf87a3-5915-4079-a9af-6c559239ddbf', NULL,
+				 '5cf37266-3473-4006-984f-9325122678b7', 'Train', 'travel', 100, 'USD', '2021-10-05 00:00:00',
+				 true, false, '', '2021-10-05 00:00:00', '2021-10-05 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert time entry and expense : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert time entry and expense.", dbtest.Success, testID)
+
+			projects, err := core.QueryProjectProfitability(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", start, end, FieldFilter{})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve project profitability : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve project profitability.", dbtest.Success, testID)
+
+			if len(projects) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single project : %d.", dbtest.Failed, testID, len(projects))
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single project.", dbtest.Success, testID)
+
+			exp := Profitability{
+				ID:            "45cf87a3-5915-4079-a9af-6c559239ddbf",
+				Name:          "Default Project",
+				TrackedHours:  2,
+				Revenue:       160,
+				LaborCost:     40,
+				Expenses:      100,
+				Margin:        20,
+				MarginPercent: 12.5,
+			}
+			if projects[0] != exp {
+				t.Errorf("\t%s\tTest %d:\tShould compute the project profitability.", dbtest.Failed, testID)
+				t.Logf("\t\tTest %d:\tGot: %+v", testID, projects[0])
+				t.Logf("\t\tTest %d:\tExp: %+v", testID, exp)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould compute the project profitability.", dbtest.Success, testID)
+			}
+
+			clients, err := core.QueryClientProfitability(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", start, end, FieldFilter{})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve client profitability : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve client profitability.", dbtest.Success, testID)
+
+			if len(clients) != 1 || clients[0].Revenue != exp.Revenue || clients[0].LaborCost != exp.LaborCost {
+				t.Errorf("\t%s\tTest %d:\tShould compute the client profitability : %+v.", dbtest.Failed, testID, clients)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould compute the client profitability.", dbtest.Success, testID)
+			}
+
+			invoice, err := core.QueryClientInvoice(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "c78db68e-e004-44f5-895b-ba562dc53d9d", start, end)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to build client invoice : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to build client invoice.", dbtest.Success, testID)
+
+			if len(invoice.Lines) != 2 || invoice.Currency != "USD" || len(invoice.Totals) != 1 || invoice.Totals["USD"] != 160 {
+				t.Errorf("\t%s\tTest %d:\tShould invoice the billable time and expense : %+v.", dbtest.Failed, testID, invoice)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould invoice the billable time and expense.", dbtest.Success, testID)
+			}
+
+			const eur = `
+			INSERT INTO expenses
+				(expense_id, wid, pid, tid, uid, description, category, amount, currency, date, billable, invoiced, receipt_url, date_created, date_updated)
+			VALUES
+				('9d3f6a1e-2b7c-4e8d-b5a0-6c4e1f2d3a87', '7da3ca14-6366-47cf-b953-f706226567d8',
+				 '45cf87a3-5915-4079-a9af-6c559239ddbf', NULL,
+				 '5cf37266-3473-4006-984f-9325122678b7', 'Hotel', 'travel', 40, 'EUR', '2021-10-06 00:00:00',
+				 true, false, '', '2021-10-06 00:00:00', '2021-10-06 00:00:00')`
+
+			if _, err := db.ExecContext(ctx, eur); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert an expense in another currency : %s.", dbtest.Failed, testID, err)
+			}
+
+			invoice, err = core.QueryClientInvoice(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "c78db68e-e004-44f5-895b-ba562dc53d9d", start, end)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to build client invoice : %s.", dbtest.Failed, testID, err)
+			}
+
+			if len(invoice.Lines) != 3 || len(invoice.Totals) != 2 || invoice.Totals["USD"] != 160 || invoice.Totals["EUR"] != 40 {
+				t.Errorf("\t%s\tTest %d:\tShould total every currency on its own : %+v.", dbtest.Failed, testID, invoice)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould total every currency on its own.", dbtest.Success, testID)
+			}
+		}
+	}
+}
+
+func TestBillingModels(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testbillingmodels")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to bill projects by their billing model.")
+	{
+		ctx := context.Background()
+		start := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2021, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+		const q = `
+		INSERT INTO time_entries
+			(time_entry_id, description, uid, wid, pid, tid, billable, start, stop, duration, created_with, tags, dur_only, date_created, date_updated)
+		VALUES
+			('c5e2f0b8-3f5c-4c8e-9a57-2b1f4d0e6a21', 'billing', '5cf37266-3473-4006-984f-9325122678b7',
+			 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+			 '346efd40-6d6e-46d5-b60b-5db9fc171779', true, '2021-10-04 08:00:00', '2021-10-04 20:00:00', 43200,
+			 'test', '{}', false, '2021-10-04 20:00:00', '2021-10-04 20:00:00')`
+
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			t.Fatalf("\t%s\tTest:\tShould be able to insert time entry : %s.", dbtest.Failed, err)
+		}
+		t.Logf("\t%s\tTest:\tShould be able to insert time entry.", dbtest.Success)
+
+		tests := []struct {
+			name    string
+			billing string
+			revenue float64
+		}{
+			{"fixed fee", `billing_model = 'fixed_fee', fixed_fee = 3000`, 1200},
+			{"retainer with overage", `billing_model = 'retainer', retainer_fee = 1000, retainer_hours = 10, overage_rate = 100`, 1200},
+			{"retainer with rollover", `billing_model = 'retainer', retainer_fee = 1000, retainer_hours = 10, overage_rate = 100, rollover = true`, 1000},
+		}
+
+		for testID, tt := range tests {
+			t.Logf("\tTest %d:\tWhen handling a %s project.", testID, tt.name)
+			{
+				q := `UPDATE projects SET ` + tt.billing + ` WHERE project_id = '45cf87a3-5915-4079-a9af-6c559239ddbf'`
+				if _, err := db.ExecContext(ctx, q); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to update billing model : %s.", dbtest.Failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to update billing model.", dbtest.Success, testID)
+
+				projects, err := core.QueryProjectProfitability(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", start, end, FieldFilter{})
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve project profitability : %s.", dbtest.Failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to retrieve project profitability.", dbtest.Success, testID)
+
+				if len(projects) != 1 || projects[0].Revenue != tt.revenue {
+					t.Errorf("\t%s\tTest %d:\tShould compute the revenue %v : %+v.", dbtest.Failed, testID, tt.revenue, projects)
+				} else {
+					t.Logf("\t%s\tTest %d:\tShould compute the revenue.", dbtest.Success, testID)
+				}
+			}
+		}
+	}
+}
+
+func TestMilestoneBudget(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testmilestonebudget")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to report on milestone budgets.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling two billable hours on a milestone task.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+			const q = `
+			INSERT INTO time_entries
+				(time_entry_id, description, uid, wid, pid, tid, billable, start, stop, duration, created_with, tags, dur_only, date_created, date_updated)
+			VALUES
+				('e1d4b6a2-7c3f-4b8e-9f21-3a5c7d9e0b12', 'milestone', '5cf37266-3473-4006-984f-9325122678b7',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '346efd40-6d6e-46d5-b60b-5db9fc171779', true, '2021-10-01 10:00:00', '2021-10-01 12:00:00', 7200,
+				 'test', '{}', false, '2021-10-01 12:00:00', '2021-10-01 12:00:00');
+			INSERT INTO milestones
+				(milestone_id, pid, wid, name, target_date, budget, date_created, date_updated)
+			VALUES
+				('b7f3c2d1-5e6a-4f8b-8c9d-0a1b2c3d4e5f', '45cf87a3-5915-4079-a9af-6c559239ddbf',
+				 '7da3ca14-6366-47cf-b953-f706226567d8', 'Alpha', '2021-11-01 00:00:00', 400,
+				 '2021-10-01 00:00:00', '2021-10-01 00:00:00');
+			INSERT INTO milestone_tasks
+				(task_id, milestone_id, position)
+			VALUES
+				('346efd40-6d6e-46d5-b60b-5db9fc171779', 'b7f3c2d1-5e6a-4f8b-8c9d-0a1b2c3d4e5f', 0)`
+
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert time entry and milestone : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert time entry and milestone.", dbtest.Success, testID)
+
+			budget, err := core.QueryProjectBudget(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "45cf87a3-5915-4079-a9af-6c559239ddbf", now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve project budget : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve project budget.", dbtest.Success, testID)
+
+			if len(budget.Milestones) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould have a single milestone : %d.", dbtest.Failed, testID, len(budget.Milestones))
+			}
+			t.Logf("\t%s\tTest %d:\tShould have a single milestone.", dbtest.Success, testID)
+
+			exp := MilestoneBudget{
+				ID:           "b7f3c2d1-5e6a-4f8b-8c9d-0a1b2c3d4e5f",
+				Name:         "Alpha",
+				TargetDate:   time.Date(2021, time.November, 1, 0, 0, 0, 0, time.UTC),
+				Budget:       400,
+				TrackedHours: 2,
+				TimeAmount:   160,
+				Remaining:    240,
+				SpentPercent: 40,
+			}
+			if diff := cmp.Diff(exp, budget.Milestones[0]); diff != "" {
+				t.Errorf("\t%s\tTest %d:\tShould count the task time toward its milestone. Diff:\n%s", dbtest.Failed, testID, diff)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould count the task time toward its milestone.", dbtest.Success, testID)
+			}
+		}
+	}
+}
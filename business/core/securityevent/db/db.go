@@ -0,0 +1,97 @@
+// Package db contains security event related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for security event access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// Create inserts a new security event into the database.
+func (s Store) Create(ctx context.Context, event Event) error {
+	const q = `
+	INSERT INTO security_events
+		(event_id, uid, email, ip, type, date_created)
+	VALUES
+		(:event_id, :uid, :email, :ip, :type, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, event); err != nil {
+		return fmt.Errorf("inserting security event: %w", err)
+	}
+
+	return nil
+}
+
+// QueryWorkspaceEvents retrieves a list of the security events of the owner
+// and members of a workspace from the database, latest first.
+func (s Store) QueryWorkspaceEvents(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Event, error) {
+	data := struct {
+		Offset      int    `db:"offset"`
+		RowsPerPage int    `db:"rows_per_page"`
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		Offset:      (pageNumber - 1) * rowsPerPage,
+		RowsPerPage: rowsPerPage,
+		WorkspaceID: workspaceID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		security_events
+	WHERE
+		uid IN (
+			SELECT uid FROM workspace_users WHERE wid = :workspace_id
+			UNION
+			SELECT uid FROM workspaces WHERE workspace_id = :workspace_id
+		)
+	ORDER BY
+		date_created DESC
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var events []Event
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &events); err != nil {
+		return nil, fmt.Errorf("selecting security events: %w", err)
+	}
+
+	return events, nil
+}
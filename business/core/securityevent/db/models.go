@@ -0,0 +1,22 @@
+package db
+
+import "time"
+
+// Set of security event types.
+const (
+	TypeLoginFailed     = "login_failed"
+	TypeAccountLocked   = "account_locked"
+	TypeAccountUnlocked = "account_unlocked"
+	TypeIPThrottled     = "ip_throttled"
+)
+
+// Event represent the structure we need for moving data
+// between the app and the database.
+type Event struct {
+	ID          string    `db:"event_id"`
+	UID         *string   `db:"uid"`
+	Email       string    `db:"email"`
+	IP          string    `db:"ip"`
+	Type        string    `db:"type"`
+	DateCreated time.Time `db:"date_created"`
+}
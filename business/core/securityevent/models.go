@@ -0,0 +1,34 @@
+package securityevent
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AhmedShaef/wakt/business/core/securityevent/db"
+)
+
+// Event represents a security relevant event of a user, like a failed sign
+// in or a lockout. The UID is empty for sign in attempts on unknown emails.
+type Event struct {
+	ID          string    `json:"id"`
+	UID         *string   `json:"uid"`
+	Email       string    `json:"email"`
+	IP          string    `json:"ip"`
+	Type        string    `json:"type"`
+	DateCreated time.Time `json:"date_created"`
+}
+
+// =============================================================================
+
+func toEvent(dbEvent db.Event) Event {
+	pu := (*Event)(unsafe.Pointer(&dbEvent))
+	return *pu
+}
+
+func toEventSlice(dbEvents []db.Event) []Event {
+	events := make([]Event, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = toEvent(dbEvent)
+	}
+	return events
+}
@@ -0,0 +1,55 @@
+// Package securityevent provides an example of a core business API. Right now
+// these calls are just wrapping the data/data layer. But at some point you
+// will want auditing or something that isn't specific to the data/store layer.
+package securityevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AhmedShaef/wakt/business/core/securityevent/db"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrInvalidID = errors.New("ID is not in its proper form")
+)
+
+// Set of security event types, recorded by the user core as users sign in.
+const (
+	TypeLoginFailed     = db.TypeLoginFailed
+	TypeAccountLocked   = db.TypeAccountLocked
+	TypeAccountUnlocked = db.TypeAccountUnlocked
+	TypeIPThrottled     = db.TypeIPThrottled
+)
+
+// Core manages the set of APIs for security event access.
+type Core struct {
+	store db.Store
+}
+
+// NewCore constructs a core for security event api access.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
+	return Core{
+		store: db.NewStore(log, sqlxDB),
+	}
+}
+
+// QueryWorkspaceEvents retrieves a list of the security events of the owner
+// and members of a workspace.
+func (c Core) QueryWorkspaceEvents(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Event, error) {
+	if err := validate.CheckID(workspaceID); err != nil {
+		return []Event{}, ErrInvalidID
+	}
+
+	dbEvents, err := c.store.QueryWorkspaceEvents(ctx, workspaceID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return toEventSlice(dbEvents), nil
+}
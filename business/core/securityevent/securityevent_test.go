@@ -0,0 +1,78 @@
+package securityevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/securityevent/db"
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestSecurityEvent(t *testing.T) {
+	log, sqlxDB, teardown := dbtest.NewUnit(t, c, "testsecurityevent")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, sqlxDB)
+	store := db.NewStore(log, sqlxDB)
+
+	t.Log("Given the need to work with Security Event records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling the events of a workspace.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+			adminID := "5cf37266-3473-4006-984f-9325122678b7"
+			userID := "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+			events := []db.Event{
+				{ID: "0ed4bcc7-3b4e-4d55-8f0a-8c1b7cf3a001", UID: &adminID, Email: "admin@example.com", IP: "192.0.2.1", Type: TypeLoginFailed, DateCreated: now},
+				{ID: "0ed4bcc7-3b4e-4d55-8f0a-8c1b7cf3a002", UID: &adminID, Email: "admin@example.com", IP: "192.0.2.1", Type: TypeAccountLocked, DateCreated: now.Add(time.Minute)},
+				{ID: "0ed4bcc7-3b4e-4d55-8f0a-8c1b7cf3a003", UID: &userID, Email: "user@example.com", IP: "192.0.2.1", Type: TypeLoginFailed, DateCreated: now},
+				{ID: "0ed4bcc7-3b4e-4d55-8f0a-8c1b7cf3a004", Email: "nobody@example.com", IP: "192.0.2.1", Type: TypeLoginFailed, DateCreated: now},
+			}
+			for _, event := range events {
+				if err := store.Create(ctx, event); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to create Security Event : %s.", dbtest.Failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create Security Events.", dbtest.Success, testID)
+
+			got, err := core.QueryWorkspaceEvents(ctx, workspaceID, 1, 10)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve the Security Events : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve the Security Events.", dbtest.Success, testID)
+
+			if len(got) != 2 || got[0].Type != TypeAccountLocked {
+				t.Fatalf("\t%s\tTest %d:\tShould get the events of the members only, latest first : %+v.", dbtest.Failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the events of the members only, latest first.", dbtest.Success, testID)
+
+			if _, err := core.QueryWorkspaceEvents(ctx, "bad-id", 1, 10); !errors.Is(err, ErrInvalidID) {
+				t.Fatalf("\t%s\tTest %d:\tShould reject an invalid workspace ID : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject an invalid workspace ID.", dbtest.Success, testID)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package db contains single sign-on related CRUD functionality.
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Store manages the set of APIs for single sign-on access.
+type Store struct {
+	log          *zap.SugaredLogger
+	tr           database.Transactor
+	db           sqlx.ExtContext
+	isWithinTran bool
+}
+
+// NewStore constructs a data for api access.
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) Store {
+	return Store{
+		log: log,
+		tr:  db,
+		db:  db,
+	}
+}
+
+// WithinTran runs passed function and do commit/rollback at the end.
+func (s Store) WithinTran(ctx context.Context, fn func(sqlx.ExtContext) error) error {
+	if s.isWithinTran {
+		return fn(s.db)
+	}
+	return database.WithinTran(ctx, s.log, s.tr, fn)
+}
+
+// Tran return new Store with transaction in it.
+func (s Store) Tran(tx sqlx.ExtContext) Store {
+	return Store{
+		log:          s.log,
+		tr:           s.tr,
+		db:           tx,
+		isWithinTran: true,
+	}
+}
+
+// CreateLogin inserts a new single sign-on login into the database.
+func (s Store) CreateLogin(ctx context.Context, login Login) error {
+	const q = `
+	INSERT INTO oidc_logins
+		(state_hash, nonce, verifier, date_expires, date_created)
+	VALUES
+		(:state_hash, :nonce, :verifier, :date_expires, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, login); err != nil {
+		return fmt.Errorf("inserting login: %w", err)
+	}
+
+	return nil
+}
+
+// UseLogin removes the single sign-on login of the state hash from the
+// database and returns it. It fails with database.ErrDBNotFound when there is
+// no such login.
+func (s Store) UseLogin(ctx context.Context, stateHash string) (Login, error) {
+	data := struct {
+		StateHash string `db:"state_hash"`
+	}{
+		StateHash: stateHash,
+	}
+
+	const q = `
+	DELETE FROM
+		oidc_logins
+	WHERE
+		state_hash = :state_hash
+	RETURNING
+		*`
+
+	var login Login
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &login); err != nil {
+		return Login{}, fmt.Errorf("using login: %w", err)
+	}
+
+	return login, nil
+}
+
+// DeleteExpiredLogins removes the single sign-on logins that expired before
+// the date from the database.
+func (s Store) DeleteExpiredLogins(ctx context.Context, now time.Time) error {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now,
+	}
+
+	const q = `
+	DELETE FROM
+		oidc_logins
+	WHERE
+		date_expires < :now`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting expired logins: %w", err)
+	}
+
+	return nil
+}
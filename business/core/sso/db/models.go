@@ -0,0 +1,13 @@
+package db
+
+import "time"
+
+// Login represent the structure we need for moving data
+// between the app and the database.
+type Login struct {
+	StateHash   string    `db:"state_hash"`
+	Nonce       string    `db:"nonce"`
+	Verifier    string    `db:"verifier"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+}
@@ -0,0 +1,8 @@
+package sso
+
+// Callback contains what the provider redirects back with once the user
+// signed in.
+type Callback struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
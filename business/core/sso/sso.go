@@ -0,0 +1,145 @@
+// Package sso provides an example of a core business API. Right now these
+// calls are just wrapping the data/data layer. But at some point you will
+// want auditing or something that isn't specific to the data/store layer.
+package sso
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/core/sso/db"
+	users "github.com/AhmedShaef/wakt/business/core/user/db"
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
+	"github.com/AhmedShaef/wakt/business/sys/validate"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Set of error variables for single sign-on.
+var (
+	ErrNotFound         = errors.New("no user has the email")
+	ErrInvalidState     = errors.New("login is invalid or expired")
+	ErrUnverifiedEmail  = errors.New("provider did not verify the email")
+	ErrUnverifiedUser   = errors.New("user did not verify the email")
+	ErrDomainNotAllowed = errors.New("email domain is not allowed")
+)
+
+// loginTTL is the duration a login stays valid for.
+const loginTTL = 10 * time.Minute
+
+// Core manages the set of APIs for single sign-on access.
+type Core struct {
+	store     db.Store
+	userStore users.Store
+	client    *oidc.Client
+}
+
+// NewCore constructs a core for single sign-on with the provider.
+func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB, provider oidc.Provider) Core {
+	return Core{
+		store:     db.NewStore(log, sqlxDB),
+		userStore: users.NewStore(log, sqlxDB),
+		client:    oidc.NewClient(provider),
+	}
+}
+
+// Login starts signing in with the provider and returns the URL to send the
+// user to.
+func (c Core) Login(ctx context.Context, now time.Time) (string, error) {
+	endpoints, err := c.client.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var random [3]string
+	for i := range random {
+		if random[i], err = oidc.RandomString(); err != nil {
+			return "", err
+		}
+	}
+	state, nonce, verifier := random[0], random[1], random[2]
+
+	dbLogin := db.Login{
+		StateHash:   hashState(state),
+		Nonce:       nonce,
+		Verifier:    verifier,
+		DateExpires: now.Add(loginTTL),
+		DateCreated: now,
+	}
+
+	if err := c.store.DeleteExpiredLogins(ctx, now); err != nil {
+		return "", fmt.Errorf("delete: %w", err)
+	}
+
+	if err := c.store.CreateLogin(ctx, dbLogin); err != nil {
+		return "", fmt.Errorf("create: %w", err)
+	}
+
+	return c.client.AuthCodeURL(endpoints, state, nonce, verifier), nil
+}
+
+// Callback completes signing in with the code the provider redirected back
+// with. It returns the ID of the user with the email the provider verified.
+// Users that did not verify the email with wakt are not linked, as the
+// account could have been signed up by someone else.
+func (c Core) Callback(ctx context.Context, cb Callback, now time.Time) (string, error) {
+	if err := validate.Check(cb); err != nil {
+		return "", fmt.Errorf("validating data: %w", err)
+	}
+
+	// A login can only be completed once.
+	dbLogin, err := c.store.UseLogin(ctx, hashState(cb.State))
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return "", ErrInvalidState
+		}
+		return "", fmt.Errorf("use: %w", err)
+	}
+
+	if !now.Before(dbLogin.DateExpires) {
+		return "", ErrInvalidState
+	}
+
+	endpoints, err := c.client.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	idClaims, err := c.client.Exchange(ctx, endpoints, cb.Code, dbLogin.Verifier, dbLogin.Nonce)
+	if err != nil {
+		return "", err
+	}
+
+	if !idClaims.EmailVerified {
+		return "", ErrUnverifiedEmail
+	}
+
+	if !c.client.AllowedEmail(idClaims.Email) {
+		return "", ErrDomainNotAllowed
+	}
+
+	dbUser, err := c.userStore.QueryByEmail(ctx, idClaims.Email)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("query: %w", err)
+	}
+
+	if !dbUser.Verified {
+		return "", ErrUnverifiedUser
+	}
+
+	return dbUser.ID, nil
+}
+
+// hashState returns the hash of a state that is stored in its place.
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
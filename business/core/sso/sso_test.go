@@ -0,0 +1,118 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/data/dbtest"
+	"github.com/AhmedShaef/wakt/business/sys/oidc/oidctest"
+	"github.com/AhmedShaef/wakt/foundation/docker"
+	"github.com/AhmedShaef/wakt/foundation/keystore"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func TestSSO(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testsso")
+	t.Cleanup(teardown)
+
+	ks, err := keystore.NewFS(os.DirFS("../../../foundation/keystore"))
+	if err != nil {
+		t.Fatalf("Should be able to read the keys : %s.", err)
+	}
+
+	idp, err := oidctest.New(ks, "test", "wakt", "s3cr3t")
+	if err != nil {
+		t.Fatalf("Should be able to start the provider : %s.", err)
+	}
+	t.Cleanup(idp.Close)
+
+	core := NewCore(log, db, idp.Provider("http://localhost:3000/v1/oidc/callback", "example.com"))
+
+	t.Log("Given the need to sign in with an OpenID Connect provider.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a user linked by email.", testID)
+		{
+			ctx := context.Background()
+			now := time.Now().UTC()
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+			authURL, err := core.Login(ctx, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to start a login : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to start a login.", dbtest.Success, testID)
+
+			code, state, err := idp.Authorize(authURL, "user@example.com", true)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authorize : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.Callback(ctx, Callback{State: state, Code: code}, now); !errors.Is(err, ErrUnverifiedUser) {
+				t.Fatalf("\t%s\tTest %d:\tShould not link a user that did not verify the email : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not link a user that did not verify the email.", dbtest.Success, testID)
+
+			if _, err := db.ExecContext(ctx, `UPDATE users SET verified = true WHERE user_id = $1`, userID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to verify the user : %s.", dbtest.Failed, testID, err)
+			}
+
+			authURL, err = core.Login(ctx, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to start a login : %s.", dbtest.Failed, testID, err)
+			}
+
+			code, state, err = idp.Authorize(authURL, "user@example.com", true)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authorize : %s.", dbtest.Failed, testID, err)
+			}
+
+			linked, err := core.Callback(ctx, Callback{State: state, Code: code}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to complete the login : %s.", dbtest.Failed, testID, err)
+			}
+			if linked != userID {
+				t.Fatalf("\t%s\tTest %d:\tShould sign in the user of the email : %s.", dbtest.Failed, testID, linked)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to complete the login.", dbtest.Success, testID)
+
+			if _, err := core.Callback(ctx, Callback{State: state, Code: code}, now); !errors.Is(err, ErrInvalidState) {
+				t.Fatalf("\t%s\tTest %d:\tShould not complete a login twice : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not complete a login twice.", dbtest.Success, testID)
+
+			authURL, err = core.Login(ctx, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to start a login : %s.", dbtest.Failed, testID, err)
+			}
+
+			code, state, err = idp.Authorize(authURL, "user@example.com", false)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authorize : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.Callback(ctx, Callback{State: state, Code: code}, now); !errors.Is(err, ErrUnverifiedEmail) {
+				t.Fatalf("\t%s\tTest %d:\tShould not link an unverified email : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not link an unverified email.", dbtest.Success, testID)
+		}
+	}
+}
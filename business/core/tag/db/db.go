@@ -153,3 +153,63 @@ func (s Store) QueryWorkspaceTags(ctx context.Context, workspaceID string, pageN
 
 	return tags, nil
 }
+
+// RenameInEntries replaces a tag name on every time entry of a workspace,
+// dropping the old name where the entry already carries the new one.
+func (s Store) RenameInEntries(ctx context.Context, workspaceID, oldName, newName string) error {
+	data := struct {
+		WorkspaceID string `db:"workspace_id"`
+		OldName     string `db:"old_name"`
+		NewName     string `db:"new_name"`
+	}{
+		WorkspaceID: workspaceID,
+		OldName:     oldName,
+		NewName:     newName,
+	}
+
+	const q = `
+	UPDATE
+		time_entries
+	SET
+		tags = ARRAY(
+			SELECT u.tag
+			FROM unnest(array_replace(tags, CAST(:old_name AS TEXT), CAST(:new_name AS TEXT))) WITH ORDINALITY AS u(tag, n)
+			GROUP BY u.tag
+			ORDER BY MIN(u.n)
+		)
+	WHERE
+		wid = :workspace_id AND
+		CAST(:old_name AS TEXT) = ANY(tags)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("renaming tag[%s] on time entries: %w", oldName, err)
+	}
+
+	return nil
+}
+
+// RemoveFromEntries removes a tag name from every time entry of a workspace.
+func (s Store) RemoveFromEntries(ctx context.Context, workspaceID, name string) error {
+	data := struct {
+		WorkspaceID string `db:"workspace_id"`
+		Name        string `db:"name"`
+	}{
+		WorkspaceID: workspaceID,
+		Name:        name,
+	}
+
+	const q = `
+	UPDATE
+		time_entries
+	SET
+		tags = array_remove(tags, CAST(:name AS TEXT))
+	WHERE
+		wid = :workspace_id AND
+		CAST(:name AS TEXT) = ANY(tags)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("removing tag[%s] from time entries: %w", name, err)
+	}
+
+	return nil
+}
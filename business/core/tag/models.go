@@ -31,6 +31,11 @@ type UpdateTag struct {
 	Name *string `json:"name"`
 }
 
+// MergeTags contains the tags to be merged into another tag.
+type MergeTags struct {
+	Tags []string `json:"tags" validate:"required,min=1,unique,dive,uuid"`
+}
+
 // =============================================================================
 
 func toTag(dbTag db.Tag) Tag {
@@ -17,8 +17,11 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound  = errors.New("tag not found")
-	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrNotFound        = errors.New("tag not found")
+	ErrInvalidID       = errors.New("ID is not in its proper form")
+	ErrUniqueName      = errors.New("tag name is not unique")
+	ErrOtherWorkspace  = errors.New("tag belongs to another workspace")
+	ErrMergeIntoItself = errors.New("tag can not be merged into itself")
 )
 
 // Core manages the set of APIs for user access.
@@ -48,6 +51,9 @@ func (c Core) Create(ctx context.Context, nt NewTag, now time.Time) (Tag, error)
 	}
 
 	if err := c.store.Create(ctx, dbtg); err != nil {
+		if errors.Is(err, database.ErrDBDuplicatedEntry) {
+			return Tag{}, fmt.Errorf("create: %w", ErrUniqueName)
+		}
 		return Tag{}, fmt.Errorf("create: %w", err)
 	}
 
@@ -72,12 +78,32 @@ func (c Core) Update(ctx context.Context, tagID string, ut UpdateTag, now time.T
 		return fmt.Errorf("updating tag tagID[%s]: %w", tagID, err)
 	}
 
+	oldName := dbtg.Name
 	if ut.Name != nil {
 		dbtg.Name = *ut.Name
 	}
 	dbtg.DateUpdated = now
-	if err := c.store.Update(ctx, dbtg); err != nil {
-		return fmt.Errorf("udpate: %w", err)
+
+	// The time entries carry the tag by name, so a rename has to be
+	// applied to them in the same transaction.
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.Update(ctx, dbtg); err != nil {
+			if errors.Is(err, database.ErrDBDuplicatedEntry) {
+				return fmt.Errorf("udpate: %w", ErrUniqueName)
+			}
+			return fmt.Errorf("udpate: %w", err)
+		}
+		if oldName != dbtg.Name {
+			if err := store.RenameInEntries(ctx, dbtg.WID, oldName, dbtg.Name); err != nil {
+				return fmt.Errorf("udpate: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
@@ -89,13 +115,95 @@ func (c Core) Delete(ctx context.Context, tagID string) error {
 		return ErrInvalidID
 	}
 
-	if err := c.store.Delete(ctx, tagID); err != nil {
-		return fmt.Errorf("delete: %w", err)
+	dbtg, err := c.store.QueryByID(ctx, tagID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return nil
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.RemoveFromEntries(ctx, dbtg.WID, dbtg.Name); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		if err := store.Delete(ctx, tagID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
 }
 
+// Merge folds the given tags into the specified tag. Every time entry
+// carrying one of the merged tags is rewritten to the name of the target tag
+// and the merged tags are removed.
+func (c Core) Merge(ctx context.Context, tagID string, mt MergeTags, now time.Time) (Tag, error) {
+	if err := validate.CheckID(tagID); err != nil {
+		return Tag{}, ErrInvalidID
+	}
+
+	if err := validate.Check(mt); err != nil {
+		return Tag{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	target, err := c.store.QueryByID(ctx, tagID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Tag{}, ErrNotFound
+		}
+		return Tag{}, fmt.Errorf("query: %w", err)
+	}
+
+	sources := make([]db.Tag, len(mt.Tags))
+	for i, id := range mt.Tags {
+		if id == tagID {
+			return Tag{}, ErrMergeIntoItself
+		}
+		dbtg, err := c.store.QueryByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return Tag{}, ErrNotFound
+			}
+			return Tag{}, fmt.Errorf("query: %w", err)
+		}
+		if dbtg.WID != target.WID {
+			return Tag{}, ErrOtherWorkspace
+		}
+		sources[i] = dbtg
+	}
+
+	target.DateUpdated = now
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		for _, source := range sources {
+			if err := store.RenameInEntries(ctx, target.WID, source.Name, target.Name); err != nil {
+				return fmt.Errorf("merge: %w", err)
+			}
+			if err := store.Delete(ctx, source.ID); err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+		if err := store.Update(ctx, target); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return Tag{}, fmt.Errorf("tran: %w", err)
+	}
+
+	return toTag(target), nil
+}
+
 // QueryByID gets the specified tag from the database.
 func (c Core) QueryByID(ctx context.Context, tagID string) (Tag, error) {
 	if err := validate.CheckID(tagID); err != nil {
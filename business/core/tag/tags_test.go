@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/AhmedShaef/wakt/business/core/timeentry"
 	"github.com/AhmedShaef/wakt/business/data/dbschema"
 	"github.com/AhmedShaef/wakt/business/data/dbtest"
 	"github.com/AhmedShaef/wakt/foundation/docker"
@@ -142,3 +143,73 @@ func TestPagingTag(t *testing.T) {
 		}
 	}
 }
+
+func TestTagEntries(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testtagentries")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+	timeEntryCore := timeentry.NewCore(log, db)
+
+	const workspaceID = "7da3ca14-6366-47cf-b953-f706226567d8"
+	const timeEntryID = "57a785f7-aff5-40a6-8b98-fc28e0f0465c"
+
+	t.Log("Given the need to keep time entry tags in sync with tag records.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen renaming, merging and deleting tags.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			tag1, err := core.Create(ctx, NewTag{Name: "tag1", WID: workspaceID}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create tag : %s.", dbtest.Failed, testID, err)
+			}
+			tag2, err := core.Create(ctx, NewTag{Name: "tag2", WID: workspaceID}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create tag : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create tags.", dbtest.Success, testID)
+
+			if _, err := core.Create(ctx, NewTag{Name: "tag1", WID: workspaceID}, now); !errors.Is(err, ErrUniqueName) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to create a tag with a taken name : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to create a tag with a taken name.", dbtest.Success, testID)
+
+			if err := core.Update(ctx, tag1.ID, UpdateTag{Name: dbtest.StringPointer("tag3")}, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to rename tag : %s.", dbtest.Failed, testID, err)
+			}
+			checkEntryTags(t, testID, timeEntryCore, timeEntryID, []string{"tag3", "tag2"})
+			t.Logf("\t%s\tTest %d:\tShould see the rename on time entries.", dbtest.Success, testID)
+
+			if _, err := core.Merge(ctx, tag1.ID, MergeTags{Tags: []string{tag2.ID}}, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to merge tags : %s.", dbtest.Failed, testID, err)
+			}
+			if _, err := core.QueryByID(ctx, tag2.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to retrieve merged tag : %s.", dbtest.Failed, testID, err)
+			}
+			checkEntryTags(t, testID, timeEntryCore, timeEntryID, []string{"tag3"})
+			t.Logf("\t%s\tTest %d:\tShould see the merge on time entries.", dbtest.Success, testID)
+
+			if err := core.Delete(ctx, tag1.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete tag : %s.", dbtest.Failed, testID, err)
+			}
+			checkEntryTags(t, testID, timeEntryCore, timeEntryID, []string{})
+			t.Logf("\t%s\tTest %d:\tShould see the delete on time entries.", dbtest.Success, testID)
+		}
+	}
+}
+
+func checkEntryTags(t *testing.T, testID int, core timeentry.Core, timeEntryID string, exp []string) {
+	t.Helper()
+
+	te, err := core.QueryByID(context.Background(), timeEntryID)
+	if err != nil {
+		t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve time entry : %s.", dbtest.Failed, testID, err)
+	}
+
+	if diff := cmp.Diff(exp, te.Tags); diff != "" {
+		t.Fatalf("\t%s\tTest %d:\tShould get back the expected tags. Diff:\n%s", dbtest.Failed, testID, diff)
+	}
+}
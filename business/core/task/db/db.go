@@ -7,8 +7,10 @@ import (
 
 	"go.uber.org/zap"
 
+	dbp "github.com/AhmedShaef/wakt/business/core/project/db"
 	"github.com/AhmedShaef/wakt/business/sys/database"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // Store manages the set of APIs for user access.
@@ -46,13 +48,17 @@ func (s Store) Tran(tx sqlx.ExtContext) Store {
 	}
 }
 
+// visible filters the tasks a user may see, the ones of private projects are
+// reserved to the project team and the workspace admins.
+var visible = dbp.VisibleThrough("tasks.pid")
+
 // Create inserts a new task into the database.
 func (s Store) Create(ctx context.Context, task Task) error {
 	const q = `
 	INSERT INTO tasks
-		(task_id, name, pid, wid, uid, estimated_seconds, active, date_created, date_updated, tracked_seconds)
+		(task_id, name, pid, wid, uid, estimated_seconds, active, date_created, date_updated, tracked_seconds, assignees, status, due_date, priority, parent_id)
 	VALUES
-		(:task_id, :name, :pid, :wid, :uid, :estimated_seconds, :active, :date_created, :date_updated, :tracked_seconds)`
+		(:task_id, :name, :pid, :wid, :uid, :estimated_seconds, :active, :date_created, :date_updated, :tracked_seconds, :assignees, :status, :due_date, :priority, :parent_id)`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, task); err != nil {
 		return fmt.Errorf("inserting task: %w", err)
@@ -71,7 +77,12 @@ func (s Store) Update(ctx context.Context, task Task) error {
 		"estimated_seconds" = :estimated_seconds,
 		"active" = :active,
 		"date_updated" = :date_updated,
-		"tracked_seconds" = :tracked_seconds
+		"tracked_seconds" = :tracked_seconds,
+		"assignees" = :assignees,
+		"status" = :status,
+		"due_date" = :due_date,
+		"priority" = :priority,
+		"parent_id" = :parent_id
 	WHERE
 		task_id = :task_id`
 
@@ -186,25 +197,28 @@ func (s Store) QueryProjectTasks(ctx context.Context, projectID string, pageNumb
 	return tasks, nil
 }
 
-// QueryWorkspaceTasks retrieves a list of existing task from the database.
-func (s Store) QueryWorkspaceTasks(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Task, error) {
+// QueryWorkspaceTasks retrieves a list of existing task the user may see from
+// the database.
+func (s Store) QueryWorkspaceTasks(ctx context.Context, workspaceID, userID string, pageNumber, rowsPerPage int) ([]Task, error) {
 	data := struct {
 		Offset      int    `db:"offset"`
 		RowsPerPage int    `db:"rows_per_page"`
 		WorkspaceID string `db:"workspace_id"`
+		UserID      string `db:"user_id"`
 	}{
 		Offset:      (pageNumber - 1) * rowsPerPage,
 		RowsPerPage: rowsPerPage,
 		WorkspaceID: workspaceID,
+		UserID:      userID,
 	}
 
-	const q = `
+	q := `
 	SELECT
 		*
 	FROM
 		tasks
 	WHERE
-		wid = :workspace_id
+		wid = :workspace_id AND ` + visible + `
 	ORDER BY
 		task_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -216,3 +230,223 @@ func (s Store) QueryWorkspaceTasks(ctx context.Context, workspaceID string, page
 
 	return tasks, nil
 }
+
+// QueryByProject retrieves all the tasks of a project from the database.
+func (s Store) QueryByProject(ctx context.Context, projectID string) ([]Task, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		tasks
+	WHERE
+		pid = :project_id
+	ORDER BY
+		name`
+
+	var tasks []Task
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &tasks); err != nil {
+		return nil, fmt.Errorf("selecting project tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// QueryWorkflow gets the task statuses of the workspace of the specified
+// project from the database. It falls back to the default workflow when the
+// project or workspace is unknown.
+func (s Store) QueryWorkflow(ctx context.Context, projectID string) ([]string, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		COALESCE((
+			SELECT
+				w.task_statuses
+			FROM
+				projects AS p
+			JOIN
+				workspaces AS w ON w.workspace_id = p.wid
+			WHERE
+				p.project_id = :project_id
+		), '{todo,in_progress,in_review,done}') AS task_statuses`
+
+	var workflow struct {
+		Statuses pq.StringArray `db:"task_statuses"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &workflow); err != nil {
+		return nil, fmt.Errorf("selecting workflow projectID[%q]: %w", projectID, err)
+	}
+
+	return workflow.Statuses, nil
+}
+
+// QueryUserOpenTasks retrieves the active tasks assigned to a user that are
+// not in the last status of their workspace workflow from the database. Tasks
+// of private projects the user may no longer see are left out.
+func (s Store) QueryUserOpenTasks(ctx context.Context, userID string) ([]Task, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	q := `
+	SELECT
+		tasks.*
+	FROM
+		tasks
+	LEFT JOIN
+		workspaces AS w ON w.workspace_id = tasks.wid
+	WHERE
+		:user_id = ANY(tasks.assignees) AND tasks.active = true AND
+		tasks.status <> COALESCE(w.task_statuses[array_length(w.task_statuses, 1)], 'done') AND ` + visible + `
+	ORDER BY
+		tasks.due_date NULLS LAST, tasks.priority DESC, tasks.name`
+
+	var tasks []Task
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &tasks); err != nil {
+		return nil, fmt.Errorf("selecting user tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// CreateDependency inserts a new task dependency into the database.
+func (s Store) CreateDependency(ctx context.Context, dependency Dependency) error {
+	const q = `
+	INSERT INTO task_dependencies
+		(task_id, blocked_by, date_created)
+	VALUES
+		(:task_id, :blocked_by, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, dependency); err != nil {
+		return fmt.Errorf("inserting dependency: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDependency removes a task dependency from the database.
+func (s Store) DeleteDependency(ctx context.Context, taskID, blockedBy string) error {
+	data := struct {
+		TaskID    string `db:"task_id"`
+		BlockedBy string `db:"blocked_by"`
+	}{
+		TaskID:    taskID,
+		BlockedBy: blockedBy,
+	}
+
+	const q = `
+	DELETE FROM
+		task_dependencies
+	WHERE
+		task_id = :task_id AND blocked_by = :blocked_by`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting dependency taskID[%s]: %w", taskID, err)
+	}
+
+	return nil
+}
+
+// Detach removes the dependencies of a task and moves its subtasks to the top
+// level in the database.
+func (s Store) Detach(ctx context.Context, taskID string) error {
+	data := struct {
+		TaskID string `db:"task_id"`
+	}{
+		TaskID: taskID,
+	}
+
+	const q = `
+	DELETE FROM
+		task_dependencies
+	WHERE
+		task_id = :task_id OR blocked_by = :task_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting dependencies taskID[%s]: %w", taskID, err)
+	}
+
+	const qs = `
+	UPDATE
+		tasks
+	SET
+		"parent_id" = NULL
+	WHERE
+		parent_id = :task_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, qs, data); err != nil {
+		return fmt.Errorf("detaching subtasks taskID[%s]: %w", taskID, err)
+	}
+
+	return nil
+}
+
+// QueryProjectDependencies retrieves the dependencies between the tasks of a
+// project from the database.
+func (s Store) QueryProjectDependencies(ctx context.Context, projectID string) ([]Dependency, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		d.*
+	FROM
+		task_dependencies AS d
+	JOIN
+		tasks AS t ON t.task_id = d.task_id
+	WHERE
+		t.pid = :project_id
+	ORDER BY
+		d.task_id, d.blocked_by`
+
+	var dependencies []Dependency
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dependencies); err != nil {
+		return nil, fmt.Errorf("selecting dependencies: %w", err)
+	}
+
+	return dependencies, nil
+}
+
+// QueryBlockers retrieves the tasks the specified task is blocked by from the
+// database.
+func (s Store) QueryBlockers(ctx context.Context, taskID string) ([]Task, error) {
+	data := struct {
+		TaskID string `db:"task_id"`
+	}{
+		TaskID: taskID,
+	}
+
+	const q = `
+	SELECT
+		t.*
+	FROM
+		tasks AS t
+	JOIN
+		task_dependencies AS d ON d.blocked_by = t.task_id
+	WHERE
+		d.task_id = :task_id
+	ORDER BY
+		t.name`
+
+	var tasks []Task
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &tasks); err != nil {
+		return nil, fmt.Errorf("selecting blockers: %w", err)
+	}
+
+	return tasks, nil
+}
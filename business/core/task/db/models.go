@@ -2,19 +2,35 @@ package db
 
 import (
 	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/lib/pq"
 )
 
 // Task represent the structure we need for moving data
 // between the app and the database.
 type Task struct {
-	ID          string        `db:"task_id"`
-	Name        string        `db:"name"`
-	PID         string        `db:"pid"`
-	WID         string        `db:"wid"`
-	UID         string        `db:"uid"`
-	Estimated   time.Duration `db:"estimated_seconds"`
-	Active      bool          `db:"active"`
-	DateCreated time.Time     `db:"date_created"`
-	DateUpdated time.Time     `db:"date_updated"`
-	Tracked     time.Duration `db:"tracked_seconds"`
+	ID          string          `db:"task_id"`
+	Name        string          `db:"name"`
+	PID         string          `db:"pid"`
+	WID         string          `db:"wid"`
+	UID         string          `db:"uid"`
+	Estimated   time.Duration   `db:"estimated_seconds"`
+	Active      bool            `db:"active"`
+	DateCreated time.Time       `db:"date_created"`
+	DateUpdated time.Time       `db:"date_updated"`
+	Tracked     time.Duration   `db:"tracked_seconds"`
+	Assignees   pq.StringArray  `db:"assignees"`
+	Status      string          `db:"status"`
+	DueDate     *time.Time      `db:"due_date"`
+	Priority    int             `db:"priority"`
+	ParentID    database.NullID `db:"parent_id"`
+}
+
+// Dependency represent the structure we need for moving data
+// between the app and the database.
+type Dependency struct {
+	TaskID      string    `db:"task_id"`
+	BlockedBy   string    `db:"blocked_by"`
+	DateCreated time.Time `db:"date_created"`
 }
@@ -7,7 +7,19 @@ import (
 	"github.com/AhmedShaef/wakt/business/core/task/db"
 )
 
-// Task represents an individual task.
+// Set of priorities a task can have.
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+// Task represents an individual task. Status is one of the task statuses of the
+// workspace workflow, the last status of the workflow marks the task as done.
+// A subtask points to its parent task with ParentID and its tracked time rolls
+// up into the tracked time of the parent.
 type Task struct {
 	ID          string        `json:"id"`
 	Name        string        `json:"name"`
@@ -19,6 +31,11 @@ type Task struct {
 	DateCreated time.Time     `json:"date_created"`
 	DateUpdated time.Time     `json:"date_updated"`
 	Tracked     time.Duration `json:"tracked_seconds"`
+	Assignees   []string      `json:"assignees"`
+	Status      string        `json:"status"`
+	DueDate     *time.Time    `json:"due_date"`
+	Priority    int           `json:"priority"`
+	ParentID    string        `json:"parent_id"`
 }
 
 // NewTask contains information needed to create a new task.
@@ -29,6 +46,11 @@ type NewTask struct {
 	UID       string        `json:"uid"`
 	Estimated time.Duration `json:"estimated_seconds"`
 	Tracked   time.Duration `json:"tracked_seconds"`
+	Assignees []string      `json:"assignees" validate:"omitempty,dive,uuid"`
+	Status    string        `json:"status"`
+	DueDate   *time.Time    `json:"due_date"`
+	Priority  int           `json:"priority" validate:"gte=0,lte=4"`
+	ParentID  string        `json:"parent_id"`
 }
 
 // UpdateTask defines what information may be provided to modify an existing
@@ -38,10 +60,52 @@ type NewTask struct {
 // we do not want to use pointers to basic types ,but we make exceptions around
 // marshalling/unmarshalling.
 type UpdateTask struct {
-	Name             *string        `json:"name"`
+	Name      *string        `json:"name"`
 	Estimated *time.Duration `json:"estimated_seconds"`
-	Active           *bool          `json:"active"`
+	Active    *bool          `json:"active"`
 	Tracked   *time.Duration `json:"tracked_seconds"`
+	Assignees []string       `json:"assignees" validate:"omitempty,dive,uuid"`
+	Status    *string        `json:"status"`
+	DueDate   *time.Time     `json:"due_date"`
+	Priority  *int           `json:"priority" validate:"omitempty,gte=0,lte=4"`
+	ParentID  *string        `json:"parent_id"`
+}
+
+// Dependency represents a task that is blocked by another task of the same
+// project.
+type Dependency struct {
+	TaskID      string    `json:"task_id"`
+	BlockedBy   string    `json:"blocked_by"`
+	DateCreated time.Time `json:"date_created"`
+}
+
+// NewDependency contains information needed to block a task by another task.
+type NewDependency struct {
+	BlockedBy string `json:"blocked_by" validate:"required"`
+}
+
+// Graph represents the tasks of a project with the dependencies between them.
+type Graph struct {
+	Tasks        []Task       `json:"tasks"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// Column represents the tasks of a project in one status of the workflow.
+type Column struct {
+	Status    string        `json:"status"`
+	Count     int           `json:"count"`
+	Estimated time.Duration `json:"estimated_seconds"`
+	Tracked   time.Duration `json:"tracked_seconds"`
+	Tasks     []Task        `json:"tasks"`
+}
+
+// Assigned represents the open tasks assigned to a user across projects.
+type Assigned struct {
+	Count     int           `json:"count"`
+	Overdue   int           `json:"overdue"`
+	Estimated time.Duration `json:"estimated_seconds"`
+	Tracked   time.Duration `json:"tracked_seconds"`
+	Tasks     []Task        `json:"tasks"`
 }
 
 // =============================================================================
@@ -58,3 +122,16 @@ func toTasksSlice(dbtask []db.Task) []Task {
 	}
 	return tasks
 }
+
+func toDependency(dbDependency db.Dependency) Dependency {
+	pu := (*Dependency)(unsafe.Pointer(&dbDependency))
+	return *pu
+}
+
+func toDependencySlice(dbDependencies []db.Dependency) []Dependency {
+	dependencies := make([]Dependency, len(dbDependencies))
+	for i, dbDependency := range dbDependencies {
+		dependencies[i] = toDependency(dbDependency)
+	}
+	return dependencies
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/AhmedShaef/wakt/business/core/task/db"
+	dbte "github.com/AhmedShaef/wakt/business/core/timeentry/db"
 	"github.com/AhmedShaef/wakt/business/sys/database"
 	"github.com/AhmedShaef/wakt/business/sys/validate"
 	"github.com/jmoiron/sqlx"
@@ -20,17 +21,31 @@ import (
 var (
 	ErrNotFound  = errors.New("user not found")
 	ErrInvalidID = errors.New("ID is not in its proper form")
+
+	ErrInvalidStatus = errors.New("status is not part of the workspace workflow")
+	ErrOtherProject  = errors.New("tasks are not in the same project")
+	ErrCycle         = errors.New("task would depend on itself")
+	ErrBlocked       = errors.New("task is blocked by open tasks")
+	ErrDependency    = errors.New("dependency already exists")
+
+	ErrInUse             = errors.New("task still has tracked time or expenses")
+	ErrReferenceNotFound = errors.New("referenced project or task not found")
 )
 
+// noTask is the reference of a task without a parent.
+const noTask = database.NoID
+
 // Core manages the set of APIs for user access.
 type Core struct {
-	store db.Store
+	store   db.Store
+	entries dbte.Store
 }
 
 // NewCore constructs a core for user api access.
 func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
 	return Core{
-		store: db.NewStore(log, sqlxDB),
+		store:   db.NewStore(log, sqlxDB),
+		entries: dbte.NewStore(log, sqlxDB),
 	}
 }
 
@@ -60,16 +75,48 @@ func (c Core) Create(ctx context.Context, userID string, nt NewTask, now time.Ti
 		DateCreated: now,
 		DateUpdated: now,
 		Tracked:     nt.Tracked,
+		Assignees:   nt.Assignees,
+		Status:      nt.Status,
+		DueDate:     nt.DueDate,
+		Priority:    nt.Priority,
+		ParentID:    database.NullID(nt.ParentID),
+	}
+
+	if dbtask.Assignees == nil {
+		dbtask.Assignees = []string{}
+	}
+
+	if dbtask.ParentID == "" {
+		dbtask.ParentID = noTask
+	}
+	if err := c.checkParent(ctx, dbtask.ID, string(dbtask.ParentID), dbtask.PID); err != nil {
+		return Task{}, err
+	}
+
+	workflow, err := c.store.QueryWorkflow(ctx, nt.PID)
+	if err != nil {
+		return Task{}, fmt.Errorf("query workflow: %w", err)
+	}
+
+	if dbtask.Status == "" {
+		dbtask.Status = workflow[0]
+	}
+	if !contains(workflow, dbtask.Status) {
+		return Task{}, ErrInvalidStatus
 	}
 
 	if err := c.store.Create(ctx, dbtask); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return Task{}, ErrReferenceNotFound
+		}
 		return Task{}, fmt.Errorf("create: %w", err)
 	}
 
 	return toTask(dbtask), nil
 }
 
-// Update replaces a task document in the database.
+// Update replaces a task document in the database. Moving the task to another
+// parent recomputes the tracked time of the old and the new parents.
 func (c Core) Update(ctx context.Context, taskID string, uc UpdateTask, now time.Time) error {
 	if err := validate.CheckID(taskID); err != nil {
 		return ErrInvalidID
@@ -86,6 +133,7 @@ func (c Core) Update(ctx context.Context, taskID string, uc UpdateTask, now time
 		}
 		return fmt.Errorf("updating task taskID[%s]: %w", taskID, err)
 	}
+	oldParentID := string(dbtask.ParentID)
 
 	if uc.Name != nil {
 		dbtask.Name = *uc.Name
@@ -99,23 +147,113 @@ func (c Core) Update(ctx context.Context, taskID string, uc UpdateTask, now time
 	if uc.Tracked != nil {
 		dbtask.Tracked = *uc.Tracked
 	}
+	if uc.Assignees != nil {
+		dbtask.Assignees = uc.Assignees
+	}
+	if uc.DueDate != nil {
+		dbtask.DueDate = uc.DueDate
+	}
+	if uc.Priority != nil {
+		dbtask.Priority = *uc.Priority
+	}
+	if uc.ParentID != nil {
+		dbtask.ParentID = database.NullID(*uc.ParentID)
+		if dbtask.ParentID == "" {
+			dbtask.ParentID = noTask
+		}
+		if err := c.checkParent(ctx, dbtask.ID, string(dbtask.ParentID), dbtask.PID); err != nil {
+			return err
+		}
+	}
+	if uc.Status != nil {
+		workflow, err := c.store.QueryWorkflow(ctx, dbtask.PID)
+		if err != nil {
+			return fmt.Errorf("query workflow: %w", err)
+		}
+		if !contains(workflow, *uc.Status) {
+			return ErrInvalidStatus
+		}
+
+		// A task can only be done once all the tasks blocking it are done.
+		done := workflow[len(workflow)-1]
+		if *uc.Status == done {
+			blockers, err := c.store.QueryBlockers(ctx, dbtask.ID)
+			if err != nil {
+				return fmt.Errorf("query blockers: %w", err)
+			}
+			for _, blocker := range blockers {
+				if blocker.Status != done {
+					return ErrBlocked
+				}
+			}
+		}
+		dbtask.Status = *uc.Status
+	}
 	dbtask.DateUpdated = now
 
-	if err := c.store.Update(ctx, dbtask); err != nil {
-		return fmt.Errorf("udpate: %w", err)
+	tran := func(tx sqlx.ExtContext) error {
+		if err := c.store.Tran(tx).Update(ctx, dbtask); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+
+		if string(dbtask.ParentID) == oldParentID {
+			return nil
+		}
+
+		entries := c.entries.Tran(tx)
+		for _, parentID := range []string{oldParentID, string(dbtask.ParentID)} {
+			if err := entries.SyncTaskTime(ctx, parentID, now); err != nil {
+				return fmt.Errorf("sync: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return ErrReferenceNotFound
+		}
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
 }
 
-// Delete removes a task from the database.
-func (c Core) Delete(ctx context.Context, taskID string) error {
+// Delete removes a task from the database. The tracked time of its parents is
+// recomputed without the task and its subtasks, which are left without a
+// parent.
+func (c Core) Delete(ctx context.Context, taskID string, now time.Time) error {
 	if err := validate.CheckID(taskID); err != nil {
 		return ErrInvalidID
 	}
 
-	if err := c.store.Delete(ctx, taskID); err != nil {
-		return fmt.Errorf("delete: %w", err)
+	dbtask, err := c.store.QueryByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		if err := c.store.Tran(tx).Detach(ctx, taskID); err != nil {
+			return fmt.Errorf("detach: %w", err)
+		}
+		if err := c.store.Tran(tx).Delete(ctx, taskID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		if err := c.entries.Tran(tx).SyncTaskTime(ctx, string(dbtask.ParentID), now); err != nil {
+			return fmt.Errorf("sync: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, database.ErrDBReferenced) {
+			return ErrInUse
+		}
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
@@ -152,13 +290,249 @@ func (c Core) QueryProjectTasks(ctx context.Context, projectID string, pageNumbe
 }
 
 // QueryWorkspaceTasks retrieves a list of existing workspace from the database.
-func (c Core) QueryWorkspaceTasks(ctx context.Context, workspaceID string, pageNumber, rowsPerPage int) ([]Task, error) {
+// Tasks of private projects the user may not see are left out.
+func (c Core) QueryWorkspaceTasks(ctx context.Context, workspaceID, userID string, pageNumber, rowsPerPage int) ([]Task, error) {
 	if err := validate.CheckID(workspaceID); err != nil {
 		return []Task{}, ErrInvalidID
 	}
-	dbTasks, err := c.store.QueryWorkspaceTasks(ctx, workspaceID, pageNumber, rowsPerPage)
+	dbTasks, err := c.store.QueryWorkspaceTasks(ctx, workspaceID, userID, pageNumber, rowsPerPage)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 	return toTasksSlice(dbTasks), nil
 }
+
+// QueryBoard retrieves the tasks of a project grouped by the statuses of the
+// workspace workflow. Tasks in a status that was removed from the workflow are
+// grouped after the workflow statuses.
+func (c Core) QueryBoard(ctx context.Context, projectID string) ([]Column, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return []Column{}, ErrInvalidID
+	}
+
+	workflow, err := c.store.QueryWorkflow(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query workflow: %w", err)
+	}
+
+	dbtasks, err := c.store.QueryByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	columns := make([]Column, len(workflow))
+	index := make(map[string]int, len(workflow))
+	for i, status := range workflow {
+		columns[i] = Column{Status: status, Tasks: []Task{}}
+		index[status] = i
+	}
+
+	for _, dbtask := range dbtasks {
+		i, ok := index[dbtask.Status]
+		if !ok {
+			i = len(columns)
+			index[dbtask.Status] = i
+			columns = append(columns, Column{Status: dbtask.Status, Tasks: []Task{}})
+		}
+		columns[i].Count++
+		columns[i].Estimated += dbtask.Estimated
+		columns[i].Tracked += dbtask.Tracked
+		columns[i].Tasks = append(columns[i].Tasks, toTask(dbtask))
+	}
+
+	return columns, nil
+}
+
+// QueryUserTasks retrieves the open tasks assigned to a user across projects,
+// ordered by due date and priority.
+func (c Core) QueryUserTasks(ctx context.Context, userID string, now time.Time) (Assigned, error) {
+	if err := validate.CheckID(userID); err != nil {
+		return Assigned{}, ErrInvalidID
+	}
+
+	dbtasks, err := c.store.QueryUserOpenTasks(ctx, userID)
+	if err != nil {
+		return Assigned{}, fmt.Errorf("query: %w", err)
+	}
+
+	assigned := Assigned{
+		Tasks: toTasksSlice(dbtasks),
+	}
+	for _, dbtask := range dbtasks {
+		assigned.Count++
+		assigned.Estimated += dbtask.Estimated
+		assigned.Tracked += dbtask.Tracked
+		if dbtask.DueDate != nil && dbtask.DueDate.Before(now) {
+			assigned.Overdue++
+		}
+	}
+
+	return assigned, nil
+}
+
+// AddDependency blocks a task by another task of the same project. It fails
+// when the new dependency would close a cycle.
+func (c Core) AddDependency(ctx context.Context, taskID string, nd NewDependency, now time.Time) (Dependency, error) {
+	if err := validate.CheckID(taskID); err != nil {
+		return Dependency{}, ErrInvalidID
+	}
+
+	if err := validate.Check(nd); err != nil {
+		return Dependency{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	if err := validate.CheckID(nd.BlockedBy); err != nil {
+		return Dependency{}, ErrInvalidID
+	}
+
+	if taskID == nd.BlockedBy {
+		return Dependency{}, ErrCycle
+	}
+
+	dbtask, err := c.store.QueryByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Dependency{}, ErrNotFound
+		}
+		return Dependency{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbblocker, err := c.store.QueryByID(ctx, nd.BlockedBy)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return Dependency{}, ErrNotFound
+		}
+		return Dependency{}, fmt.Errorf("query: %w", err)
+	}
+
+	if dbtask.PID != dbblocker.PID {
+		return Dependency{}, ErrOtherProject
+	}
+
+	dbdependencies, err := c.store.QueryProjectDependencies(ctx, dbtask.PID)
+	if err != nil {
+		return Dependency{}, fmt.Errorf("query dependencies: %w", err)
+	}
+
+	blockedBy := make(map[string][]string)
+	for _, dbdependency := range dbdependencies {
+		if dbdependency.TaskID == taskID && dbdependency.BlockedBy == nd.BlockedBy {
+			return Dependency{}, ErrDependency
+		}
+		blockedBy[dbdependency.TaskID] = append(blockedBy[dbdependency.TaskID], dbdependency.BlockedBy)
+	}
+
+	// Walk everything the blocker waits on, reaching the task means a cycle.
+	seen := map[string]bool{nd.BlockedBy: true}
+	queue := []string{nd.BlockedBy}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range blockedBy[id] {
+			if next == taskID {
+				return Dependency{}, ErrCycle
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	dbdependency := db.Dependency{
+		TaskID:      taskID,
+		BlockedBy:   nd.BlockedBy,
+		DateCreated: now,
+	}
+
+	if err := c.store.CreateDependency(ctx, dbdependency); err != nil {
+		return Dependency{}, fmt.Errorf("create: %w", err)
+	}
+
+	return toDependency(dbdependency), nil
+}
+
+// RemoveDependency stops a task from being blocked by another task.
+func (c Core) RemoveDependency(ctx context.Context, taskID, blockedBy string) error {
+	if err := validate.CheckID(taskID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.CheckID(blockedBy); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := c.store.DeleteDependency(ctx, taskID, blockedBy); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryGraph retrieves the tasks of a project with the dependencies between
+// them.
+func (c Core) QueryGraph(ctx context.Context, projectID string) (Graph, error) {
+	if err := validate.CheckID(projectID); err != nil {
+		return Graph{}, ErrInvalidID
+	}
+
+	dbtasks, err := c.store.QueryByProject(ctx, projectID)
+	if err != nil {
+		return Graph{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbdependencies, err := c.store.QueryProjectDependencies(ctx, projectID)
+	if err != nil {
+		return Graph{}, fmt.Errorf("query dependencies: %w", err)
+	}
+
+	graph := Graph{
+		Tasks:        toTasksSlice(dbtasks),
+		Dependencies: toDependencySlice(dbdependencies),
+	}
+
+	return graph, nil
+}
+
+// checkParent makes sure the parent is a task of the same project and the task
+// is not one of its ancestors.
+func (c Core) checkParent(ctx context.Context, taskID, parentID, projectID string) error {
+	if parentID == noTask {
+		return nil
+	}
+
+	if err := validate.CheckID(parentID); err != nil {
+		return ErrInvalidID
+	}
+
+	for id := parentID; id != noTask; {
+		if id == taskID {
+			return ErrCycle
+		}
+
+		dbparent, err := c.store.QueryByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("query parent: %w", err)
+		}
+
+		if dbparent.PID != projectID {
+			return ErrOtherProject
+		}
+		id = string(dbparent.ParentID)
+	}
+
+	return nil
+}
+
+// contains reports whether the status is part of the workflow.
+func contains(workflow []string, status string) bool {
+	for _, s := range workflow {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -80,7 +80,7 @@ func TestTask(t *testing.T) {
 				t.Logf("\t%s\tTest %d:\tShould be able to see updates to Name.", dbtest.Success, testID)
 			}
 
-			if err := core.Delete(ctx, tsk.ID); err != nil {
+			if err := core.Delete(ctx, tsk.ID, now); err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to delete task : %s.", dbtest.Failed, testID, err)
 			}
 			t.Logf("\t%s\tTest %d:\tShould be able to delete task.", dbtest.Success, testID)
@@ -143,7 +143,7 @@ func TestPagingTask(t *testing.T) {
 
 			//========================================================================================================================
 
-			tasks3, err := core.QueryWorkspaceTasks(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 1, 1)
+			tasks3, err := core.QueryWorkspaceTasks(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "5cf37266-3473-4006-984f-9325122678b7", 1, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve tasks for page 1 : %s.", dbtest.Failed, testID, err)
 			}
@@ -154,7 +154,7 @@ func TestPagingTask(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould have a single task.", dbtest.Success, testID)
 
-			tasks4, err := core.QueryWorkspaceTasks(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", 2, 1)
+			tasks4, err := core.QueryWorkspaceTasks(ctx, "7da3ca14-6366-47cf-b953-f706226567d8", "5cf37266-3473-4006-984f-9325122678b7", 2, 1)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve tasks for page 2 : %s.", dbtest.Failed, testID, err)
 			}
@@ -174,3 +174,238 @@ func TestPagingTask(t *testing.T) {
 		}
 	}
 }
+
+func TestBoard(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testboard")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to follow tasks through the workflow.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen assigning tasks of the default project.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			userID := "5cf37266-3473-4006-984f-9325122678b7"
+			due := now.Add(-24 * time.Hour)
+
+			nt := NewTask{
+				Name:      "Review",
+				PID:       projectID,
+				WID:       "7da3ca14-6366-47cf-b953-f706226567d8",
+				Assignees: []string{userID},
+				Status:    "in_review",
+				DueDate:   &due,
+				Priority:  PriorityHigh,
+				Estimated: time.Hour,
+			}
+
+			tsk, err := core.Create(ctx, userID, nt, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create task : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create task.", dbtest.Success, testID)
+
+			nt.Name = "Unknown"
+			nt.Status = "blocked"
+			if _, err := core.Create(ctx, userID, nt, now); !errors.Is(err, ErrInvalidStatus) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to create task outside the workflow : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to create task outside the workflow.", dbtest.Success, testID)
+
+			board, err := core.QueryBoard(ctx, projectID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve board : %s.", dbtest.Failed, testID, err)
+			}
+			if len(board) != 4 || board[0].Count != 2 || board[2].Count != 1 || board[2].Estimated != time.Hour {
+				t.Logf("\t\tTest %d:\tGot: %+v", testID, board)
+				t.Fatalf("\t%s\tTest %d:\tShould group the tasks by status.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould group the tasks by status.", dbtest.Success, testID)
+
+			assigned, err := core.QueryUserTasks(ctx, userID, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve user tasks : %s.", dbtest.Failed, testID, err)
+			}
+			if assigned.Count != 1 || assigned.Overdue != 1 || assigned.Tasks[0].ID != tsk.ID {
+				t.Logf("\t\tTest %d:\tGot: %+v", testID, assigned)
+				t.Fatalf("\t%s\tTest %d:\tShould list the open assigned task.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould list the open assigned task.", dbtest.Success, testID)
+
+			upd := UpdateTask{
+				Status: dbtest.StringPointer("done"),
+			}
+			if err := core.Update(ctx, tsk.ID, upd, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to finish task : %s.", dbtest.Failed, testID, err)
+			}
+
+			assigned, err = core.QueryUserTasks(ctx, userID, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve user tasks : %s.", dbtest.Failed, testID, err)
+			}
+			if assigned.Count != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould drop the done task from the user tasks.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould drop the done task from the user tasks.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testdependencies")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to order tasks by their dependencies.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen blocking the default task by the user task.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			taskID := "346efd40-6d6e-46d5-b60b-5db9fc171779"
+			blockerID := "4ea20d73-a11e-4e83-b95c-ba8b4b5ff6c1"
+
+			if _, err := core.AddDependency(ctx, taskID, NewDependency{BlockedBy: blockerID}, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add dependency : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to add dependency.", dbtest.Success, testID)
+
+			if _, err := core.AddDependency(ctx, blockerID, NewDependency{BlockedBy: taskID}, now); !errors.Is(err, ErrCycle) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to add a cycle : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to add a cycle.", dbtest.Success, testID)
+
+			done := UpdateTask{
+				Status: dbtest.StringPointer("done"),
+			}
+			if err := core.Update(ctx, taskID, done, now); !errors.Is(err, ErrBlocked) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to finish a blocked task : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to finish a blocked task.", dbtest.Success, testID)
+
+			if err := core.Update(ctx, blockerID, done, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to finish the blocker : %s.", dbtest.Failed, testID, err)
+			}
+			if err := core.Update(ctx, taskID, done, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to finish the unblocked task : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to finish the unblocked task.", dbtest.Success, testID)
+
+			graph, err := core.QueryGraph(ctx, projectID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve graph : %s.", dbtest.Failed, testID, err)
+			}
+			if len(graph.Tasks) != 2 || len(graph.Dependencies) != 1 {
+				t.Logf("\t\tTest %d:\tGot: %+v", testID, graph)
+				t.Fatalf("\t%s\tTest %d:\tShould get the dependency graph.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the dependency graph.", dbtest.Success, testID)
+		}
+
+		testID = 1
+		t.Logf("\tTest %d:\tWhen nesting tasks.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			parentID := "346efd40-6d6e-46d5-b60b-5db9fc171779"
+
+			nt := NewTask{
+				Name:     "Subtask",
+				PID:      "45cf87a3-5915-4079-a9af-6c559239ddbf",
+				ParentID: parentID,
+			}
+
+			sub, err := core.Create(ctx, "5cf37266-3473-4006-984f-9325122678b7", nt, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create subtask : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create subtask.", dbtest.Success, testID)
+
+			upd := UpdateTask{
+				ParentID: &sub.ID,
+			}
+			if err := core.Update(ctx, parentID, upd, now); !errors.Is(err, ErrCycle) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to nest a task in its subtask : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to nest a task in its subtask.", dbtest.Success, testID)
+		}
+
+		testID = 2
+		t.Logf("\tTest %d:\tWhen moving and deleting tasks with tracked time.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+			userID := "5cf37266-3473-4006-984f-9325122678b7"
+
+			create := func(name, parentID string) Task {
+				tsk, err := core.Create(ctx, userID, NewTask{Name: name, PID: projectID, ParentID: parentID}, now)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to create task %s : %s.", dbtest.Failed, testID, name, err)
+				}
+				return tsk
+			}
+			oldParent := create("Old Parent", "")
+			grandParent := create("Grand Parent", "")
+			newParent := create("New Parent", grandParent.ID)
+			sub := create("Tracked", oldParent.ID)
+			t.Logf("\t%s\tTest %d:\tShould be able to create the tasks.", dbtest.Success, testID)
+
+			const q = `
+			INSERT INTO time_entries
+				(time_entry_id, description, uid, wid, pid, tid, billable, start, stop, duration, created_with, tags, dur_only, date_created, date_updated)
+			VALUES
+				('b1d2e3f4-5a6b-4c7d-8e9f-0a1b2c3d4e5f', 'tracked', $1,
+				 '7da3ca14-6366-47cf-b953-f706226567d8', $2, $3, true, '2021-10-01 10:00:00', '2021-10-01 11:00:00', 3600,
+				 'test', '{}', false, '2021-10-01 11:00:00', '2021-10-01 11:00:00')`
+
+			if _, err := db.ExecContext(ctx, q, userID, projectID, sub.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert time entry : %s.", dbtest.Failed, testID, err)
+			}
+			if _, err := db.ExecContext(ctx, `UPDATE tasks SET tracked_seconds = 3600 WHERE task_id IN ($1, $2)`, sub.ID, oldParent.ID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to track time : %s.", dbtest.Failed, testID, err)
+			}
+
+			tracked := func(tasks ...Task) map[string]time.Duration {
+				got := make(map[string]time.Duration)
+				for _, tsk := range tasks {
+					var seconds time.Duration
+					if err := db.GetContext(ctx, &seconds, `SELECT tracked_seconds FROM tasks WHERE task_id = $1`, tsk.ID); err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be able to query tracked time : %s.", dbtest.Failed, testID, err)
+					}
+					got[tsk.Name] = seconds
+				}
+				return got
+			}
+
+			if err := core.Update(ctx, sub.ID, UpdateTask{ParentID: &newParent.ID}, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to move the task : %s.", dbtest.Failed, testID, err)
+			}
+			if got := tracked(oldParent, grandParent, newParent); got["Old Parent"] != 0 || got["New Parent"] != 3600 || got["Grand Parent"] != 3600 {
+				t.Fatalf("\t%s\tTest %d:\tShould move the tracked time to the new parents : %v.", dbtest.Failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould move the tracked time to the new parents.", dbtest.Success, testID)
+
+			if err := core.Delete(ctx, newParent.ID, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to delete the parent : %s.", dbtest.Failed, testID, err)
+			}
+			if got := tracked(grandParent); got["Grand Parent"] != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould take the deleted task out of the tracked time : %v.", dbtest.Failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould take the deleted task out of the tracked time.", dbtest.Success, testID)
+		}
+	}
+}
@@ -49,9 +49,9 @@ func (s Store) Tran(tx sqlx.ExtContext) Store {
 func (s Store) Create(ctx context.Context, team Team) error {
 	const q = `
 	INSERT INTO teams
-	   (team_id, pid, uid, wid, manager, date_created, date_updated)
+	   (team_id, pid, uid, wid, manager, rate, date_created, date_updated)
 	VALUES
-	   (:team_id, :pid, :uid, :wid, :manager, :date_created, :date_updated)`
+	   (:team_id, :pid, :uid, :wid, :manager, :rate, :date_created, :date_updated)`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, team); err != nil {
 		return fmt.Errorf("inserting project user: %w", err)
@@ -67,6 +67,7 @@ func (s Store) Update(ctx context.Context, team Team) error {
 		teams
 	SET
 		"manager" = :manager,
+		"rate" = :rate,
 		"date_updated" = :date_updated
 	WHERE
 		"team_id" = :team_id`
@@ -153,3 +154,29 @@ func (s Store) QueryByID(ctx context.Context, teamID string) (Team, error) {
 
 	return team, nil
 }
+
+// QueryByProject retrieves all the members of a project team from the database.
+func (s Store) QueryByProject(ctx context.Context, projectID string) ([]Team, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		teams
+	WHERE
+		pid = :project_id
+	ORDER BY
+		team_id`
+
+	var teams []Team
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &teams); err != nil {
+		return nil, fmt.Errorf("selecting project team: %w", err)
+	}
+
+	return teams, nil
+}
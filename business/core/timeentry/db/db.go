@@ -3,11 +3,14 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	dbp "github.com/AhmedShaef/wakt/business/core/project/db"
+	dbtg "github.com/AhmedShaef/wakt/business/core/tag/db"
 	"github.com/AhmedShaef/wakt/business/core/task/db"
 	"github.com/AhmedShaef/wakt/business/sys/database"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 	"time"
 )
@@ -137,6 +140,20 @@ func (s Store) QueryByID(ctx context.Context, timeEntryID string) (TimeEntry, er
 	return tim, nil
 }
 
+// hasField filters the time entries by a custom field value set on the time
+// entry, its task or its project. It passes every time entry when no field
+// is given.
+const hasField = `(:field_id = '' OR EXISTS (
+		SELECT 1 FROM custom_field_values v
+		WHERE CAST(v.field_id AS text) = :field_id
+			AND v.entity_id IN (time_entry_id, tid, pid)
+			AND :field_value = ANY(v.value)
+	))`
+
+// visible filters the time entries a user may see, the ones of private
+// projects are reserved to the project team and the workspace admins.
+var visible = dbp.VisibleThrough("time_entries.pid")
+
 // QueryRunning gets all TimeEntry from the database.
 func (s Store) QueryRunning(ctx context.Context, userID string, pageNumber int, rowsPerPage int) ([]TimeEntry, error) {
 	data := struct {
@@ -149,14 +166,14 @@ func (s Store) QueryRunning(ctx context.Context, userID string, pageNumber int,
 		UserID:      userID,
 	}
 
-	const q = `
+	q := `
 	SELECT
 		*
 	FROM
 		time_entries
 	WHERE 
 		duration < 0
-		AND uid = :user_id
+		AND uid = :user_id AND ` + visible + `
 	ORDER BY
 		time_entry_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -170,29 +187,34 @@ func (s Store) QueryRunning(ctx context.Context, userID string, pageNumber int,
 }
 
 // QueryRange gets all TimeEntry from the database.
-func (s Store) QueryRange(ctx context.Context, userID string, pageNumber, rowsPerPage int, start, end time.Time) ([]TimeEntry, error) {
+func (s Store) QueryRange(ctx context.Context, userID string, pageNumber, rowsPerPage int, start, end time.Time, fieldID, fieldValue string) ([]TimeEntry, error) {
 	data := struct {
 		Offset      int       `db:"offset"`
 		RowsPerPage int       `db:"rows_per_page"`
 		Start       time.Time `db:"start"`
 		End         time.Time `db:"end"`
 		UserID      string    `db:"user_id"`
+		FieldID     string    `db:"field_id"`
+		FieldValue  string    `db:"field_value"`
 	}{
 		Offset:      (pageNumber - 1) * rowsPerPage,
 		RowsPerPage: rowsPerPage,
 		Start:       start,
 		End:         end,
 		UserID:      userID,
+		FieldID:     fieldID,
+		FieldValue:  fieldValue,
 	}
 
-	const q = `
+	q := `
 	SELECT
 		*
 	FROM
 		time_entries
 	WHERE 
 		date_created >= :start AND date_created <= :end
-		AND uid = :user_id
+		AND uid = :user_id AND ` + visible + `
+		AND ` + hasField + `
 	ORDER BY
 		time_entry_id
 	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
@@ -239,13 +261,13 @@ func (s Store) QueryActivity(ctx context.Context, userID string) ([]TimeEntry, e
 	}{
 		UserID: userID,
 	}
-	const q = `
+	q := `
 	SELECT
 		uid, pid, duration, description, stop, tid
 	FROM
 		time_entries
 	WHERE
-		uid = :user_id
+		uid = :user_id AND ` + visible + `
 	ORDER BY
 		start DESC
 	LIMIT 20`
@@ -282,6 +304,109 @@ func (s Store) QueryProjectTime(ctx context.Context, projectID string) (TimeEntr
 	return ps, nil
 }
 
+// QueryProjectTags gets the names of the tags used by the time entries of the
+// specified project from the database.
+func (s Store) QueryProjectTags(ctx context.Context, projectID string) ([]string, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		COALESCE(array_agg(DISTINCT tag), '{}') AS tags
+	FROM
+		time_entries, unnest(tags) AS tag
+	WHERE
+		pid = :project_id`
+
+	var result struct {
+		Tags pq.StringArray `db:"tags"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return nil, fmt.Errorf("selecting tags projectID[%q]: %w", projectID, err)
+	}
+
+	return result.Tags, nil
+}
+
+// MoveProject moves the time entries of the specified project into the
+// workspace in the database.
+func (s Store) MoveProject(ctx context.Context, projectID, workspaceID string) error {
+	data := struct {
+		ProjectID   string `db:"project_id"`
+		WorkspaceID string `db:"workspace_id"`
+	}{
+		ProjectID:   projectID,
+		WorkspaceID: workspaceID,
+	}
+
+	const q = `
+	UPDATE
+		time_entries
+	SET
+		"wid" = :workspace_id
+	WHERE
+		pid = :project_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("moving time entries projectID[%s]: %w", projectID, err)
+	}
+
+	return nil
+}
+
+// DetachProject removes the specified project and its tasks from the time
+// entries in the database. The time entries stay in their workspace.
+func (s Store) DetachProject(ctx context.Context, projectID string) error {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	UPDATE
+		time_entries
+	SET
+		"pid" = NULL,
+		"tid" = NULL
+	WHERE
+		pid = :project_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("detaching time entries projectID[%s]: %w", projectID, err)
+	}
+
+	return nil
+}
+
+// QueryProjectStatus gets the lifecycle state of the specified project from the
+// database.
+func (s Store) QueryProjectStatus(ctx context.Context, projectID string) (string, error) {
+	data := struct {
+		ProjectID string `db:"project_id"`
+	}{
+		ProjectID: projectID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		projects
+	WHERE
+		project_id = :project_id`
+
+	var prj dbp.Project
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &prj); err != nil {
+		return "", fmt.Errorf("selecting status projectID[%q]: %w", projectID, err)
+	}
+
+	return prj.Status, nil
+}
+
 // UpdateProjectTime modifies data about a TimeEntry. It will error if the specified ID is
 // invalid or does not reference an existing TimeEntry.
 func (s Store) UpdateProjectTime(ctx context.Context, data dbp.Project) error {
@@ -302,7 +427,8 @@ func (s Store) UpdateProjectTime(ctx context.Context, data dbp.Project) error {
 	return nil
 }
 
-// QueryTaskTime sync the specified task time from the database.
+// QueryTaskTime sync the specified task time from the database. The tracked
+// time of the subtasks is added to the time of the task.
 func (s Store) QueryTaskTime(ctx context.Context, taskID string) (TimeEntry, error) {
 	data := struct {
 		TaskID string `db:"task_id"`
@@ -312,11 +438,8 @@ func (s Store) QueryTaskTime(ctx context.Context, taskID string) (TimeEntry, err
 
 	const q = `
 	SELECT
-		SUM(duration) AS duration
-	FROM
-		time_entries
-	WHERE 
-		tid = :task_id`
+		COALESCE((SELECT SUM(duration) FROM time_entries WHERE tid = :task_id), 0) +
+		COALESCE((SELECT SUM(tracked_seconds) FROM tasks WHERE parent_id = :task_id), 0) AS duration`
 
 	var ts TimeEntry
 	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &ts); err != nil {
@@ -326,6 +449,30 @@ func (s Store) QueryTaskTime(ctx context.Context, taskID string) (TimeEntry, err
 	return ts, nil
 }
 
+// QueryTaskParent gets the parent of the specified task from the database.
+func (s Store) QueryTaskParent(ctx context.Context, taskID string) (string, error) {
+	data := struct {
+		TaskID string `db:"task_id"`
+	}{
+		TaskID: taskID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		tasks
+	WHERE
+		task_id = :task_id`
+
+	var tsk db.Task
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &tsk); err != nil {
+		return "", fmt.Errorf("selecting parent taskID[%q]: %w", taskID, err)
+	}
+
+	return string(tsk.ParentID), nil
+}
+
 // UpdateTaskTime modifies data about a TimeEntry. It will error if the specified ID is
 // invalid or does not reference an existing TimeEntry.
 func (s Store) UpdateTaskTime(ctx context.Context, data db.Task) error {
@@ -345,3 +492,50 @@ func (s Store) UpdateTaskTime(ctx context.Context, data db.Task) error {
 
 	return nil
 }
+
+// SyncTaskTime recomputes the tracked time of the task and of its parents in
+// the database, as the tracked time of a task includes its subtasks.
+func (s Store) SyncTaskTime(ctx context.Context, taskID string, now time.Time) error {
+	for taskID != database.NoID {
+		taskTime, err := s.QueryTaskTime(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		dbTask := db.Task{
+			ID:          taskID,
+			Tracked:     taskTime.Duration,
+			DateUpdated: now,
+		}
+		if err := s.UpdateTaskTime(ctx, dbTask); err != nil {
+			return err
+		}
+
+		taskID, err = s.QueryTaskParent(ctx, taskID)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateTag registers a tag used by a time entry, unless the workspace
+// already has a tag with the same name.
+func (s Store) CreateTag(ctx context.Context, tag dbtg.Tag) error {
+	const q = `
+	INSERT INTO tags
+		(tag_id, name, wid, date_created, date_updated)
+	VALUES
+		(:tag_id, :name, :wid, :date_created, :date_updated)
+	ON CONFLICT (wid, name) DO NOTHING`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, tag); err != nil {
+		return fmt.Errorf("inserting tag: %w", err)
+	}
+
+	return nil
+}
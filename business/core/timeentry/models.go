@@ -27,6 +27,7 @@ type TimeEntry struct {
 }
 
 // NewTimeEntry contains information needed to create a new time_entry.
+// CustomFields holds the custom field values of the time entry by field id.
 type NewTimeEntry struct {
 	Description string        `json:"description"`
 	WID         string        `json:"wid"`
@@ -39,6 +40,8 @@ type NewTimeEntry struct {
 	CreatedWith string        `json:"created_with" validate:"required"`
 	Tags        []string      `json:"tags"`
 	DurOnly     bool          `json:"dur_only"`
+
+	CustomFields map[string][]string `json:"custom_fields"`
 }
 
 //StartTimeEntry contains information needed to start a new time_entry.
@@ -51,6 +54,8 @@ type StartTimeEntry struct {
 	CreatedWith string   `json:"created_with" validate:"required"`
 	Tags        []string `json:"tags"`
 	DurOnly     bool     `json:"dur_only"`
+
+	CustomFields map[string][]string `json:"custom_fields"`
 }
 
 // UpdateTimeEntry defines what information may be provided to modify an existing
@@ -75,6 +80,13 @@ type UpdateTimeEntryTags struct {
 	TagMode string   `json:"tag_mode" validate:"required"`
 }
 
+// FieldFilter keeps the time entries having Value in the custom field set on
+// the time entry, its task or its project. An empty FieldID keeps them all.
+type FieldFilter struct {
+	FieldID string
+	Value   string
+}
+
 // =============================================================================
 
 func toTimeEntry(dbTimeEntry db.TimeEntry) TimeEntry {
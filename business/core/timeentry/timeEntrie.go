@@ -9,7 +9,7 @@ import (
 	"fmt"
 
 	dbp "github.com/AhmedShaef/wakt/business/core/project/db"
-	dbt "github.com/AhmedShaef/wakt/business/core/task/db"
+	dbtg "github.com/AhmedShaef/wakt/business/core/tag/db"
 	"github.com/AhmedShaef/wakt/business/core/timeentry/db"
 	"github.com/AhmedShaef/wakt/business/sys/database"
 	"github.com/AhmedShaef/wakt/business/sys/util"
@@ -25,17 +25,26 @@ import (
 var (
 	ErrNotFound  = errors.New("user not found")
 	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrArchived  = errors.New("project is archived")
+	ErrPrivate   = errors.New("project is private")
+
+	ErrReferenceNotFound = errors.New("referenced project or task not found")
 )
 
+// statusArchived mirrors the archived lifecycle state of a project.
+const statusArchived = "archived"
+
 // Core manages the set of APIs for user access.
 type Core struct {
-	store db.Store
+	store    db.Store
+	projects dbp.Store
 }
 
 // NewCore constructs a core for user api access.
 func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
 	return Core{
-		store: db.NewStore(log, sqlxDB),
+		store:    db.NewStore(log, sqlxDB),
+		projects: dbp.NewStore(log, sqlxDB),
 	}
 }
 
@@ -57,8 +66,8 @@ func (c Core) Create(ctx context.Context, nt NewTimeEntry, userID string, now ti
 		Description: nt.Description,
 		UID:         userID,
 		WID:         nt.WID,
-		PID:         nt.PID,
-		TID:         nt.TID,
+		PID:         database.NullID(nt.PID),
+		TID:         database.NullID(nt.TID),
 		Billable:    nt.Billable,
 		Start:       nt.Start,
 		Stop:        stop,
@@ -70,24 +79,37 @@ func (c Core) Create(ctx context.Context, nt NewTimeEntry, userID string, now ti
 		DateUpdated: now,
 	}
 	if dbTimeEntry.PID == "" {
-		dbTimeEntry.PID = "00000000-0000-0000-0000-000000000000"
+		dbTimeEntry.PID = database.NoID
 	}
 	if dbTimeEntry.TID == "" {
-		dbTimeEntry.TID = "00000000-0000-0000-0000-000000000000"
+		dbTimeEntry.TID = database.NoID
 	}
-	if dbTimeEntry.Tags == nil {
-		dbTimeEntry.Tags = []string{}
+	dbTimeEntry.Tags = util.Unique(dbTimeEntry.Tags)
+
+	if err := c.checkProject(ctx, string(dbTimeEntry.PID), userID); err != nil {
+		return TimeEntry{}, err
 	}
 
-	if err := c.store.Create(ctx, dbTimeEntry); err != nil {
-		return TimeEntry{}, fmt.Errorf("create: %w", err)
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.Create(ctx, dbTimeEntry); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+		return c.createTags(ctx, store, dbTimeEntry.WID, dbTimeEntry.Tags, now)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return TimeEntry{}, ErrReferenceNotFound
+		}
+		return TimeEntry{}, fmt.Errorf("tran: %w", err)
 	}
 
-	if err := c.SyncTaskTime(ctx, dbTimeEntry.TID, now); err != nil {
+	if err := c.SyncTaskTime(ctx, string(dbTimeEntry.TID), now); err != nil {
 		return TimeEntry{}, fmt.Errorf("sync task time: %w", err)
 	}
 
-	if err := c.SyncProjectTime(ctx, dbTimeEntry.PID, now); err != nil {
+	if err := c.SyncProjectTime(ctx, string(dbTimeEntry.PID), now); err != nil {
 		return TimeEntry{}, fmt.Errorf("sync project time: %w", err)
 	}
 
@@ -110,8 +132,8 @@ func (c Core) Start(ctx context.Context, st StartTimeEntry, userID string, now t
 		Description: st.Description,
 		UID:         userID,
 		WID:         st.WID,
-		PID:         st.PID,
-		TID:         st.TID,
+		PID:         database.NullID(st.PID),
+		TID:         database.NullID(st.TID),
 		Billable:    st.Billable,
 		Start:       now,
 		Stop:        time.Time{},
@@ -124,22 +146,62 @@ func (c Core) Start(ctx context.Context, st StartTimeEntry, userID string, now t
 	}
 
 	if dbTimeEntry.PID == "" {
-		dbTimeEntry.PID = "00000000-0000-0000-0000-000000000000"
+		dbTimeEntry.PID = database.NoID
 	}
 	if dbTimeEntry.TID == "" {
-		dbTimeEntry.TID = "00000000-0000-0000-0000-000000000000"
+		dbTimeEntry.TID = database.NoID
 	}
-	if dbTimeEntry.Tags == nil {
-		dbTimeEntry.Tags = []string{}
+	dbTimeEntry.Tags = util.Unique(dbTimeEntry.Tags)
+
+	if err := c.checkProject(ctx, string(dbTimeEntry.PID), userID); err != nil {
+		return TimeEntry{}, err
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.Create(ctx, dbTimeEntry); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+		return c.createTags(ctx, store, dbTimeEntry.WID, dbTimeEntry.Tags, now)
 	}
 
-	if err := c.store.Create(ctx, dbTimeEntry); err != nil {
-		return TimeEntry{}, fmt.Errorf("create: %w", err)
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return TimeEntry{}, ErrReferenceNotFound
+		}
+		return TimeEntry{}, fmt.Errorf("tran: %w", err)
 	}
 
 	return toTimeEntry(dbTimeEntry), nil
 }
 
+// checkProject makes sure new time is not tracked against an archived project,
+// nor against a private project the user is not allowed on.
+func (c Core) checkProject(ctx context.Context, projectID, userID string) error {
+	status, err := c.store.QueryProjectStatus(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return nil
+		}
+		return fmt.Errorf("query project: %w", err)
+	}
+
+	if status == statusArchived {
+		return ErrArchived
+	}
+
+	access, err := c.projects.QueryAccess(ctx, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("query project: %w", err)
+	}
+
+	if !access {
+		return ErrPrivate
+	}
+
+	return nil
+}
+
 // Stop replaces a time_entry document in the database.
 func (c Core) Stop(ctx context.Context, TimeEntryID string, now time.Time) (TimeEntry, error) {
 	if err := validate.CheckID(TimeEntryID); err != nil {
@@ -162,11 +224,11 @@ func (c Core) Stop(ctx context.Context, TimeEntryID string, now time.Time) (Time
 		return TimeEntry{}, fmt.Errorf("stop: %w", err)
 	}
 
-	if err := c.SyncTaskTime(ctx, dbTimeEntry.TID, now); err != nil {
+	if err := c.SyncTaskTime(ctx, string(dbTimeEntry.TID), now); err != nil {
 		return TimeEntry{}, fmt.Errorf("sync task time: %w", err)
 	}
 
-	if err := c.SyncProjectTime(ctx, dbTimeEntry.PID, now); err != nil {
+	if err := c.SyncProjectTime(ctx, string(dbTimeEntry.PID), now); err != nil {
 		return TimeEntry{}, fmt.Errorf("sync project time: %w", err)
 	}
 
@@ -209,15 +271,26 @@ func (c Core) Update(ctx context.Context, TimeEntryID string, ut UpdateTimeEntry
 		dbTimEntry.CreatedWith = *ut.CreatedWith
 	}
 	if ut.Tags != nil {
-		dbTimEntry.Tags = ut.Tags
+		dbTimEntry.Tags = util.Unique(ut.Tags)
 	}
 	if ut.DurOnly != nil {
 		dbTimEntry.DurOnly = *ut.DurOnly
 	}
 	dbTimEntry.DateUpdated = now
 
-	if err := c.store.Update(ctx, dbTimEntry); err != nil {
-		return fmt.Errorf("udpate: %w", err)
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.Update(ctx, dbTimEntry); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+		return c.createTags(ctx, store, dbTimEntry.WID, dbTimEntry.Tags, now)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, database.ErrDBMissingReference) {
+			return ErrReferenceNotFound
+		}
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
@@ -278,27 +351,20 @@ func (c Core) SyncProjectTime(ctx context.Context, projectID string, now time.Ti
 	return nil
 }
 
-// SyncTaskTime sync the specified task time from the database.
+// SyncTaskTime sync the specified task time from the database. The time rolls
+// up through the parents of the task.
 func (c Core) SyncTaskTime(ctx context.Context, taskID string, now time.Time) error {
 	if err := validate.CheckID(taskID); err != nil {
 		return ErrInvalidID
 	}
 
-	taskTime, err := c.store.QueryTaskTime(ctx, taskID)
-	if err != nil {
+	if err := c.store.SyncTaskTime(ctx, taskID, now); err != nil {
 		if errors.Is(err, database.ErrDBNotFound) {
 			return ErrNotFound
 		}
 		return fmt.Errorf("sync: %w", err)
 	}
-	dbTask := dbt.Task{
-		ID:             taskID,
-		Tracked: taskTime.Duration,
-		DateUpdated:    now,
-	}
-	if err = c.store.UpdateTaskTime(ctx, dbTask); err != nil {
-		return fmt.Errorf("udpate: %w", err)
-	}
+
 	return nil
 }
 
@@ -316,13 +382,20 @@ func (c Core) QueryRunning(ctx context.Context, userID string, pageNumber int, r
 	return toTimeEntrySlice(dbTimeEntry), nil
 }
 
-//QueryRange retrieves a list of existing time entry from the database.
-func (c Core) QueryRange(ctx context.Context, userID string, pageNumber, rowsPerPage int, start, end time.Time) ([]TimeEntry, error) {
+// QueryRange retrieves a list of existing time entry from the database. Only
+// the time entries matching the custom field filter are kept when it is set.
+func (c Core) QueryRange(ctx context.Context, userID string, pageNumber, rowsPerPage int, start, end time.Time, ff FieldFilter) ([]TimeEntry, error) {
 	if err := validate.CheckID(userID); err != nil {
 		return []TimeEntry{}, ErrInvalidID
 	}
 
-	dbTimeEntry, err := c.store.QueryRange(ctx, userID, pageNumber, rowsPerPage, start, end)
+	if ff.FieldID != "" {
+		if err := validate.CheckID(ff.FieldID); err != nil {
+			return []TimeEntry{}, ErrInvalidID
+		}
+	}
+
+	dbTimeEntry, err := c.store.QueryRange(ctx, userID, pageNumber, rowsPerPage, start, end, ff.FieldID, ff.Value)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -354,13 +427,40 @@ func (c Core) UpdateTags(ctx context.Context, TimeEntryID string, ut UpdateTimeE
 		dbTimEntry.Tags = util.Remove(dbTimEntry.Tags, ut.Tags)
 	}
 	dbTimEntry.DateUpdated = now
-	if err := c.store.Update(ctx, dbTimEntry); err != nil {
-		return fmt.Errorf("udpate: %w", err)
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+		if err := store.Update(ctx, dbTimEntry); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+		return c.createTags(ctx, store, dbTimEntry.WID, dbTimEntry.Tags, now)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
 }
 
+// createTags makes sure every tag name carried by a time entry is also
+// known as a tag of the workspace.
+func (c Core) createTags(ctx context.Context, store db.Store, workspaceID string, tags []string, now time.Time) error {
+	for _, name := range tags {
+		tag := dbtg.Tag{
+			ID:          validate.GenerateID(),
+			Name:        name,
+			WID:         workspaceID,
+			DateCreated: now,
+			DateUpdated: now,
+		}
+		if err := store.CreateTag(ctx, tag); err != nil {
+			return fmt.Errorf("create tag: %w", err)
+		}
+	}
+	return nil
+}
+
 //QueryDash retrieves a list of existing time entry from the database.
 func (c Core) QueryDash(ctx context.Context, UserID string) ([]TimeEntry, error) {
 	if err := validate.CheckID(UserID); err != nil {
@@ -215,7 +215,7 @@ func TestPagingTimeEntry(t *testing.T) {
 
 			//==================================================================================================================
 
-			timeEntryRange1, err := core.QueryRange(ctx, "5cf37266-3473-4006-984f-9325122678b7", 1, 1, time.Date(2006, time.October, 1, 0, 0, 0, 0, time.UTC), time.Now())
+			timeEntryRange1, err := core.QueryRange(ctx, "5cf37266-3473-4006-984f-9325122678b7", 1, 1, time.Date(2006, time.October, 1, 0, 0, 0, 0, time.UTC), time.Now(), FieldFilter{})
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve workspace timeEntryRange for page 1 : %s.", dbtest.Failed, testID, err)
 			}
@@ -226,7 +226,7 @@ func TestPagingTimeEntry(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould have a single timeEntry.", dbtest.Success, testID)
 
-			timeEntryRange2, err := core.QueryRange(ctx, "5cf37266-3473-4006-984f-9325122678b7", 2, 1, time.Date(2006, time.October, 1, 0, 0, 0, 0, time.UTC), time.Now())
+			timeEntryRange2, err := core.QueryRange(ctx, "5cf37266-3473-4006-984f-9325122678b7", 2, 1, time.Date(2006, time.October, 1, 0, 0, 0, 0, time.UTC), time.Now(), FieldFilter{})
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve workspace timeEntryRange for page 2 : %s.", dbtest.Failed, testID, err)
 			}
@@ -247,3 +247,152 @@ func TestPagingTimeEntry(t *testing.T) {
 		}
 	}
 }
+
+func TestArchivedProject(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testarchivedproject")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to keep time off archived projects.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen tracking time against an archived project.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+
+			if _, err := db.ExecContext(ctx, `UPDATE projects SET status = 'archived' WHERE project_id = $1`, projectID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to archive project : %s.", dbtest.Failed, testID, err)
+			}
+
+			ntc := NewTimeEntry{
+				WID:         "7da3ca14-6366-47cf-b953-f706226567d8",
+				PID:         projectID,
+				Start:       now,
+				Duration:    time.Hour,
+				CreatedWith: "API",
+			}
+
+			if _, err := core.Create(ctx, ntc, "5cf37266-3473-4006-984f-9325122678b7", now); !errors.Is(err, ErrArchived) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to create time entry : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to create time entry.", dbtest.Success, testID)
+
+			nts := StartTimeEntry{
+				WID:         "7da3ca14-6366-47cf-b953-f706226567d8",
+				PID:         projectID,
+				CreatedWith: "API",
+			}
+
+			if _, err := core.Start(ctx, nts, "5cf37266-3473-4006-984f-9325122678b7", now); !errors.Is(err, ErrArchived) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to start time entry : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to start time entry.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestTaskRollup(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testtaskrollup")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to roll subtask time up into the parent task.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen tracking time on a subtask.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			parentID := "346efd40-6d6e-46d5-b60b-5db9fc171779"
+			subtaskID := "4ea20d73-a11e-4e83-b95c-ba8b4b5ff6c1"
+
+			if _, err := db.ExecContext(ctx, `UPDATE tasks SET parent_id = $1 WHERE task_id = $2`, parentID, subtaskID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to nest task : %s.", dbtest.Failed, testID, err)
+			}
+
+			ntc := NewTimeEntry{
+				WID:         "7da3ca14-6366-47cf-b953-f706226567d8",
+				PID:         "45cf87a3-5915-4079-a9af-6c559239ddbf",
+				TID:         subtaskID,
+				Start:       now,
+				Duration:    600 * 1000000,
+				CreatedWith: "API",
+			}
+
+			if _, err := core.Create(ctx, ntc, "5cf37266-3473-4006-984f-9325122678b7", now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create time entry : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create time entry.", dbtest.Success, testID)
+
+			var tracked, exp time.Duration
+			if err := db.GetContext(ctx, &tracked, `SELECT tracked_seconds FROM tasks WHERE task_id = $1`, parentID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve parent time : %s.", dbtest.Failed, testID, err)
+			}
+			if err := db.GetContext(ctx, &exp, `SELECT SUM(duration) FROM time_entries WHERE tid IN ($1, $2)`, parentID, subtaskID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve entry time : %s.", dbtest.Failed, testID, err)
+			}
+			if tracked != exp {
+				t.Logf("\t\tTest %d:\tGot: %v", testID, tracked)
+				t.Logf("\t\tTest %d:\tExp: %v", testID, exp)
+				t.Fatalf("\t%s\tTest %d:\tShould roll the time up into the parent.", dbtest.Failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould roll the time up into the parent.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestPrivateProject(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testprivateproject")
+	t.Cleanup(teardown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dbschema.Seed(ctx, db)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to keep time off private projects.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen tracking time against a private project of another team.", testID)
+		{
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			projectID := "45cf87a3-5915-4079-a9af-6c559239ddbf"
+
+			if _, err := db.ExecContext(ctx, `UPDATE projects SET is_private = true WHERE project_id = $1`, projectID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to make project private : %s.", dbtest.Failed, testID, err)
+			}
+
+			ntc := NewTimeEntry{
+				WID:         "7da3ca14-6366-47cf-b953-f706226567d8",
+				PID:         projectID,
+				Start:       now,
+				Duration:    time.Hour,
+				CreatedWith: "API",
+			}
+
+			if _, err := core.Create(ctx, ntc, "45b5fbd3-755f-4379-8f07-a58d4a30fa2f", now); !errors.Is(err, ErrPrivate) {
+				t.Fatalf("\t%s\tTest %d:\tShould NOT be able to create time entry outside the team : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould NOT be able to create time entry outside the team.", dbtest.Success, testID)
+
+			if _, err := core.Create(ctx, ntc, "5cf37266-3473-4006-984f-9325122678b7", now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create time entry as a team member : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create time entry as a team member.", dbtest.Success, testID)
+		}
+	}
+}
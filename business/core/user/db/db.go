@@ -4,6 +4,8 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"go.uber.org/zap"
 
 	"github.com/AhmedShaef/wakt/business/sys/database"
@@ -49,9 +51,9 @@ func (s Store) Tran(tx sqlx.ExtContext) Store {
 func (s Store) Create(ctx context.Context, user User) error {
 	const q = `
 	INSERT INTO users
-		(user_id, default_wid, email, password_hash,full_name, time_of_day_format, date_format, beginning_of_week, language, image_url, date_created, date_updated, timezone, invitation, duration_format)
+		(user_id, default_wid, email, password_hash,full_name, time_of_day_format, date_format, beginning_of_week, language, image_url, date_created, date_updated, timezone, invitation, duration_format, verified)
 	VALUES
-		(:user_id, :default_wid, :email, :password_hash, :full_name, :time_of_day_format, :date_format, :beginning_of_week, :language, :image_url, :date_created, :date_updated, :timezone, :invitation, :duration_format)`
+		(:user_id, :default_wid, :email, :password_hash, :full_name, :time_of_day_format, :date_format, :beginning_of_week, :language, :image_url, :date_created, :date_updated, :timezone, :invitation, :duration_format, :verified)`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, user); err != nil {
 		return fmt.Errorf("inserting user: %w", err)
@@ -78,7 +80,8 @@ func (s Store) Update(ctx context.Context, user User) error {
 		date_updated = :date_updated,
 		timezone = :timezone,
 		invitation = :invitation,
-		duration_format = :duration_format
+		duration_format = :duration_format,
+		verified = :verified
 	WHERE
 		user_id = :user_id`
 
@@ -136,3 +139,935 @@ func (s Store) QueryByEmail(ctx context.Context, email string) (User, error) {
 
 	return user, nil
 }
+
+// CreatePasswordReset inserts a new password reset token into the database.
+func (s Store) CreatePasswordReset(ctx context.Context, reset PasswordReset) error {
+	const q = `
+	INSERT INTO password_resets
+		(token_hash, uid, date_expires, date_created)
+	VALUES
+		(:token_hash, :uid, :date_expires, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, reset); err != nil {
+		return fmt.Errorf("inserting password reset: %w", err)
+	}
+
+	return nil
+}
+
+// UsePasswordReset removes the password reset token with the hash from the
+// database and returns it, so it can only be used once.
+func (s Store) UsePasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	DELETE FROM
+		password_resets
+	WHERE
+		token_hash = :token_hash
+	RETURNING
+		*`
+
+	var reset PasswordReset
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &reset); err != nil {
+		return PasswordReset{}, fmt.Errorf("using password reset: %w", err)
+	}
+
+	return reset, nil
+}
+
+// DeletePasswordResets removes every password reset token of a user from the
+// database.
+func (s Store) DeletePasswordResets(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		password_resets
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting password resets userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// RevokeSessions records that the sessions of a user issued before the date
+// are no longer valid.
+func (s Store) RevokeSessions(ctx context.Context, userID string, revoked time.Time) error {
+	data := struct {
+		UserID  string    `db:"user_id"`
+		Revoked time.Time `db:"date_revoked"`
+	}{
+		UserID:  userID,
+		Revoked: revoked,
+	}
+
+	const q = `
+	INSERT INTO session_revocations
+		(uid, date_revoked)
+	VALUES
+		(:user_id, :date_revoked)
+	ON CONFLICT (uid) DO UPDATE SET
+		date_revoked = EXCLUDED.date_revoked`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("revoking sessions userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// QuerySessionsRevoked gets the date the sessions of a user were last revoked
+// from the database.
+func (s Store) QuerySessionsRevoked(ctx context.Context, userID string) (time.Time, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	SELECT
+		date_revoked
+	FROM
+		session_revocations
+	WHERE
+		uid = :user_id`
+
+	var revocation struct {
+		Revoked time.Time `db:"date_revoked"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &revocation); err != nil {
+		return time.Time{}, fmt.Errorf("selecting session revocation userID[%s]: %w", userID, err)
+	}
+
+	return revocation.Revoked, nil
+}
+
+// CreateEmailVerification inserts a new email verification token into the
+// database.
+func (s Store) CreateEmailVerification(ctx context.Context, verification EmailVerification) error {
+	const q = `
+	INSERT INTO email_verifications
+		(token_hash, uid, email, date_expires, date_created)
+	VALUES
+		(:token_hash, :uid, :email, :date_expires, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, verification); err != nil {
+		return fmt.Errorf("inserting email verification: %w", err)
+	}
+
+	return nil
+}
+
+// UseEmailVerification removes the email verification token with the hash
+// from the database and returns it, so it can only be used once.
+func (s Store) UseEmailVerification(ctx context.Context, tokenHash string) (EmailVerification, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	DELETE FROM
+		email_verifications
+	WHERE
+		token_hash = :token_hash
+	RETURNING
+		*`
+
+	var verification EmailVerification
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &verification); err != nil {
+		return EmailVerification{}, fmt.Errorf("using email verification: %w", err)
+	}
+
+	return verification, nil
+}
+
+// DeleteEmailVerifications removes every email verification token of a user
+// from the database.
+func (s Store) DeleteEmailVerifications(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		email_verifications
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting email verifications userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// CreateRefreshToken inserts a new refresh token into the database.
+func (s Store) CreateRefreshToken(ctx context.Context, refresh RefreshToken) error {
+	const q = `
+	INSERT INTO refresh_tokens
+		(token_hash, uid, session_id, jti, date_used, date_expires, date_created)
+	VALUES
+		(:token_hash, :uid, :session_id, :jti, :date_used, :date_expires, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, refresh); err != nil {
+		return fmt.Errorf("inserting refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// QueryRefreshToken gets the refresh token with the hash from the database.
+// The row stays locked until the transaction it is read in ends.
+func (s Store) QueryRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		refresh_tokens
+	WHERE
+		token_hash = :token_hash
+	FOR UPDATE`
+
+	var refresh RefreshToken
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &refresh); err != nil {
+		return RefreshToken{}, fmt.Errorf("selecting refresh token: %w", err)
+	}
+
+	return refresh, nil
+}
+
+// QueryRefreshTokenByJTI gets the refresh token issued along with the access
+// token of the jti from the database.
+func (s Store) QueryRefreshTokenByJTI(ctx context.Context, jti string) (RefreshToken, error) {
+	data := struct {
+		JTI string `db:"jti"`
+	}{
+		JTI: jti,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		refresh_tokens
+	WHERE
+		jti = :jti`
+
+	var refresh RefreshToken
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &refresh); err != nil {
+		return RefreshToken{}, fmt.Errorf("selecting refresh token jti[%s]: %w", jti, err)
+	}
+
+	return refresh, nil
+}
+
+// UseRefreshToken marks a refresh token as used so it can't be used again.
+func (s Store) UseRefreshToken(ctx context.Context, tokenHash string, used time.Time) error {
+	data := struct {
+		TokenHash string    `db:"token_hash"`
+		DateUsed  time.Time `db:"date_used"`
+	}{
+		TokenHash: tokenHash,
+		DateUsed:  used,
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_used = :date_used
+	WHERE
+		token_hash = :token_hash`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("using refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeRefreshSession revokes every access token issued in a refresh token
+// session, up to the date they all expire by, and removes the refresh tokens
+// of the session from the database.
+func (s Store) RevokeRefreshSession(ctx context.Context, sessionID string, expires time.Time) error {
+	data := struct {
+		SessionID   string    `db:"session_id"`
+		DateExpires time.Time `db:"date_expires"`
+	}{
+		SessionID:   sessionID,
+		DateExpires: expires,
+	}
+
+	const revoke = `
+	INSERT INTO revoked_tokens
+		(jti, date_expires)
+	SELECT
+		jti, :date_expires
+	FROM
+		refresh_tokens
+	WHERE
+		session_id = :session_id
+	ON CONFLICT (jti) DO NOTHING`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, revoke, data); err != nil {
+		return fmt.Errorf("revoking session tokens sessionID[%s]: %w", sessionID, err)
+	}
+
+	const remove = `
+	DELETE FROM
+		refresh_tokens
+	WHERE
+		session_id = :session_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, remove, data); err != nil {
+		return fmt.Errorf("deleting refresh tokens sessionID[%s]: %w", sessionID, err)
+	}
+
+	return nil
+}
+
+// DeleteRefreshTokens removes every refresh token of a user from the
+// database.
+func (s Store) DeleteRefreshTokens(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		refresh_tokens
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting refresh tokens userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// RevokeToken records that the access token of the jti is no longer valid.
+func (s Store) RevokeToken(ctx context.Context, jti string, expires time.Time) error {
+	data := struct {
+		JTI         string    `db:"jti"`
+		DateExpires time.Time `db:"date_expires"`
+	}{
+		JTI:         jti,
+		DateExpires: expires,
+	}
+
+	const q = `
+	INSERT INTO revoked_tokens
+		(jti, date_expires)
+	VALUES
+		(:jti, :date_expires)
+	ON CONFLICT (jti) DO NOTHING`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("revoking token jti[%s]: %w", jti, err)
+	}
+
+	return nil
+}
+
+// QueryTokenRevoked reports whether the access token of the jti was revoked.
+func (s Store) QueryTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	data := struct {
+		JTI string `db:"jti"`
+	}{
+		JTI: jti,
+	}
+
+	const q = `
+	SELECT
+		EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = :jti) AS revoked`
+
+	var result struct {
+		Revoked bool `db:"revoked"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return false, fmt.Errorf("selecting revoked token jti[%s]: %w", jti, err)
+	}
+
+	return result.Revoked, nil
+}
+
+// DeleteExpiredTokens removes the refresh tokens and the revoked access tokens
+// that expired before the date from the database.
+func (s Store) DeleteExpiredTokens(ctx context.Context, now time.Time) error {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now,
+	}
+
+	const refresh = `
+	DELETE FROM
+		refresh_tokens
+	WHERE
+		date_expires < :now`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, refresh, data); err != nil {
+		return fmt.Errorf("deleting expired refresh tokens: %w", err)
+	}
+
+	const revoked = `
+	DELETE FROM
+		revoked_tokens
+	WHERE
+		date_expires < :now`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, revoked, data); err != nil {
+		return fmt.Errorf("deleting expired revoked tokens: %w", err)
+	}
+
+	return nil
+}
+
+// SaveTwoFactor inserts the two-factor secret of a user into the database,
+// replacing the one the user has.
+func (s Store) SaveTwoFactor(ctx context.Context, twoFactor TwoFactor) error {
+	const q = `
+	INSERT INTO two_factors
+		(uid, secret, confirmed, last_step, date_created, date_updated)
+	VALUES
+		(:uid, :secret, :confirmed, :last_step, :date_created, :date_updated)
+	ON CONFLICT (uid) DO UPDATE SET
+		secret = EXCLUDED.secret,
+		confirmed = EXCLUDED.confirmed,
+		last_step = EXCLUDED.last_step,
+		date_created = EXCLUDED.date_created,
+		date_updated = EXCLUDED.date_updated`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, twoFactor); err != nil {
+		return fmt.Errorf("saving two factor userID[%s]: %w", twoFactor.UID, err)
+	}
+
+	return nil
+}
+
+// UpdateTwoFactor replaces the two-factor state of a user in the database.
+func (s Store) UpdateTwoFactor(ctx context.Context, twoFactor TwoFactor) error {
+	const q = `
+	UPDATE
+		two_factors
+	SET
+		confirmed = :confirmed,
+		last_step = :last_step,
+		date_updated = :date_updated
+	WHERE
+		uid = :uid`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, twoFactor); err != nil {
+		return fmt.Errorf("updating two factor userID[%s]: %w", twoFactor.UID, err)
+	}
+
+	return nil
+}
+
+// QueryTwoFactor gets the two-factor secret of a user from the database. The
+// row stays locked until the transaction it is read in ends.
+func (s Store) QueryTwoFactor(ctx context.Context, userID string) (TwoFactor, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		two_factors
+	WHERE
+		uid = :user_id
+	FOR UPDATE`
+
+	var twoFactor TwoFactor
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &twoFactor); err != nil {
+		return TwoFactor{}, fmt.Errorf("selecting two factor userID[%s]: %w", userID, err)
+	}
+
+	return twoFactor, nil
+}
+
+// DeleteTwoFactor removes the two-factor secret and the recovery codes of a
+// user from the database.
+func (s Store) DeleteTwoFactor(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		two_factors
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting two factor userID[%s]: %w", userID, err)
+	}
+
+	return s.DeleteRecoveryCodes(ctx, userID)
+}
+
+// CreateRecoveryCode inserts a new recovery code of a user into the database.
+func (s Store) CreateRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	data := struct {
+		UserID   string `db:"user_id"`
+		CodeHash string `db:"code_hash"`
+	}{
+		UserID:   userID,
+		CodeHash: codeHash,
+	}
+
+	const q = `
+	INSERT INTO recovery_codes
+		(uid, code_hash)
+	VALUES
+		(:user_id, :code_hash)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("inserting recovery code userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode removes a recovery code of a user from the database. It
+// fails with database.ErrDBNotFound when the user has no such code.
+func (s Store) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	data := struct {
+		UserID   string `db:"user_id"`
+		CodeHash string `db:"code_hash"`
+	}{
+		UserID:   userID,
+		CodeHash: codeHash,
+	}
+
+	const q = `
+	DELETE FROM
+		recovery_codes
+	WHERE
+		uid = :user_id AND code_hash = :code_hash
+	RETURNING
+		code_hash`
+
+	var used struct {
+		CodeHash string `db:"code_hash"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &used); err != nil {
+		return fmt.Errorf("using recovery code userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodes removes every recovery code of a user from the
+// database.
+func (s Store) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		recovery_codes
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting recovery codes userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// QueryTwoFactorRequired reports whether a workspace the user owns or is an
+// active member of requires two-factor authentication.
+func (s Store) QueryTwoFactorRequired(ctx context.Context, userID string) (bool, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	SELECT
+		EXISTS (
+			SELECT 1 FROM workspaces w
+			WHERE w.require_two_factor AND (w.uid = :user_id
+				OR EXISTS (SELECT 1 FROM workspace_users wu WHERE wu.wid = w.workspace_id AND wu.uid = :user_id AND wu.active))
+		) AS required`
+
+	var result struct {
+		Required bool `db:"required"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &result); err != nil {
+		return false, fmt.Errorf("selecting two factor required userID[%s]: %w", userID, err)
+	}
+
+	return result.Required, nil
+}
+
+// CreateTwoFactorChallenge inserts a new two-factor challenge into the
+// database.
+func (s Store) CreateTwoFactorChallenge(ctx context.Context, challenge TwoFactorChallenge) error {
+	const q = `
+	INSERT INTO two_factor_challenges
+		(token_hash, uid, date_expires, date_created)
+	VALUES
+		(:token_hash, :uid, :date_expires, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, challenge); err != nil {
+		return fmt.Errorf("inserting two factor challenge: %w", err)
+	}
+
+	return nil
+}
+
+// QueryTwoFactorChallenge gets the two-factor challenge with the hash from
+// the database.
+func (s Store) QueryTwoFactorChallenge(ctx context.Context, tokenHash string) (TwoFactorChallenge, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		two_factor_challenges
+	WHERE
+		token_hash = :token_hash`
+
+	var challenge TwoFactorChallenge
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &challenge); err != nil {
+		return TwoFactorChallenge{}, fmt.Errorf("selecting two factor challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// AttemptTwoFactorChallenge counts an attempt to complete the two-factor
+// challenge with the hash and returns it, as long as it has attempts left.
+func (s Store) AttemptTwoFactorChallenge(ctx context.Context, tokenHash string, maxAttempts int) (TwoFactorChallenge, error) {
+	data := struct {
+		TokenHash   string `db:"token_hash"`
+		MaxAttempts int    `db:"max_attempts"`
+	}{
+		TokenHash:   tokenHash,
+		MaxAttempts: maxAttempts,
+	}
+
+	const q = `
+	UPDATE
+		two_factor_challenges
+	SET
+		attempts = attempts + 1
+	WHERE
+		token_hash = :token_hash AND attempts < :max_attempts
+	RETURNING
+		*`
+
+	var challenge TwoFactorChallenge
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &challenge); err != nil {
+		return TwoFactorChallenge{}, fmt.Errorf("attempting two factor challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// DeleteTwoFactorChallenge removes the two-factor challenge with the hash
+// from the database.
+func (s Store) DeleteTwoFactorChallenge(ctx context.Context, tokenHash string) error {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	DELETE FROM
+		two_factor_challenges
+	WHERE
+		token_hash = :token_hash`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting two factor challenge: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTwoFactorChallenges removes every two-factor challenge of a user from
+// the database.
+func (s Store) DeleteTwoFactorChallenges(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		two_factor_challenges
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting two factor challenges userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// QueryLoginFailure gets the failed sign in attempts of an account or an IP
+// address from the database.
+func (s Store) QueryLoginFailure(ctx context.Context, kind, subject string) (LoginFailure, error) {
+	data := struct {
+		Kind    string `db:"kind"`
+		Subject string `db:"subject"`
+	}{
+		Kind:    kind,
+		Subject: subject,
+	}
+
+	const q = `
+	SELECT
+		*
+	FROM
+		login_failures
+	WHERE
+		kind = :kind AND subject = :subject`
+
+	var failure LoginFailure
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &failure); err != nil {
+		return LoginFailure{}, fmt.Errorf("selecting login failure kind[%s]: %w", kind, err)
+	}
+
+	return failure, nil
+}
+
+// RecordLoginAttempt counts a sign in attempt of an account or an IP address
+// as failed and returns the attempts so far. Attempts that failed before the
+// window started are no longer counted. An account or an IP address that has
+// to wait is not counted and ErrDBNotFound is returned instead.
+//
+// The row stays locked until the end of the transaction, so concurrent
+// attempts are counted after any wait it sets.
+func (s Store) RecordLoginAttempt(ctx context.Context, kind, subject string, windowStart, attempted time.Time) (LoginFailure, error) {
+	data := struct {
+		Kind           string    `db:"kind"`
+		Subject        string    `db:"subject"`
+		WindowStart    time.Time `db:"window_start"`
+		DateLastFailed time.Time `db:"date_last_failed"`
+	}{
+		Kind:           kind,
+		Subject:        subject,
+		WindowStart:    windowStart,
+		DateLastFailed: attempted,
+	}
+
+	const q = `
+	INSERT INTO login_failures
+		(kind, subject, failures, date_last_failed)
+	VALUES
+		(:kind, :subject, 1, :date_last_failed)
+	ON CONFLICT (kind, subject) DO UPDATE SET
+		failures = CASE
+			WHEN login_failures.date_last_failed < :window_start THEN 1
+			ELSE login_failures.failures + 1
+		END,
+		date_last_failed = EXCLUDED.date_last_failed
+	WHERE
+		login_failures.date_locked_until IS NULL OR
+		login_failures.date_locked_until <= :date_last_failed
+	RETURNING
+		*`
+
+	var failure LoginFailure
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &failure); err != nil {
+		return LoginFailure{}, fmt.Errorf("recording login attempt kind[%s]: %w", kind, err)
+	}
+
+	return failure, nil
+}
+
+// RefundLoginAttempt takes back a sign in attempt of an account or an IP
+// address that turned out to succeed or could not be checked. The wait it set
+// is lifted, unless an other attempt set a wait since.
+func (s Store) RefundLoginAttempt(ctx context.Context, kind, subject string, lockedUntil *time.Time) error {
+	data := struct {
+		Kind            string     `db:"kind"`
+		Subject         string     `db:"subject"`
+		DateLockedUntil *time.Time `db:"date_locked_until"`
+	}{
+		Kind:            kind,
+		Subject:         subject,
+		DateLockedUntil: lockedUntil,
+	}
+
+	const q = `
+	UPDATE
+		login_failures
+	SET
+		failures = failures - 1,
+		date_locked_until = CASE
+			WHEN date_locked_until = :date_locked_until THEN NULL
+			ELSE date_locked_until
+		END
+	WHERE
+		kind = :kind AND subject = :subject AND failures > 0`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("refunding login attempt kind[%s]: %w", kind, err)
+	}
+
+	return nil
+}
+
+// LockLoginFailure keeps an account or an IP address from signing in until
+// the date.
+func (s Store) LockLoginFailure(ctx context.Context, kind, subject string, until time.Time) error {
+	data := struct {
+		Kind            string    `db:"kind"`
+		Subject         string    `db:"subject"`
+		DateLockedUntil time.Time `db:"date_locked_until"`
+	}{
+		Kind:            kind,
+		Subject:         subject,
+		DateLockedUntil: until,
+	}
+
+	const q = `
+	UPDATE
+		login_failures
+	SET
+		date_locked_until = :date_locked_until
+	WHERE
+		kind = :kind AND subject = :subject`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("locking login failure kind[%s]: %w", kind, err)
+	}
+
+	return nil
+}
+
+// DeleteLoginFailure forgets the failed sign in attempts of an account or an
+// IP address.
+func (s Store) DeleteLoginFailure(ctx context.Context, kind, subject string) error {
+	data := struct {
+		Kind    string `db:"kind"`
+		Subject string `db:"subject"`
+	}{
+		Kind:    kind,
+		Subject: subject,
+	}
+
+	const q = `
+	DELETE FROM
+		login_failures
+	WHERE
+		kind = :kind AND subject = :subject`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting login failure kind[%s]: %w", kind, err)
+	}
+
+	return nil
+}
+
+// CreateAccountUnlock inserts a new account unlock token into the database.
+func (s Store) CreateAccountUnlock(ctx context.Context, unlock AccountUnlock) error {
+	const q = `
+	INSERT INTO account_unlocks
+		(token_hash, uid, date_expires, date_created)
+	VALUES
+		(:token_hash, :uid, :date_expires, :date_created)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, unlock); err != nil {
+		return fmt.Errorf("inserting account unlock: %w", err)
+	}
+
+	return nil
+}
+
+// UseAccountUnlock removes the account unlock token with the hash from the
+// database and returns it, so it can only be used once.
+func (s Store) UseAccountUnlock(ctx context.Context, tokenHash string) (AccountUnlock, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: tokenHash,
+	}
+
+	const q = `
+	DELETE FROM
+		account_unlocks
+	WHERE
+		token_hash = :token_hash
+	RETURNING
+		*`
+
+	var unlock AccountUnlock
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &unlock); err != nil {
+		return AccountUnlock{}, fmt.Errorf("using account unlock: %w", err)
+	}
+
+	return unlock, nil
+}
+
+// DeleteAccountUnlocks removes every account unlock token of a user from the
+// database.
+func (s Store) DeleteAccountUnlocks(ctx context.Context, userID string) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	const q = `
+	DELETE FROM
+		account_unlocks
+	WHERE
+		uid = :user_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("deleting account unlocks userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
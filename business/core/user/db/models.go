@@ -23,4 +23,76 @@ type User struct {
 	TimeZone        string         `db:"timezone"`
 	Invitation      pq.StringArray `db:"invitation"`
 	DurationFormat  string         `db:"duration_format"`
+	Verified        bool           `db:"verified"`
+}
+
+// EmailVerification represent the structure we need for moving data
+// between the app and the database.
+type EmailVerification struct {
+	TokenHash   string    `db:"token_hash"`
+	UID         string    `db:"uid"`
+	Email       string    `db:"email"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+// PasswordReset represent the structure we need for moving data
+// between the app and the database.
+type PasswordReset struct {
+	TokenHash   string    `db:"token_hash"`
+	UID         string    `db:"uid"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+// RefreshToken represent the structure we need for moving data
+// between the app and the database.
+type RefreshToken struct {
+	TokenHash   string     `db:"token_hash"`
+	UID         string     `db:"uid"`
+	SessionID   string     `db:"session_id"`
+	JTI         string     `db:"jti"`
+	DateUsed    *time.Time `db:"date_used"`
+	DateExpires time.Time  `db:"date_expires"`
+	DateCreated time.Time  `db:"date_created"`
+}
+
+// TwoFactor represent the structure we need for moving data
+// between the app and the database.
+type TwoFactor struct {
+	UID         string    `db:"uid"`
+	Secret      string    `db:"secret"`
+	Confirmed   bool      `db:"confirmed"`
+	LastStep    int64     `db:"last_step"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+// TwoFactorChallenge represent the structure we need for moving data
+// between the app and the database.
+type TwoFactorChallenge struct {
+	TokenHash   string    `db:"token_hash"`
+	UID         string    `db:"uid"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+	Attempts    int       `db:"attempts"`
+}
+
+// LoginFailure represent the structure we need for moving data
+// between the app and the database.
+type LoginFailure struct {
+	Kind            string     `db:"kind"`
+	Subject         string     `db:"subject"`
+	Failures        int        `db:"failures"`
+	DateLockedUntil *time.Time `db:"date_locked_until"`
+	DateLastFailed  time.Time  `db:"date_last_failed"`
+}
+
+// AccountUnlock represent the structure we need for moving data
+// between the app and the database.
+type AccountUnlock struct {
+	TokenHash   string    `db:"token_hash"`
+	UID         string    `db:"uid"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
 }
@@ -24,6 +24,7 @@ type User struct {
 	TimeZone        string    `json:"timezone"`
 	Invitation      []string  `json:"invitation"`
 	DurationFormat  string    `json:"duration_format"`
+	Verified        bool      `json:"verified"`
 }
 
 // NewUser contains information needed to create a new user.
@@ -60,6 +61,67 @@ type ChangePassword struct {
 	Password    *string `json:"Password" validate:"required,min=6,max=64"`
 }
 
+// ForgotPassword contains information needed to request a password reset.
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPassword contains information needed to reset a password with the
+// token that was emailed to the user.
+type ResetPassword struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6,max=64"`
+}
+
+// RefreshToken contains the refresh token to exchange for a new access token.
+type RefreshToken struct {
+	Token string `json:"refresh_token" validate:"required"`
+}
+
+// TwoFactorEnrollment is a new two-factor secret along with its provisioning
+// URI for authenticator apps.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorCode contains a code of the two-factor secret or a recovery code.
+type TwoFactorCode struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorChallenge is the second step of signing in. Enroll is set for
+// users that still need to enroll in two-factor authentication.
+type TwoFactorChallenge struct {
+	Token  string `json:"challenge_token"`
+	Enroll bool   `json:"enroll"`
+}
+
+// EnrollChallenge contains the challenge token of a user that needs to enroll
+// in two-factor authentication to sign in.
+type EnrollChallenge struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+// VerifyChallenge contains information needed to complete signing in with a
+// two-factor code.
+type VerifyChallenge struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// VerifyEmail contains information needed to verify an email with the token
+// that was emailed to it.
+type VerifyEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// UnlockAccount contains the token emailed to a user whose account was
+// locked after too many failed sign in attempts.
+type UnlockAccount struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // UpdateImage defines what information may be provided to update an existing
 // user's image.
 type UpdateImage struct {
@@ -5,14 +5,21 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	tokendb "github.com/AhmedShaef/wakt/business/core/apitoken/db"
+	eventdb "github.com/AhmedShaef/wakt/business/core/securityevent/db"
 	"github.com/AhmedShaef/wakt/business/core/user/db"
+	send "github.com/AhmedShaef/wakt/business/send/smtp"
 	"github.com/AhmedShaef/wakt/business/sys/auth"
 	"github.com/AhmedShaef/wakt/business/sys/database"
+	"github.com/AhmedShaef/wakt/business/sys/totp"
 	"github.com/AhmedShaef/wakt/business/sys/validate"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/jmoiron/sqlx"
@@ -28,17 +35,72 @@ var (
 	ErrUniqueEmail           = errors.New("email is not unique")
 	ErrAuthenticationFailure = errors.New("authentication failed")
 	ErrInvalidPassword       = errors.New("password is not valid")
+	ErrInvalidToken          = errors.New("token is invalid or expired")
+	ErrSessionRevoked        = errors.New("session was revoked")
+	ErrVerified              = errors.New("email is already verified")
+	ErrTokenReused           = errors.New("refresh token was already used")
+	ErrTwoFactorEnabled      = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled   = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired     = errors.New("two-factor authentication is required by a workspace")
+	ErrInvalidCode           = errors.New("two-factor code is not valid")
+	ErrTooManyAttempts       = errors.New("too many failed attempts, try again later")
+	ErrAccountLocked         = errors.New("account is locked, check your email to unlock it")
 )
 
+// Set of durations the issued and emailed tokens stay valid for.
+const (
+	accessTTL    = auth.TokenTTL
+	refreshTTL   = 30 * 24 * time.Hour
+	resetTTL     = time.Hour
+	verifyTTL    = 24 * time.Hour
+	challengeTTL = 5 * time.Minute
+	unlockTTL    = 24 * time.Hour
+)
+
+// Set of limits on failed sign in attempts. After the free attempts every
+// failure doubles the time to wait before the next attempt, up to the max
+// backoff. An account is locked for the lockout time, or until it is unlocked
+// by email, once it reaches the lock attempts. Failures older than the window
+// are forgotten.
+const (
+	accountFreeAttempts = 3
+	accountLockAttempts = 10
+	ipFreeAttempts      = 20
+	backoffBase         = time.Second
+	backoffMax          = 15 * time.Minute
+	lockoutTTL          = time.Hour
+	failureWindow       = 24 * time.Hour
+)
+
+// Set of kinds failed sign in attempts are tracked by.
+const (
+	failureAccount = "account"
+	failureIP      = "ip"
+)
+
+// recoveryCodes is the number of recovery codes a user gets when enabling
+// two-factor authentication.
+const recoveryCodes = 10
+
+// challengeAttempts is the number of codes that can be tried on a two-factor
+// challenge before it is used up.
+const challengeAttempts = 5
+
 // Core manages the set of APIs for user access.
 type Core struct {
-	store db.Store
+	log         *zap.SugaredLogger
+	store       db.Store
+	eventsStore eventdb.Store
+	tokensStore tokendb.Store
 }
 
 // NewCore constructs a core for user api access.
 func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
 	return Core{
-		store: db.NewStore(log, sqlxDB),
+		log:         log,
+		store:       db.NewStore(log, sqlxDB),
+		eventsStore: eventdb.NewStore(log, sqlxDB),
+		tokensStore: tokendb.NewStore(log, sqlxDB),
 	}
 }
 
@@ -138,8 +200,16 @@ func (c Core) Update(ctx context.Context, userID string, uu UpdateUser, now time
 		dbuser.DefaultWid = *uu.DefaultWid
 	}
 
-	if uu.Email != nil {
-		dbuser.Email = *uu.Email
+	// A new email only replaces the current one once the user confirms it
+	// with the token that was sent to it.
+	var newEmail string
+	if uu.Email != nil && *uu.Email != dbuser.Email {
+		if _, err := c.store.QueryByEmail(ctx, *uu.Email); err == nil {
+			return ErrUniqueEmail
+		} else if !errors.Is(err, database.ErrDBNotFound) {
+			return fmt.Errorf("query: %w", err)
+		}
+		newEmail = *uu.Email
 	}
 	if uu.FullName != nil {
 		dbuser.FullName = *uu.FullName
@@ -171,10 +241,17 @@ func (c Core) Update(ctx context.Context, userID string, uu UpdateUser, now time
 		return fmt.Errorf("udpate: %w", err)
 	}
 
+	if newEmail != "" {
+		if err := c.sendVerification(ctx, dbuser.ID, newEmail, now); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// ChangePassword replaces a user document in the database.
+// ChangePassword replaces a user document in the database. Every session and
+// personal API token of the user is revoked along with the old password.
 func (c Core) ChangePassword(ctx context.Context, userID string, cp ChangePassword, now time.Time) error {
 	if err := validate.CheckID(userID); err != nil {
 		return ErrInvalidID
@@ -202,8 +279,30 @@ func (c Core) ChangePassword(ctx context.Context, userID string, cp ChangePasswo
 	dbuser.PasswordHash = hash
 	dbuser.DateUpdated = now
 
-	if err := c.store.Update(ctx, dbuser); err != nil {
-		return fmt.Errorf("udpate: %w", err)
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		if err := store.Update(ctx, dbuser); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+
+		if err := store.DeleteRefreshTokens(ctx, dbuser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if err := store.RevokeSessions(ctx, dbuser.ID, now.Truncate(time.Second)); err != nil {
+			return fmt.Errorf("revoke: %w", err)
+		}
+
+		if err := c.tokensStore.Tran(tx).DeleteUserTokens(ctx, dbuser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
 	}
 
 	return nil
@@ -273,36 +372,1119 @@ func (c Core) QueryByEmail(ctx context.Context, email string) (User, error) {
 // Authenticate finds a user by their email and verifies their password. On
 // success it returns a Claims User representing this user. The claims can be
 // used to generate a token for future authentication.
-func (c Core) Authenticate(ctx context.Context, email, password string) (auth.Claims, error) {
+//
+// Failed attempts are tracked per account and per IP address. Both have to
+// wait longer after every failure and an account is locked after too many.
+func (c Core) Authenticate(ctx context.Context, email, password, ip string, now time.Time) (auth.Claims, error) {
 
 	// Email Validate function in validate.
 	if !validate.CheckEmail(email) {
 		return auth.Claims{}, ErrInvalidEmail
 	}
+	account := strings.ToLower(email)
+
+	// Count the attempt before looking at the password, so the password can't
+	// be guessed while waiting or by concurrent attempts.
+	at, err := c.countAttempt(ctx, account, ip, now)
+	if err != nil {
+		return auth.Claims{}, err
+	}
 
 	dbUser, err := c.store.QueryByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, database.ErrDBNotFound) {
+			if err := c.loginFailed(ctx, nil, account, ip, at, now); err != nil {
+				return auth.Claims{}, err
+			}
 			return auth.Claims{}, ErrNotFound
 		}
+		if err := refundAttempt(ctx, c.store, account, ip, at); err != nil {
+			return auth.Claims{}, err
+		}
 		return auth.Claims{}, fmt.Errorf("query: %w", err)
 	}
 
 	// Compare the provided password with the saved hash. Use the bcrypt
 	// comparison function so it is cryptographically secure.
 	if err := bcrypt.CompareHashAndPassword(dbUser.PasswordHash, []byte(password)); err != nil {
+		if err := c.loginFailed(ctx, &dbUser, account, ip, at, now); err != nil {
+			return auth.Claims{}, err
+		}
 		return auth.Claims{}, ErrAuthenticationFailure
 	}
 
+	tran := func(tx sqlx.ExtContext) error {
+		return loginSucceeded(ctx, c.store.Tran(tx), account, ip, at)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if err := refundAttempt(ctx, c.store, account, ip, at); err != nil {
+			return auth.Claims{}, err
+		}
+		return auth.Claims{}, fmt.Errorf("tran: %w", err)
+	}
+
 	// If we are this far the request is valid. Create some claims for the user
 	// and generate their token.
-	claims := auth.Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   dbUser.ID,
-			Issuer:    "wakt project",
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		},
+	return NewClaims(dbUser.ID, now), nil
+}
+
+// UnlockAccount lets the user an unlock token was emailed to sign in again
+// right away. Every other unlock token of the user is used up.
+func (c Core) UnlockAccount(ctx context.Context, ua UnlockAccount, now time.Time) error {
+	if err := validate.Check(ua); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		dbUnlock, err := store.UseAccountUnlock(ctx, hashToken(ua.Token))
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+
+		if !now.Before(dbUnlock.DateExpires) {
+			return ErrInvalidToken
+		}
+
+		dbUser, err := store.QueryByID(ctx, dbUnlock.UID)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+
+		if err := store.DeleteLoginFailure(ctx, failureAccount, strings.ToLower(dbUser.Email)); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if err := store.DeleteAccountUnlocks(ctx, dbUser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if err := c.recordEvent(ctx, c.eventsStore.Tran(tx), &dbUser.ID, dbUser.Email, "", eventdb.TypeAccountUnlocked, now); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// NewRefreshToken starts a refresh token session for the access token of the
+// claims and returns its first refresh token.
+func (c Core) NewRefreshToken(ctx context.Context, claims auth.Claims, now time.Time) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	dbRefresh := db.RefreshToken{
+		TokenHash:   hashToken(token),
+		UID:         claims.Subject,
+		SessionID:   validate.GenerateID(),
+		JTI:         claims.ID,
+		DateExpires: now.Add(refreshTTL),
+		DateCreated: now,
+	}
+
+	if err := c.store.CreateRefreshToken(ctx, dbRefresh); err != nil {
+		return "", fmt.Errorf("create: %w", err)
+	}
+
+	return token, nil
+}
+
+// Refresh exchanges a refresh token for the claims of a new access token and
+// a new refresh token of the same session. A refresh token can only be used
+// once, using it again revokes the whole session since it was likely stolen.
+func (c Core) Refresh(ctx context.Context, rt RefreshToken, now time.Time) (auth.Claims, string, error) {
+	if err := validate.Check(rt); err != nil {
+		return auth.Claims{}, "", fmt.Errorf("validating data: %w", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return auth.Claims{}, "", err
+	}
+
+	var claims auth.Claims
+	var reused string
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		dbRefresh, err := store.QueryRefreshToken(ctx, hashToken(rt.Token))
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+
+		if dbRefresh.DateUsed != nil {
+			reused = dbRefresh.SessionID
+			return nil
+		}
+
+		if !now.Before(dbRefresh.DateExpires) {
+			return ErrInvalidToken
+		}
+
+		if err := store.UseRefreshToken(ctx, dbRefresh.TokenHash, now); err != nil {
+			return fmt.Errorf("use: %w", err)
+		}
+
+		claims = NewClaims(dbRefresh.UID, now)
+		next := db.RefreshToken{
+			TokenHash:   hashToken(token),
+			UID:         dbRefresh.UID,
+			SessionID:   dbRefresh.SessionID,
+			JTI:         claims.ID,
+			DateExpires: now.Add(refreshTTL),
+			DateCreated: now,
+		}
+
+		if err := store.CreateRefreshToken(ctx, next); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			return auth.Claims{}, "", ErrInvalidToken
+		}
+		return auth.Claims{}, "", fmt.Errorf("tran: %w", err)
+	}
+
+	if reused != "" {
+		revoke := func(tx sqlx.ExtContext) error {
+			return c.store.Tran(tx).RevokeRefreshSession(ctx, reused, now.Add(accessTTL))
+		}
+		if err := c.store.WithinTran(ctx, revoke); err != nil {
+			return auth.Claims{}, "", fmt.Errorf("tran: %w", err)
+		}
+		return auth.Claims{}, "", ErrTokenReused
+	}
+
+	return claims, token, nil
+}
+
+// Logout revokes the access token of the claims along with the refresh token
+// session it belongs to.
+func (c Core) Logout(ctx context.Context, claims auth.Claims, now time.Time) error {
+	if err := validate.CheckID(claims.ID); err != nil {
+		return ErrInvalidID
+	}
+
+	expires := now.Add(accessTTL)
+	if claims.ExpiresAt != nil {
+		expires = claims.ExpiresAt.Time
+	}
+
+	dbRefresh, err := c.store.QueryRefreshTokenByJTI(ctx, claims.ID)
+	if err != nil && !errors.Is(err, database.ErrDBNotFound) {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		if err := store.RevokeToken(ctx, claims.ID, expires); err != nil {
+			return fmt.Errorf("revoke: %w", err)
+		}
+
+		if dbRefresh.SessionID != "" {
+			if err := store.RevokeRefreshSession(ctx, dbRefresh.SessionID, now.Add(accessTTL)); err != nil {
+				return fmt.Errorf("revoke: %w", err)
+			}
+		}
+
+		if err := store.DeleteExpiredTokens(ctx, now); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// ForgotPassword emails a single use token to reset the password of the user
+// with the email. Unknown emails are ignored, so callers can not tell whether
+// an account exists.
+func (c Core) ForgotPassword(ctx context.Context, fp ForgotPassword, now time.Time) error {
+	if err := validate.Check(fp); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	dbUser, err := c.store.QueryByEmail(ctx, fp.Email)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return nil
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	dbReset := db.PasswordReset{
+		TokenHash:   hashToken(token),
+		UID:         dbUser.ID,
+		DateExpires: now.Add(resetTTL),
+		DateCreated: now,
+	}
+
+	if err := c.store.CreatePasswordReset(ctx, dbReset); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	// A failing email is only logged, an error would tell the account exists.
+	if err := send.Email("example@example.com", dbUser.Email, "Reset your WAKT password", "www.example.com/reset_password?token="+token); err != nil {
+		c.log.Errorw("forgot password", "userID", dbUser.ID, "ERROR", fmt.Errorf("send email: %w", err))
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password for the user a reset token was issued
+// to. It uses up every reset token of the user and revokes all the sessions
+// and personal API tokens of the user.
+func (c Core) ResetPassword(ctx context.Context, rp ResetPassword, now time.Time) error {
+	if err := validate.Check(rp); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(rp.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("generating password hash: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		// The token is removed as it is read, so concurrent requests can't
+		// both use it.
+		dbReset, err := store.UsePasswordReset(ctx, hashToken(rp.Token))
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+
+		if !now.Before(dbReset.DateExpires) {
+			return ErrInvalidToken
+		}
+
+		dbUser, err := store.QueryByID(ctx, dbReset.UID)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+		dbUser.PasswordHash = hash
+		dbUser.DateUpdated = now
+
+		if err := store.Update(ctx, dbUser); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+
+		if err := store.DeletePasswordResets(ctx, dbUser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if err := store.DeleteRefreshTokens(ctx, dbUser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if err := store.RevokeSessions(ctx, dbUser.ID, now.Truncate(time.Second)); err != nil {
+			return fmt.Errorf("revoke: %w", err)
+		}
+
+		if err := c.tokensStore.Tran(tx).DeleteUserTokens(ctx, dbUser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// CheckSession makes sure the token of the claims was not revoked, by itself
+// or with every session of the user since it was issued.
+func (c Core) CheckSession(ctx context.Context, claims auth.Claims) error {
+	if err := validate.CheckID(claims.Subject); err != nil {
+		return ErrInvalidID
+	}
+
+	if claims.ID != "" {
+		if err := validate.CheckID(claims.ID); err != nil {
+			return ErrInvalidID
+		}
+
+		revoked, err := c.store.QueryTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+		if revoked {
+			return ErrSessionRevoked
+		}
+	}
+
+	revoked, err := c.store.QuerySessionsRevoked(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return nil
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if claims.IssuedAt == nil || claims.IssuedAt.Time.Before(revoked) {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+// EnrollTwoFactor generates a new two-factor secret for the user. It only
+// takes effect once the user confirms it with a code of the secret.
+func (c Core) EnrollTwoFactor(ctx context.Context, userID string, now time.Time) (TwoFactorEnrollment, error) {
+	if err := validate.CheckID(userID); err != nil {
+		return TwoFactorEnrollment{}, ErrInvalidID
+	}
+
+	dbUser, err := c.store.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return TwoFactorEnrollment{}, ErrNotFound
+		}
+		return TwoFactorEnrollment{}, fmt.Errorf("query: %w", err)
+	}
+
+	dbTwoFactor, err := c.store.QueryTwoFactor(ctx, userID)
+	if err != nil && !errors.Is(err, database.ErrDBNotFound) {
+		return TwoFactorEnrollment{}, fmt.Errorf("query: %w", err)
+	}
+	if dbTwoFactor.Confirmed {
+		return TwoFactorEnrollment{}, ErrTwoFactorEnabled
 	}
-	return claims, nil
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	dbTwoFactor = db.TwoFactor{
+		UID:         userID,
+		Secret:      secret,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.store.SaveTwoFactor(ctx, dbTwoFactor); err != nil {
+		return TwoFactorEnrollment{}, fmt.Errorf("save: %w", err)
+	}
+
+	enrollment := TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI("WAKT", dbUser.Email, secret),
+	}
+
+	return enrollment, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication for the user with a code
+// of the enrolled secret. It returns the recovery codes of the user, which
+// are only known here.
+func (c Core) ConfirmTwoFactor(ctx context.Context, userID string, tc TwoFactorCode, now time.Time) ([]string, error) {
+	if err := validate.CheckID(userID); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	if err := validate.Check(tc); err != nil {
+		return nil, fmt.Errorf("validating data: %w", err)
+	}
+
+	var codes []string
+	tran := func(tx sqlx.ExtContext) error {
+		var err error
+		codes, err = c.confirmTwoFactor(ctx, c.store.Tran(tx), userID, tc.Code, now)
+		return err
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return nil, fmt.Errorf("tran: %w", err)
+	}
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off for the user with a
+// code of the secret or a recovery code. Users of a workspace that requires
+// two-factor authentication can't turn it off.
+func (c Core) DisableTwoFactor(ctx context.Context, userID string, tc TwoFactorCode, now time.Time) error {
+	if err := validate.CheckID(userID); err != nil {
+		return ErrInvalidID
+	}
+
+	if err := validate.Check(tc); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	required, err := c.store.QueryTwoFactorRequired(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		dbTwoFactor, err := store.QueryTwoFactor(ctx, userID)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrTwoFactorNotEnabled
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+		if !dbTwoFactor.Confirmed {
+			return ErrTwoFactorNotEnabled
+		}
+
+		if err := c.checkCode(ctx, store, dbTwoFactor, tc.Code, now); err != nil {
+			return err
+		}
+
+		if err := store.DeleteTwoFactor(ctx, userID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// Challenge starts the second step of signing in for users with two-factor
+// authentication, and for users a workspace requires it of who still need to
+// enroll. The token of the challenge is empty when no second step is needed.
+func (c Core) Challenge(ctx context.Context, userID string, now time.Time) (TwoFactorChallenge, error) {
+	if err := validate.CheckID(userID); err != nil {
+		return TwoFactorChallenge{}, ErrInvalidID
+	}
+
+	dbTwoFactor, err := c.store.QueryTwoFactor(ctx, userID)
+	if err != nil && !errors.Is(err, database.ErrDBNotFound) {
+		return TwoFactorChallenge{}, fmt.Errorf("query: %w", err)
+	}
+
+	var challenge TwoFactorChallenge
+	if !dbTwoFactor.Confirmed {
+		required, err := c.store.QueryTwoFactorRequired(ctx, userID)
+		if err != nil {
+			return TwoFactorChallenge{}, fmt.Errorf("query: %w", err)
+		}
+		if !required {
+			return TwoFactorChallenge{}, nil
+		}
+		challenge.Enroll = true
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return TwoFactorChallenge{}, err
+	}
+
+	dbChallenge := db.TwoFactorChallenge{
+		TokenHash:   hashToken(token),
+		UID:         userID,
+		DateExpires: now.Add(challengeTTL),
+		DateCreated: now,
+	}
+
+	if err := c.store.CreateTwoFactorChallenge(ctx, dbChallenge); err != nil {
+		return TwoFactorChallenge{}, fmt.Errorf("create: %w", err)
+	}
+
+	challenge.Token = token
+	return challenge, nil
+}
+
+// EnrollChallenge generates a new two-factor secret for the user of a
+// challenge that still needs to enroll.
+func (c Core) EnrollChallenge(ctx context.Context, ec EnrollChallenge, now time.Time) (TwoFactorEnrollment, error) {
+	if err := validate.Check(ec); err != nil {
+		return TwoFactorEnrollment{}, fmt.Errorf("validating data: %w", err)
+	}
+
+	dbChallenge, err := c.queryChallenge(ctx, ec.ChallengeToken, now)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	return c.EnrollTwoFactor(ctx, dbChallenge.UID, now)
+}
+
+// VerifyChallenge completes signing in with a code of the secret or a
+// recovery code. For users that are enrolling the code confirms the secret,
+// and the recovery codes of the user are returned as well.
+//
+// A challenge only takes a few codes, and wrong codes count as failed sign in
+// attempts of the account and the IP address like wrong passwords do.
+func (c Core) VerifyChallenge(ctx context.Context, vc VerifyChallenge, ip string, now time.Time) (auth.Claims, []string, error) {
+	if err := validate.Check(vc); err != nil {
+		return auth.Claims{}, nil, fmt.Errorf("validating data: %w", err)
+	}
+
+	// The attempt is counted before the code is checked, so concurrent
+	// requests can't try more codes than the challenge takes.
+	dbChallenge, err := c.store.AttemptTwoFactorChallenge(ctx, hashToken(vc.ChallengeToken), challengeAttempts)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return auth.Claims{}, nil, ErrInvalidToken
+		}
+		return auth.Claims{}, nil, fmt.Errorf("query: %w", err)
+	}
+
+	if !now.Before(dbChallenge.DateExpires) {
+		return auth.Claims{}, nil, ErrInvalidToken
+	}
+
+	dbUser, err := c.store.QueryByID(ctx, dbChallenge.UID)
+	if err != nil {
+		return auth.Claims{}, nil, fmt.Errorf("query: %w", err)
+	}
+	account := strings.ToLower(dbUser.Email)
+
+	at, err := c.countAttempt(ctx, account, ip, now)
+	if err != nil {
+		return auth.Claims{}, nil, err
+	}
+
+	var codes []string
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		dbTwoFactor, err := store.QueryTwoFactor(ctx, dbChallenge.UID)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrTwoFactorNotEnabled
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+
+		if dbTwoFactor.Confirmed {
+			if err := c.checkCode(ctx, store, dbTwoFactor, vc.Code, now); err != nil {
+				return err
+			}
+		} else {
+			if codes, err = c.confirmTwoFactor(ctx, store, dbChallenge.UID, vc.Code, now); err != nil {
+				return err
+			}
+		}
+
+		if err := store.DeleteTwoFactorChallenges(ctx, dbChallenge.UID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return loginSucceeded(ctx, store, account, ip, at)
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		if errors.Is(err, ErrInvalidCode) {
+			if err := c.challengeFailed(ctx, dbChallenge, dbUser, account, ip, at, now); err != nil {
+				return auth.Claims{}, nil, err
+			}
+			return auth.Claims{}, nil, fmt.Errorf("tran: %w", err)
+		}
+
+		// The code was never checked, so the attempt does not count.
+		if err := refundAttempt(ctx, c.store, account, ip, at); err != nil {
+			return auth.Claims{}, nil, err
+		}
+		return auth.Claims{}, nil, fmt.Errorf("tran: %w", err)
+	}
+
+	return NewClaims(dbChallenge.UID, now), codes, nil
+}
+
+// challengeFailed records a wrong code of a two-factor challenge as a failed
+// sign in attempt and drops the challenge once it has no attempts left.
+func (c Core) challengeFailed(ctx context.Context, dbChallenge db.TwoFactorChallenge, dbUser db.User, account, ip string, at attempt, now time.Time) error {
+	if dbChallenge.Attempts >= challengeAttempts {
+		if err := c.store.DeleteTwoFactorChallenge(ctx, dbChallenge.TokenHash); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+	}
+
+	return c.loginFailed(ctx, &dbUser, account, ip, at, now)
+}
+
+// queryChallenge gets the two-factor challenge of the token when it did not
+// expire yet.
+func (c Core) queryChallenge(ctx context.Context, token string, now time.Time) (db.TwoFactorChallenge, error) {
+	dbChallenge, err := c.store.QueryTwoFactorChallenge(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return db.TwoFactorChallenge{}, ErrInvalidToken
+		}
+		return db.TwoFactorChallenge{}, fmt.Errorf("query: %w", err)
+	}
+
+	if !now.Before(dbChallenge.DateExpires) {
+		return db.TwoFactorChallenge{}, ErrInvalidToken
+	}
+
+	return dbChallenge, nil
+}
+
+// confirmTwoFactor enables the enrolled two-factor secret of the user with a
+// code of it and replaces the recovery codes of the user.
+func (c Core) confirmTwoFactor(ctx context.Context, store db.Store, userID, code string, now time.Time) ([]string, error) {
+	dbTwoFactor, err := store.QueryTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return nil, ErrTwoFactorNotEnabled
+		}
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	if dbTwoFactor.Confirmed {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(dbTwoFactor.Secret, code, now)
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	dbTwoFactor.Confirmed = true
+	dbTwoFactor.LastStep = step
+	dbTwoFactor.DateUpdated = now
+
+	if err := store.UpdateTwoFactor(ctx, dbTwoFactor); err != nil {
+		return nil, fmt.Errorf("udpate: %w", err)
+	}
+
+	if err := store.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete: %w", err)
+	}
+
+	codes := make([]string, recoveryCodes)
+	for i := range codes {
+		code := make([]byte, 5)
+		if _, err := rand.Read(code); err != nil {
+			return nil, fmt.Errorf("generating recovery code: %w", err)
+		}
+		hexCode := hex.EncodeToString(code)
+		codes[i] = hexCode[:5] + "-" + hexCode[5:]
+
+		if err := store.CreateRecoveryCode(ctx, userID, hashToken(hexCode)); err != nil {
+			return nil, fmt.Errorf("create: %w", err)
+		}
+	}
+
+	return codes, nil
+}
+
+// checkCode accepts a code of the two-factor secret that was not used before,
+// or else a recovery code which is used up.
+func (c Core) checkCode(ctx context.Context, store db.Store, dbTwoFactor db.TwoFactor, code string, now time.Time) error {
+	if step, ok := totp.Validate(dbTwoFactor.Secret, code, now); ok {
+		if step <= dbTwoFactor.LastStep {
+			return ErrInvalidCode
+		}
+
+		dbTwoFactor.LastStep = step
+		dbTwoFactor.DateUpdated = now
+
+		if err := store.UpdateTwoFactor(ctx, dbTwoFactor); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+		return nil
+	}
+
+	recovery := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if err := store.UseRecoveryCode(ctx, dbTwoFactor.UID, hashToken(recovery)); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrInvalidCode
+		}
+		return fmt.Errorf("use: %w", err)
+	}
+
+	return nil
+}
+
+// SendVerification emails a token to verify the email of a user that is not
+// verified yet.
+func (c Core) SendVerification(ctx context.Context, userID string, now time.Time) error {
+	if err := validate.CheckID(userID); err != nil {
+		return ErrInvalidID
+	}
+
+	dbUser, err := c.store.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if dbUser.Verified {
+		return ErrVerified
+	}
+
+	return c.sendVerification(ctx, dbUser.ID, dbUser.Email, now)
+}
+
+// VerifyEmail confirms the email a verification token was sent to. The user
+// takes the email on, when it was a change of email, and becomes verified.
+// Every other verification token of the user is used up.
+func (c Core) VerifyEmail(ctx context.Context, ve VerifyEmail, now time.Time) error {
+	if err := validate.Check(ve); err != nil {
+		return fmt.Errorf("validating data: %w", err)
+	}
+
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		// The token is removed as it is read, so concurrent requests can't
+		// both use it.
+		dbVerification, err := store.UseEmailVerification(ctx, hashToken(ve.Token))
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+
+		if !now.Before(dbVerification.DateExpires) {
+			return ErrInvalidToken
+		}
+
+		dbUser, err := store.QueryByID(ctx, dbVerification.UID)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+		dbUser.Email = dbVerification.Email
+		dbUser.Verified = true
+		dbUser.DateUpdated = now
+
+		if err := store.Update(ctx, dbUser); err != nil {
+			if errors.Is(err, database.ErrDBDuplicatedEntry) {
+				return ErrUniqueEmail
+			}
+			return fmt.Errorf("udpate: %w", err)
+		}
+
+		if err := store.DeleteEmailVerifications(ctx, dbUser.ID); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	return nil
+}
+
+// attempt holds the failed sign in attempts of the account and the IP
+// address, counting the attempt being checked. The IP address is nil when it
+// is unknown.
+type attempt struct {
+	account db.LoginFailure
+	ip      *db.LoginFailure
+}
+
+// countAttempt counts a sign in attempt on the account and the IP address as
+// failed before it is checked, and makes them wait before the next one.
+// Concurrent attempts are counted one after the other, so none of them gets
+// past the wait of another. An error is returned when the account or the IP
+// address has to wait.
+func (c Core) countAttempt(ctx context.Context, account, ip string, now time.Time) (attempt, error) {
+	var at attempt
+	tran := func(tx sqlx.ExtContext) error {
+		store := c.store.Tran(tx)
+
+		if ip != "" {
+			dbFailure, err := store.RecordLoginAttempt(ctx, failureIP, ip, now.Add(-failureWindow), now)
+			if err != nil {
+				if errors.Is(err, database.ErrDBNotFound) {
+					return ErrTooManyAttempts
+				}
+				return fmt.Errorf("create: %w", err)
+			}
+
+			if dbFailure.Failures >= ipFreeAttempts {
+				until := now.Add(backoff(dbFailure.Failures - ipFreeAttempts))
+				if err := store.LockLoginFailure(ctx, failureIP, ip, until); err != nil {
+					return fmt.Errorf("udpate: %w", err)
+				}
+				dbFailure.DateLockedUntil = &until
+			}
+			at.ip = &dbFailure
+		}
+
+		dbFailure, err := store.RecordLoginAttempt(ctx, failureAccount, account, now.Add(-failureWindow), now)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return lockedError(ctx, store, account)
+			}
+			return fmt.Errorf("create: %w", err)
+		}
+
+		switch {
+		case dbFailure.Failures >= accountLockAttempts:
+			if err := store.LockLoginFailure(ctx, failureAccount, account, now.Add(lockoutTTL)); err != nil {
+				return fmt.Errorf("udpate: %w", err)
+			}
+
+		case dbFailure.Failures >= accountFreeAttempts:
+			if err := store.LockLoginFailure(ctx, failureAccount, account, now.Add(backoff(dbFailure.Failures-accountFreeAttempts))); err != nil {
+				return fmt.Errorf("udpate: %w", err)
+			}
+		}
+		at.account = dbFailure
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return attempt{}, fmt.Errorf("tran: %w", err)
+	}
+
+	return at, nil
+}
+
+// lockedError tells whether an account that has to wait is locked or only
+// has to wait after a failed attempt.
+func lockedError(ctx context.Context, store db.Store, account string) error {
+	dbFailure, err := store.QueryLoginFailure(ctx, failureAccount, account)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if dbFailure.Failures >= accountLockAttempts {
+		return ErrAccountLocked
+	}
+	return ErrTooManyAttempts
+}
+
+// loginSucceeded forgets the failed sign in attempts of the account and takes
+// the counted attempt back from the IP address.
+func loginSucceeded(ctx context.Context, store db.Store, account, ip string, at attempt) error {
+	if err := store.DeleteLoginFailure(ctx, failureAccount, account); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if at.ip != nil {
+		if err := store.RefundLoginAttempt(ctx, failureIP, ip, at.ip.DateLockedUntil); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// refundAttempt takes back a counted sign in attempt on the account and the IP
+// address that failed before the credentials could be checked.
+func refundAttempt(ctx context.Context, store db.Store, account, ip string, at attempt) error {
+	if err := store.RefundLoginAttempt(ctx, failureAccount, account, at.account.DateLockedUntil); err != nil {
+		return fmt.Errorf("udpate: %w", err)
+	}
+
+	if at.ip != nil {
+		if err := store.RefundLoginAttempt(ctx, failureIP, ip, at.ip.DateLockedUntil); err != nil {
+			return fmt.Errorf("udpate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loginFailed records the security events of a failed sign in attempt, which
+// was counted already. The user is nil for an unknown email. An unlock token
+// is emailed to a user whose account got locked.
+func (c Core) loginFailed(ctx context.Context, dbUser *db.User, account, ip string, at attempt, now time.Time) error {
+	var userID *string
+	if dbUser != nil {
+		userID = &dbUser.ID
+	}
+
+	locked := at.account.Failures >= accountLockAttempts
+	tran := func(tx sqlx.ExtContext) error {
+		eventsStore := c.eventsStore.Tran(tx)
+
+		if err := c.recordEvent(ctx, eventsStore, userID, account, ip, eventdb.TypeLoginFailed, now); err != nil {
+			return err
+		}
+
+		if locked {
+			if err := c.recordEvent(ctx, eventsStore, userID, account, ip, eventdb.TypeAccountLocked, now); err != nil {
+				return err
+			}
+		}
+
+		// Only the first throttled attempt is recorded, the failed attempts
+		// tell the rest.
+		if at.ip != nil && at.ip.Failures == ipFreeAttempts {
+			if err := c.recordEvent(ctx, eventsStore, nil, account, ip, eventdb.TypeIPThrottled, now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := c.store.WithinTran(ctx, tran); err != nil {
+		return fmt.Errorf("tran: %w", err)
+	}
+
+	if locked && dbUser != nil {
+		if err := c.sendUnlock(ctx, *dbUser, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendUnlock emails a token to unlock the account of the user.
+func (c Core) sendUnlock(ctx context.Context, dbUser db.User, now time.Time) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	dbUnlock := db.AccountUnlock{
+		TokenHash:   hashToken(token),
+		UID:         dbUser.ID,
+		DateExpires: now.Add(unlockTTL),
+		DateCreated: now,
+	}
+
+	if err := c.store.CreateAccountUnlock(ctx, dbUnlock); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	if err := send.Email("example@example.com", dbUser.Email, "Your WAKT account was locked", "www.example.com/unlock_account?token="+token); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+
+	return nil
+}
+
+// recordEvent adds an event to the security event log.
+func (c Core) recordEvent(ctx context.Context, eventsStore eventdb.Store, userID *string, email, ip, eventType string, now time.Time) error {
+	dbEvent := eventdb.Event{
+		ID:          validate.GenerateID(),
+		UID:         userID,
+		Email:       email,
+		IP:          ip,
+		Type:        eventType,
+		DateCreated: now,
+	}
+
+	if err := eventsStore.Create(ctx, dbEvent); err != nil {
+		return fmt.Errorf("create event: %w", err)
+	}
+
+	return nil
+}
+
+// backoff returns the time to wait after the number of failures past the
+// free attempts.
+func backoff(failures int) time.Duration {
+	if failures >= 30 {
+		return backoffMax
+	}
+
+	wait := backoffBase << failures
+	if wait > backoffMax {
+		return backoffMax
+	}
+	return wait
+}
+
+// sendVerification emails a token to verify that the user owns the email.
+func (c Core) sendVerification(ctx context.Context, userID, email string, now time.Time) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	dbVerification := db.EmailVerification{
+		TokenHash:   hashToken(token),
+		UID:         userID,
+		Email:       email,
+		DateExpires: now.Add(verifyTTL),
+		DateCreated: now,
+	}
+
+	if err := c.store.CreateEmailVerification(ctx, dbVerification); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	if err := send.Email("example@example.com", email, "Verify your WAKT email", "www.example.com/verify_email?token="+token); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+
+	return nil
+}
+
+// NewClaims creates the claims of a new access token for the user. Every way
+// of signing in issues its tokens with these claims.
+func NewClaims(userID string, now time.Time) auth.Claims {
+	return auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        validate.GenerateID(),
+			Subject:   userID,
+			Issuer:    "wakt project",
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+}
+
+// newToken generates a random token to email to a user.
+func newToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// hashToken returns the hash a token is stored by, so the tokens can not be
+// used by anyone reading the database.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
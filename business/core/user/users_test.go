@@ -2,9 +2,11 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/AhmedShaef/wakt/business/data/dbtest"
 	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/totp"
 	"github.com/AhmedShaef/wakt/foundation/docker"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/go-cmp/cmp"
@@ -51,7 +53,7 @@ func TestUser(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould be able to create user.", dbtest.Success, testID)
 
-			authenticate, err := core.Authenticate(ctx, nu.Email, nu.Password)
+			authenticate, err := core.Authenticate(ctx, nu.Email, nu.Password, "127.0.0.1", now)
 			if err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to authenticate user : %s.", dbtest.Failed, testID, err)
 			}
@@ -59,6 +61,7 @@ func TestUser(t *testing.T) {
 
 			claim := auth.Claims{
 				RegisteredClaims: jwt.RegisteredClaims{
+					ID:        authenticate.ID,
 					Subject:   user.ID,
 					Issuer:    "wakt project",
 					ExpiresAt: authenticate.ExpiresAt,
@@ -133,3 +136,453 @@ func TestUser(t *testing.T) {
 		}
 	}
 }
+
+func TestResetPassword(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testresetpassword")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to reset a forgotten password.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a reset token.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+			const token = "5f0c2b1e9d7a4c3b8e6f1a2d4c7b9e0f"
+
+			const q = `
+			INSERT INTO password_resets
+				(token_hash, uid, date_expires, date_created)
+			VALUES
+				($1, $2, $3, $4)`
+
+			if _, err := db.ExecContext(ctx, q, hashToken(token), userID, now.Add(resetTTL), now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert reset token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert reset token.", dbtest.Success, testID)
+
+			const qt = `
+			INSERT INTO api_tokens
+				(token_id, uid, name, token_hash, scopes, date_created)
+			VALUES
+				('b3f5a1c2-7d4e-4f6a-9b8c-0d1e2f3a4b5c', $1, 'ci', 'hash', '{}', $2)`
+
+			if _, err := db.ExecContext(ctx, qt, userID, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert api token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert api token.", dbtest.Success, testID)
+
+			if err := core.ForgotPassword(ctx, ForgotPassword{Email: "nobody@example.com"}, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not reveal an unknown email : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not reveal an unknown email.", dbtest.Success, testID)
+
+			expired := ResetPassword{Token: token, Password: "n3w-pa55word"}
+			if err := core.ResetPassword(ctx, expired, now.Add(2*resetTTL)); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept an expired token : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept an expired token.", dbtest.Success, testID)
+
+			session := auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject:  userID,
+					IssuedAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+				},
+			}
+			if err := core.CheckSession(ctx, session); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould accept the session before the reset : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould accept the session before the reset.", dbtest.Success, testID)
+
+			rp := ResetPassword{Token: token, Password: "n3w-pa55word"}
+			if err := core.ResetPassword(ctx, rp, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to reset the password : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to reset the password.", dbtest.Success, testID)
+
+			if err := core.ResetPassword(ctx, rp, now); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to use the token twice : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to use the token twice.", dbtest.Success, testID)
+
+			if err := core.CheckSession(ctx, session); !errors.Is(err, ErrSessionRevoked) {
+				t.Fatalf("\t%s\tTest %d:\tShould revoke the session : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould revoke the session.", dbtest.Success, testID)
+
+			tokens, err := core.tokensStore.QueryUserTokens(ctx, userID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to query api tokens : %s.", dbtest.Failed, testID, err)
+			}
+			if len(tokens) != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould revoke the api tokens : got %d.", dbtest.Failed, testID, len(tokens))
+			}
+			t.Logf("\t%s\tTest %d:\tShould revoke the api tokens.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestLockout(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testlockout")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to slow down guessing passwords.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling failed sign in attempts.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+			const email = "user@example.com"
+			const ip = "192.0.2.1"
+
+			for i := 0; i < accountFreeAttempts; i++ {
+				if _, err := core.Authenticate(ctx, email, "wrong-password", ip, now); !errors.Is(err, ErrAuthenticationFailure) {
+					t.Fatalf("\t%s\tTest %d:\tShould fail to authenticate with a wrong password : %v.", dbtest.Failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail to authenticate with a wrong password.", dbtest.Success, testID)
+
+			if _, err := core.Authenticate(ctx, email, "wrong-password", ip, now); !errors.Is(err, ErrTooManyAttempts) {
+				t.Fatalf("\t%s\tTest %d:\tShould have to wait after the free attempts : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have to wait after the free attempts.", dbtest.Success, testID)
+
+			if _, err := core.Authenticate(ctx, email, "wrong-password", ip, now.Add(backoffBase)); !errors.Is(err, ErrAuthenticationFailure) {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to try again after waiting : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to try again after waiting.", dbtest.Success, testID)
+
+			var failed int
+			if err := db.GetContext(ctx, &failed, `SELECT COUNT(*) FROM security_events WHERE CAST(uid AS text) = $1 AND type = 'login_failed'`, userID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count the security events : %s.", dbtest.Failed, testID, err)
+			}
+			if failed != accountFreeAttempts+1 {
+				t.Fatalf("\t%s\tTest %d:\tShould log every failed attempt : got %d.", dbtest.Failed, testID, failed)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log every failed attempt.", dbtest.Success, testID)
+
+			// Lock the account the way reaching the lock attempts does, without
+			// sending the unlock email.
+			const lock = `
+			UPDATE login_failures SET failures = $1, date_locked_until = $2
+			WHERE kind = 'account' AND subject = $3`
+
+			if _, err := db.ExecContext(ctx, lock, accountLockAttempts, now.Add(lockoutTTL), email); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to lock the account : %s.", dbtest.Failed, testID, err)
+			}
+
+			if _, err := core.Authenticate(ctx, email, "wrong-password", ip, now.Add(time.Minute)); !errors.Is(err, ErrAccountLocked) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to sign in to a locked account : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to sign in to a locked account.", dbtest.Success, testID)
+
+			const token = "9c4f2e7a1b3d5f6e8a0c2b4d6f8e1a3c"
+
+			const q = `
+			INSERT INTO account_unlocks
+				(token_hash, uid, date_expires, date_created)
+			VALUES
+				($1, $2, $3, $4)`
+
+			if _, err := db.ExecContext(ctx, q, hashToken(token), userID, now.Add(unlockTTL), now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert unlock token : %s.", dbtest.Failed, testID, err)
+			}
+
+			if err := core.UnlockAccount(ctx, UnlockAccount{Token: token}, now.Add(time.Minute)); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to unlock the account : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to unlock the account.", dbtest.Success, testID)
+
+			if _, err := core.Authenticate(ctx, email, "wrong-password", ip, now.Add(time.Minute)); !errors.Is(err, ErrAuthenticationFailure) {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to try again once unlocked : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to try again once unlocked.", dbtest.Success, testID)
+
+			if err := core.UnlockAccount(ctx, UnlockAccount{Token: token}, now.Add(time.Minute)); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to use the token twice : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to use the token twice.", dbtest.Success, testID)
+
+			// Concurrent attempts are counted one after the other, so only the
+			// free attempts get to have their password checked.
+			const attempts = 10
+			errs := make(chan error, attempts)
+			for i := 0; i < attempts; i++ {
+				go func() {
+					_, err := core.Authenticate(ctx, "nobody@example.com", "wrong-password", "", now)
+					errs <- err
+				}()
+			}
+
+			var checked int
+			for i := 0; i < attempts; i++ {
+				switch err := <-errs; {
+				case errors.Is(err, ErrNotFound):
+					checked++
+				case !errors.Is(err, ErrTooManyAttempts):
+					t.Fatalf("\t%s\tTest %d:\tShould have to wait after the free attempts : %v.", dbtest.Failed, testID, err)
+				}
+			}
+			if checked != accountFreeAttempts {
+				t.Fatalf("\t%s\tTest %d:\tShould only check the free attempts of concurrent attempts : got %d.", dbtest.Failed, testID, checked)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only check the free attempts of concurrent attempts.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testverifyemail")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to verify a changed email.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a verification token.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+			const token = "9c1d7e3a5b2f4e6d8a0c1b3e5f7a9d2c"
+			const email = "verified@example.com"
+
+			const q = `
+			INSERT INTO email_verifications
+				(token_hash, uid, email, date_expires, date_created)
+			VALUES
+				($1, $2, $3, $4, $5)`
+
+			if _, err := db.ExecContext(ctx, q, hashToken(token), userID, email, now.Add(verifyTTL), now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to insert verification token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to insert verification token.", dbtest.Success, testID)
+
+			ve := VerifyEmail{Token: token}
+			if err := core.VerifyEmail(ctx, ve, now.Add(2*verifyTTL)); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept an expired token : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept an expired token.", dbtest.Success, testID)
+
+			if err := core.VerifyEmail(ctx, ve, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to verify the email : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to verify the email.", dbtest.Success, testID)
+
+			usr, err := core.QueryByID(ctx, userID)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve user by ID : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve user by ID.", dbtest.Success, testID)
+
+			if !usr.Verified || usr.Email != email {
+				t.Fatalf("\t%s\tTest %d:\tShould have the verified email : %s %v.", dbtest.Failed, testID, usr.Email, usr.Verified)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have the verified email.", dbtest.Success, testID)
+
+			if err := core.VerifyEmail(ctx, ve, now); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to use the token twice : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to use the token twice.", dbtest.Success, testID)
+
+			if err := core.SendVerification(ctx, userID, now); !errors.Is(err, ErrVerified) {
+				t.Fatalf("\t%s\tTest %d:\tShould not send a verification to a verified email : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not send a verification to a verified email.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testrefreshtoken")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to refresh and revoke API tokens.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a refresh token.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+			claims := NewClaims(userID, now)
+			refresh, err := core.NewRefreshToken(ctx, claims, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create a refresh token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create a refresh token.", dbtest.Success, testID)
+
+			next, rotated, err := core.Refresh(ctx, RefreshToken{Token: refresh}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to refresh the token : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to refresh the token.", dbtest.Success, testID)
+
+			if next.Subject != userID || next.ID == claims.ID || rotated == refresh {
+				t.Fatalf("\t%s\tTest %d:\tShould get new tokens for the user : %+v.", dbtest.Failed, testID, next)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get new tokens for the user.", dbtest.Success, testID)
+
+			if _, _, err := core.Refresh(ctx, RefreshToken{Token: refresh}, now); !errors.Is(err, ErrTokenReused) {
+				t.Fatalf("\t%s\tTest %d:\tShould detect the reuse of a refresh token : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould detect the reuse of a refresh token.", dbtest.Success, testID)
+
+			if _, _, err := core.Refresh(ctx, RefreshToken{Token: rotated}, now); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould revoke the session on reuse : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould revoke the session on reuse.", dbtest.Success, testID)
+
+			if err := core.CheckSession(ctx, next); !errors.Is(err, ErrSessionRevoked) {
+				t.Fatalf("\t%s\tTest %d:\tShould revoke the access tokens of the session : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould revoke the access tokens of the session.", dbtest.Success, testID)
+
+			other := NewClaims(userID, now)
+			if _, err := core.NewRefreshToken(ctx, other, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create a refresh token : %s.", dbtest.Failed, testID, err)
+			}
+
+			if err := core.Logout(ctx, other, now); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to logout : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to logout.", dbtest.Success, testID)
+
+			if err := core.CheckSession(ctx, other); !errors.Is(err, ErrSessionRevoked) {
+				t.Fatalf("\t%s\tTest %d:\tShould revoke the token on logout : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould revoke the token on logout.", dbtest.Success, testID)
+		}
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	log, db, teardown := dbtest.NewUnit(t, c, "testtwofactor")
+	t.Cleanup(teardown)
+
+	core := NewCore(log, db)
+
+	t.Log("Given the need to sign in with two-factor authentication.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling a TOTP secret.", testID)
+		{
+			ctx := context.Background()
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+			const userID = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+			enrollment, err := core.EnrollTwoFactor(ctx, userID, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to enroll : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to enroll.", dbtest.Success, testID)
+
+			code, err := totp.Code(enrollment.Secret, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to compute a code : %s.", dbtest.Failed, testID, err)
+			}
+
+			recovery, err := core.ConfirmTwoFactor(ctx, userID, TwoFactorCode{Code: code}, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to confirm the secret : %s.", dbtest.Failed, testID, err)
+			}
+			if len(recovery) != recoveryCodes {
+				t.Fatalf("\t%s\tTest %d:\tShould get the recovery codes : %v.", dbtest.Failed, testID, recovery)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to confirm the secret.", dbtest.Success, testID)
+
+			challenge, err := core.Challenge(ctx, userID, now)
+			if err != nil || challenge.Token == "" || challenge.Enroll {
+				t.Fatalf("\t%s\tTest %d:\tShould get a challenge to sign in : %v %+v.", dbtest.Failed, testID, err, challenge)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a challenge to sign in.", dbtest.Success, testID)
+
+			vc := VerifyChallenge{ChallengeToken: challenge.Token, Code: code}
+			if _, _, err := core.VerifyChallenge(ctx, vc, "127.0.0.1", now); !errors.Is(err, ErrInvalidCode) {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept a code twice : %v.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept a code twice.", dbtest.Success, testID)
+
+			guess, err := core.Challenge(ctx, userID, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get another challenge : %s.", dbtest.Failed, testID, err)
+			}
+			wrong := VerifyChallenge{ChallengeToken: guess.Token, Code: "000000"}
+			for i := 0; i < challengeAttempts; i++ {
+				if _, _, err := core.VerifyChallenge(ctx, wrong, "127.0.0.1", now); err == nil {
+					t.Fatalf("\t%s\tTest %d:\tShould not accept a wrong code.", dbtest.Failed, testID)
+				}
+			}
+			wrong.Code = code
+			if _, _, err := core.VerifyChallenge(ctx, wrong, "127.0.0.1", now); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould use up the challenge after %d attempts : %v.", dbtest.Failed, testID, challengeAttempts, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould use up the challenge after %d attempts.", dbtest.Success, testID, challengeAttempts)
+
+			later := now.Add(30 * time.Second)
+			if vc.Code, err = totp.Code(enrollment.Secret, later); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to compute a code : %s.", dbtest.Failed, testID, err)
+			}
+
+			claims, _, err := core.VerifyChallenge(ctx, vc, "127.0.0.1", later)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to complete the challenge : %s.", dbtest.Failed, testID, err)
+			}
+			if claims.Subject != userID {
+				t.Fatalf("\t%s\tTest %d:\tShould get the claims of the user : %s.", dbtest.Failed, testID, claims.Subject)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to complete the challenge.", dbtest.Success, testID)
+
+			stale, err := core.Challenge(ctx, userID, later)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get another challenge : %s.", dbtest.Failed, testID, err)
+			}
+
+			if err := core.DisableTwoFactor(ctx, userID, TwoFactorCode{Code: recovery[0]}, later); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to disable with a recovery code : %s.", dbtest.Failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to disable with a recovery code.", dbtest.Success, testID)
+
+			vc = VerifyChallenge{ChallengeToken: stale.Token, Code: vc.Code}
+			if _, _, err := core.VerifyChallenge(ctx, vc, "127.0.0.1", later); !errors.Is(err, ErrTwoFactorNotEnabled) {
+				t.Fatalf("\t%s\tTest %d:\tShould not complete a challenge once disabled : %v.", dbtest.Failed, testID, err)
+			}
+
+			const failures = `
+			SELECT COALESCE(SUM(f.failures), 0) FROM login_failures AS f
+			JOIN users AS u ON f.kind = 'account' AND f.subject = lower(u.email)
+			WHERE u.user_id = $1`
+
+			var count int
+			if err := db.GetContext(ctx, &count, failures, userID); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to count the failures : %s.", dbtest.Failed, testID, err)
+			}
+			if count != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould not count an attempt whose code was never checked : %d.", dbtest.Failed, testID, count)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not count an attempt whose code was never checked.", dbtest.Success, testID)
+
+			challenge, err = core.Challenge(ctx, userID, later)
+			if err != nil || challenge.Token != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould not get a challenge once disabled : %v %+v.", dbtest.Failed, testID, err, challenge)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not get a challenge once disabled.", dbtest.Success, testID)
+		}
+	}
+}
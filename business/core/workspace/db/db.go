@@ -49,9 +49,9 @@ func (s Store) Tran(tx sqlx.ExtContext) Store {
 func (s Store) Create(ctx context.Context, workspace Workspace) error {
 	const q = `
 	INSERT INTO workspaces
-		(workspace_id, name, uid, default_hourly_rate, default_currency, only_admin_may_create_projects, only_admin_see_billable_rates, only_admin_see_team_dashboard, rounding, rounding_minutes, date_created, date_updated, logo_url )
+		(workspace_id, name, uid, default_hourly_rate, default_currency, only_admin_may_create_projects, only_admin_see_billable_rates, only_admin_see_team_dashboard, rounding, rounding_minutes, date_created, date_updated, logo_url, task_statuses, only_verified_members, require_two_factor)
 	VALUES
-		(:workspace_id, :name, :uid, :default_hourly_rate, :default_currency, :only_admin_may_create_projects, :only_admin_see_billable_rates, :only_admin_see_team_dashboard, :rounding, :rounding_minutes, :date_created, :date_updated, :logo_url)`
+		(:workspace_id, :name, :uid, :default_hourly_rate, :default_currency, :only_admin_may_create_projects, :only_admin_see_billable_rates, :only_admin_see_team_dashboard, :rounding, :rounding_minutes, :date_created, :date_updated, :logo_url, :task_statuses, :only_verified_members, :require_two_factor)`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, workspace); err != nil {
 		return fmt.Errorf("inserting workspace: %w", err)
@@ -75,7 +75,10 @@ func (s Store) Update(ctx context.Context, workspace Workspace) error {
 		rounding = :rounding,
 		rounding_minutes = :rounding_minutes,
 		date_updated = :date_updated,
-		logo_url = :logo_url
+		logo_url = :logo_url,
+		task_statuses = :task_statuses,
+		only_verified_members = :only_verified_members,
+		require_two_factor = :require_two_factor
 	WHERE
 		workspace_id = :workspace_id`
 
@@ -6,7 +6,14 @@ import (
 	"unsafe"
 )
 
-// Workspace represents an individual Group.
+// DefaultTaskStatuses is the task workflow of a new workspace. The last status
+// of a workflow marks a task as done.
+var DefaultTaskStatuses = []string{"todo", "in_progress", "in_review", "done"}
+
+// Workspace represents an individual Group. OnlyVerifiedMembers keeps users
+// without a verified email from inviting others to the workspace or from
+// being invited to it. RequireTwoFactor makes its members enroll in two-factor
+// authentication before they can sign in.
 type Workspace struct {
 	ID                         string    `json:"id"`
 	Name                       string    `json:"name"`
@@ -21,6 +28,9 @@ type Workspace struct {
 	DateCreated                time.Time `json:"date_created"`
 	DateUpdated                time.Time `json:"date_updated"`
 	LogoURL                    string    `json:"logo_url"`
+	TaskStatuses               []string  `json:"task_statuses"`
+	OnlyVerifiedMembers        bool      `json:"only_verified_members"`
+	RequireTwoFactor           bool      `json:"require_two_factor"`
 }
 
 // NewWorkspace contains information needed to create a new Group.
@@ -45,6 +55,9 @@ type UpdateWorkspace struct {
 	Rounding                   *int     `json:"rounding" validate:"omitempty,eq=0|eq=1|eq=-1"`
 	RoundingMinutes            *int     `json:"rounding_minutes"`
 	LogoURL                    string   `json:"logo_url"`
+	TaskStatuses               []string `json:"task_statuses" validate:"omitempty,min=2,unique,dive,required"`
+	OnlyVerifiedMembers        *bool    `json:"only_verified_members"`
+	RequireTwoFactor           *bool    `json:"require_two_factor"`
 }
 
 // =============================================================================
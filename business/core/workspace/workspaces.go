@@ -54,6 +54,7 @@ func (c Core) Create(ctx context.Context, nw NewWorkspace, now time.Time) (Works
 		DateCreated:                now,
 		DateUpdated:                now,
 		LogoURL:                    "",
+		TaskStatuses:               DefaultTaskStatuses,
 	}
 
 	if err := c.store.Create(ctx, dbworkspace); err != nil {
@@ -105,6 +106,15 @@ func (c Core) Update(ctx context.Context, workspaceID string, uw UpdateWorkspace
 	if uw.RoundingMinutes != nil {
 		dbWorkspace.RoundingMinutes = *uw.RoundingMinutes
 	}
+	if uw.TaskStatuses != nil {
+		dbWorkspace.TaskStatuses = uw.TaskStatuses
+	}
+	if uw.OnlyVerifiedMembers != nil {
+		dbWorkspace.OnlyVerifiedMembers = *uw.OnlyVerifiedMembers
+	}
+	if uw.RequireTwoFactor != nil {
+		dbWorkspace.RequireTwoFactor = *uw.RequireTwoFactor
+	}
 	dbWorkspace.DateUpdated = now
 
 	if err := c.store.Update(ctx, dbWorkspace); err != nil {
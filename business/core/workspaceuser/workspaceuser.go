@@ -10,6 +10,7 @@ import (
 	"time"
 
 	users "github.com/AhmedShaef/wakt/business/core/user/db"
+	workspaces "github.com/AhmedShaef/wakt/business/core/workspace/db"
 	"github.com/AhmedShaef/wakt/business/core/workspaceuser/db"
 	send "github.com/AhmedShaef/wakt/business/send/smtp"
 	"github.com/AhmedShaef/wakt/business/sys/database"
@@ -20,21 +21,24 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound  = errors.New("user not found")
-	ErrInvalidID = errors.New("ID is not in its proper form")
+	ErrNotFound   = errors.New("user not found")
+	ErrInvalidID  = errors.New("ID is not in its proper form")
+	ErrUnverified = errors.New("workspace only allows members with a verified email")
 )
 
 // Core manages the set of APIs for user access.
 type Core struct {
-	store     db.Store
-	userStore users.Store
+	store          db.Store
+	userStore      users.Store
+	workspaceStore workspaces.Store
 }
 
 // NewCore constructs a core for user api access.
 func NewCore(log *zap.SugaredLogger, sqlxDB *sqlx.DB) Core {
 	return Core{
-		store:     db.NewStore(log, sqlxDB),
-		userStore: users.NewStore(log, sqlxDB),
+		store:          db.NewStore(log, sqlxDB),
+		userStore:      users.NewStore(log, sqlxDB),
+		workspaceStore: workspaces.NewStore(log, sqlxDB),
 	}
 }
 
@@ -86,6 +90,10 @@ func (c Core) InviteUser(ctx context.Context, workspaceID string, ni InviteUsers
 		}
 	}
 
+	if err := c.checkVerified(ctx, workspaceID, ni.InviterID, emails); err != nil {
+		return []WorkspaceUser{}, err
+	}
+
 	for _, v := range emails {
 		var userID string
 
@@ -132,6 +140,54 @@ func (c Core) InviteUser(ctx context.Context, workspaceID string, ni InviteUsers
 	return toWorkspaceUserSlice(workspaceUsers), nil
 }
 
+// checkVerified makes sure the inviter and the invited users that already have
+// an account verified their email, when the workspace only allows verified
+// members.
+func (c Core) checkVerified(ctx context.Context, workspaceID, inviterID string, emails []string) error {
+	dbWorkspace, err := c.workspaceStore.QueryByID(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if !dbWorkspace.OnlyVerifiedMembers {
+		return nil
+	}
+
+	dbInviter, err := c.store.QueryByID(ctx, inviterID)
+	if err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("query: %w", err)
+	}
+
+	inviter, err := c.userStore.QueryByID(ctx, dbInviter.UID)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	if !inviter.Verified {
+		return ErrUnverified
+	}
+
+	for _, email := range emails {
+		usr, err := c.userStore.QueryByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				continue
+			}
+			return fmt.Errorf("query: %w", err)
+		}
+		if !usr.Verified {
+			return ErrUnverified
+		}
+	}
+
+	return nil
+}
+
 // Update replaces a workspace user document in the database.
 func (c Core) Update(ctx context.Context, workspaceUserID string, uwu UpdateWorkspaceUser, now time.Time) error {
 	if err := validate.CheckID(workspaceUserID); err != nil {
@@ -1,3 +1,32 @@
+DROP TABLE security_events;
+DROP TABLE account_unlocks;
+DROP TABLE login_failures;
+DROP TABLE oidc_logins;
+DROP TABLE two_factor_challenges;
+DROP TABLE recovery_codes;
+DROP TABLE two_factors;
+DROP TABLE api_tokens;
+DROP TABLE refresh_tokens;
+DROP TABLE revoked_tokens;
+DROP TABLE email_verifications;
+DROP TABLE password_resets;
+DROP TABLE session_revocations;
+DROP TABLE custom_field_values;
+DROP TABLE custom_fields;
+DROP VIEW project_members;
+DROP TABLE project_groups;
+DROP TABLE group_members;
+DROP TABLE milestone_tasks;
+DROP TABLE milestones;
+DROP TABLE task_dependencies;
+DROP TABLE template_members;
+DROP TABLE template_tasks;
+DROP TABLE project_templates;
+DROP TABLE export_formats;
+DROP TABLE account_codes;
+DROP TABLE client_contacts;
+DROP TABLE expenses;
+DROP TABLE cost_rates;
 DROP TABLE workspace_users;
 DROP TABLE teams;
 DROP TABLE tags;
@@ -155,4 +155,698 @@ CREATE TABLE workspace_users
     invite_key       text,
     date_created      timestamp,
     date_updated      timestamp
-);
\ No newline at end of file
+);
+
+-- Version: 1.01
+-- Description: Add rate to teams
+ALTER TABLE teams
+    ADD COLUMN rate double precision DEFAULT 0;
+
+-- Description: Create table cost_rates
+CREATE TABLE cost_rates
+(
+    cost_rate_id   uuid
+        constraint cost_rate_pk primary key,
+    wid            uuid,
+    uid            uuid,
+    rate           double precision,
+    effective_date timestamp,
+    date_created   timestamp,
+    date_updated   timestamp
+);
+
+-- Version: 1.02
+-- Description: Add budget to projects
+ALTER TABLE projects
+    ADD COLUMN budget double precision DEFAULT 0;
+
+-- Description: Create table expenses
+CREATE TABLE expenses
+(
+    expense_id   uuid
+        constraint expense_pk primary key,
+    wid          uuid,
+    pid          uuid,
+    tid          uuid,
+    uid          uuid,
+    description  text,
+    category     text,
+    amount       double precision,
+    currency     text,
+    date         timestamp,
+    billable     boolean,
+    invoiced     boolean,
+    receipt_url  text,
+    date_created timestamp,
+    date_updated timestamp
+);
+
+-- Version: 1.03
+-- Description: Add billing models to projects
+ALTER TABLE projects
+    ADD COLUMN billing_model  text             DEFAULT 'hourly',
+    ADD COLUMN fixed_fee      double precision DEFAULT 0,
+    ADD COLUMN retainer_fee   double precision DEFAULT 0,
+    ADD COLUMN retainer_hours double precision DEFAULT 0,
+    ADD COLUMN overage_rate   double precision DEFAULT 0,
+    ADD COLUMN rollover       boolean          DEFAULT false;
+
+-- Version: 1.04
+-- Description: Add billing details and archive state to clients
+ALTER TABLE clients
+    ADD COLUMN billing_address text             DEFAULT '',
+    ADD COLUMN tax_id          text             DEFAULT '',
+    ADD COLUMN currency        text             DEFAULT '',
+    ADD COLUMN rate            double precision DEFAULT 0,
+    ADD COLUMN payment_terms   integer          DEFAULT 0,
+    ADD COLUMN archived        boolean          DEFAULT false;
+
+-- Description: Add archive state to projects
+ALTER TABLE projects
+    ADD COLUMN archived boolean DEFAULT false;
+
+-- Description: Create table client_contacts
+CREATE TABLE client_contacts
+(
+    client_contact_id uuid
+        constraint client_contact_pk primary key,
+    cid               uuid,
+    name              text,
+    email             text,
+    phone             text,
+    is_primary        boolean,
+    date_created      timestamp,
+    date_updated      timestamp
+);
+
+-- Version: 1.05
+-- Description: Create table account_codes
+CREATE TABLE account_codes
+(
+    account_code_id uuid
+        constraint account_code_pk primary key,
+    wid             uuid,
+    pid             uuid,
+    cid             uuid,
+    code            text,
+    date_created    timestamp,
+    date_updated    timestamp,
+    constraint account_code_target_uq unique (wid, pid, cid)
+);
+
+-- Description: Create table export_formats
+CREATE TABLE export_formats
+(
+    export_format_id uuid
+        constraint export_format_pk primary key,
+    wid              uuid,
+    kind             text,
+    columns          text[],
+    headers          text[],
+    pay_period       text,
+    period_anchor    timestamp,
+    overtime_hours   double precision,
+    date_created     timestamp,
+    date_updated     timestamp,
+    constraint export_format_kind_uq unique (wid, kind)
+);
+
+-- Version: 1.06
+-- Description: Replace the project archive flag with lifecycle states
+ALTER TABLE projects
+    ADD COLUMN status text DEFAULT 'active';
+
+UPDATE projects
+SET status = CASE WHEN archived THEN 'archived' WHEN active THEN 'active' ELSE 'planned' END;
+
+ALTER TABLE projects
+    DROP COLUMN archived;
+
+-- Version: 1.07
+-- Description: Create table project_templates
+CREATE TABLE project_templates
+(
+    template_id     uuid
+        constraint project_template_pk primary key,
+    wid             uuid,
+    uid             uuid,
+    name            text,
+    is_private      boolean,
+    billable        boolean,
+    auto_estimates  boolean,
+    estimated_hours double precision,
+    rate            double precision,
+    hex_color       text,
+    budget          double precision,
+    billing_model   text,
+    fixed_fee       double precision,
+    retainer_fee    double precision,
+    retainer_hours  double precision,
+    overage_rate    double precision,
+    rollover        boolean,
+    date_created    timestamp,
+    date_updated    timestamp
+);
+
+-- Description: Create table template_tasks
+CREATE TABLE template_tasks
+(
+    template_task_id  uuid
+        constraint template_task_pk primary key,
+    template_id       uuid,
+    name              text,
+    estimated_seconds bigint
+);
+
+-- Description: Create table template_members
+CREATE TABLE template_members
+(
+    template_member_id uuid
+        constraint template_member_pk primary key,
+    template_id        uuid,
+    uid                uuid,
+    manager            boolean,
+    rate               double precision
+);
+
+-- Version: 1.08
+-- Description: Add task workflow to workspaces
+ALTER TABLE workspaces
+    ADD COLUMN task_statuses text[] DEFAULT '{todo,in_progress,in_review,done}';
+
+-- Description: Add assignees, status, due date and priority to tasks
+ALTER TABLE tasks
+    ADD COLUMN assignees text[]    DEFAULT '{}',
+    ADD COLUMN status    text      DEFAULT 'todo',
+    ADD COLUMN due_date  timestamp,
+    ADD COLUMN priority  integer   DEFAULT 0;
+
+-- Version: 1.09
+-- Description: Add parent task to tasks
+ALTER TABLE tasks
+    ADD COLUMN parent_id uuid DEFAULT '00000000-0000-0000-0000-000000000000';
+
+-- Description: Create table task_dependencies
+CREATE TABLE task_dependencies
+(
+    task_id      uuid,
+    blocked_by   uuid,
+    date_created timestamp,
+    constraint task_dependency_pk primary key (task_id, blocked_by)
+);
+
+-- Version: 1.10
+-- Description: Create table milestones
+CREATE TABLE milestones
+(
+    milestone_id uuid
+        constraint milestone_pk primary key,
+    pid          uuid,
+    wid          uuid,
+    name         text,
+    target_date  timestamp,
+    budget       double precision DEFAULT 0,
+    date_created timestamp,
+    date_updated timestamp
+);
+
+-- Description: Create table milestone_tasks
+CREATE TABLE milestone_tasks
+(
+    task_id      uuid
+        constraint milestone_task_pk primary key,
+    milestone_id uuid,
+    position     integer
+);
+
+-- Version: 1.11
+-- Description: Remove duplicated tags and tag names on time entries
+DELETE FROM tags a
+    USING tags b
+WHERE a.wid = b.wid
+  AND a.name = b.name
+  AND a.tag_id > b.tag_id;
+
+UPDATE time_entries
+SET tags = ARRAY(SELECT u.tag
+                 FROM unnest(tags) WITH ORDINALITY AS u(tag, n)
+                 GROUP BY u.tag
+                 ORDER BY MIN(u.n));
+
+-- Description: Enforce unique tag names per workspace
+ALTER TABLE tags
+    ADD CONSTRAINT tags_wid_name_unique UNIQUE (wid, name);
+
+-- Version: 1.12
+-- Description: Create table group_members
+CREATE TABLE group_members
+(
+    group_id     uuid,
+    uid          uuid,
+    date_created timestamp,
+    PRIMARY KEY (group_id, uid)
+);
+
+-- Description: Create table project_groups
+CREATE TABLE project_groups
+(
+    pid          uuid,
+    group_id     uuid,
+    wid          uuid,
+    manager      boolean,
+    rate         double precision DEFAULT 0,
+    date_created timestamp,
+    date_updated timestamp,
+    PRIMARY KEY (pid, group_id)
+);
+
+-- Description: Resolve the members of a project from its team and its groups
+CREATE VIEW project_members AS
+SELECT t.pid, t.uid, t.manager, t.rate, true AS direct
+FROM teams t
+UNION ALL
+SELECT pg.pid, gm.uid, pg.manager, pg.rate, false AS direct
+FROM project_groups pg
+         JOIN group_members gm ON gm.group_id = pg.group_id;
+
+-- Version: 1.13
+-- Description: Store missing references as NULL instead of the zero uuid
+ALTER TABLE tasks
+    ALTER COLUMN parent_id DROP DEFAULT;
+
+UPDATE time_entries SET pid = NULL WHERE pid = '00000000-0000-0000-0000-000000000000';
+UPDATE time_entries SET tid = NULL WHERE tid = '00000000-0000-0000-0000-000000000000';
+UPDATE tasks SET parent_id = NULL WHERE parent_id = '00000000-0000-0000-0000-000000000000';
+UPDATE projects SET cid = NULL WHERE cid = '00000000-0000-0000-0000-000000000000';
+UPDATE expenses SET tid = NULL WHERE tid = '00000000-0000-0000-0000-000000000000';
+UPDATE account_codes SET pid = NULL WHERE pid = '00000000-0000-0000-0000-000000000000';
+UPDATE account_codes SET cid = NULL WHERE cid = '00000000-0000-0000-0000-000000000000';
+
+-- Description: Drop optional references to entries that no longer exist
+UPDATE time_entries SET pid = NULL WHERE NOT EXISTS (SELECT 1 FROM projects WHERE project_id = time_entries.pid);
+UPDATE time_entries SET tid = NULL WHERE NOT EXISTS (SELECT 1 FROM tasks WHERE task_id = time_entries.tid);
+UPDATE tasks SET parent_id = NULL WHERE NOT EXISTS (SELECT 1 FROM tasks p WHERE p.task_id = tasks.parent_id);
+UPDATE projects SET cid = NULL WHERE NOT EXISTS (SELECT 1 FROM clients WHERE client_id = projects.cid);
+UPDATE expenses SET tid = NULL WHERE NOT EXISTS (SELECT 1 FROM tasks WHERE task_id = expenses.tid);
+
+-- Description: Keep one account code per project or client now that the other side is NULL
+ALTER TABLE account_codes
+    DROP CONSTRAINT account_code_target_uq;
+
+CREATE UNIQUE INDEX account_code_project_uq ON account_codes (wid, pid) WHERE cid IS NULL;
+CREATE UNIQUE INDEX account_code_client_uq ON account_codes (wid, cid) WHERE pid IS NULL;
+
+-- Description: Add foreign keys. Entries of a workspace go with it, memberships
+-- go with their user, project or group, and tracked time, expenses and the
+-- projects of a client keep their references from being deleted.
+ALTER TABLE workspaces
+    ADD CONSTRAINT workspace_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT;
+
+ALTER TABLE clients
+    ADD CONSTRAINT client_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT client_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT;
+
+ALTER TABLE projects
+    ADD CONSTRAINT project_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT project_cid_fk FOREIGN KEY (cid) REFERENCES clients (client_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT project_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT;
+
+ALTER TABLE tasks
+    ADD CONSTRAINT task_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE CASCADE,
+    ADD CONSTRAINT task_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT task_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT task_parent_id_fk FOREIGN KEY (parent_id) REFERENCES tasks (task_id) ON DELETE SET NULL;
+
+ALTER TABLE time_entries
+    ADD CONSTRAINT time_entry_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT time_entry_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT time_entry_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT time_entry_tid_fk FOREIGN KEY (tid) REFERENCES tasks (task_id) ON DELETE RESTRICT;
+
+ALTER TABLE groups
+    ADD CONSTRAINT group_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT group_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT;
+
+ALTER TABLE tags
+    ADD CONSTRAINT tag_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+ALTER TABLE teams
+    ADD CONSTRAINT team_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE CASCADE,
+    ADD CONSTRAINT team_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE,
+    ADD CONSTRAINT team_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+ALTER TABLE workspace_users
+    ADD CONSTRAINT workspace_user_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE,
+    ADD CONSTRAINT workspace_user_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+ALTER TABLE cost_rates
+    ADD CONSTRAINT cost_rate_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT cost_rate_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+ALTER TABLE expenses
+    ADD CONSTRAINT expense_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT expense_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT expense_tid_fk FOREIGN KEY (tid) REFERENCES tasks (task_id) ON DELETE RESTRICT,
+    ADD CONSTRAINT expense_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT;
+
+ALTER TABLE client_contacts
+    ADD CONSTRAINT client_contact_cid_fk FOREIGN KEY (cid) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+ALTER TABLE account_codes
+    ADD CONSTRAINT account_code_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT account_code_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE CASCADE,
+    ADD CONSTRAINT account_code_cid_fk FOREIGN KEY (cid) REFERENCES clients (client_id) ON DELETE CASCADE;
+
+ALTER TABLE export_formats
+    ADD CONSTRAINT export_format_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+ALTER TABLE project_templates
+    ADD CONSTRAINT project_template_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE,
+    ADD CONSTRAINT project_template_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE RESTRICT;
+
+ALTER TABLE template_tasks
+    ADD CONSTRAINT template_task_template_id_fk FOREIGN KEY (template_id) REFERENCES project_templates (template_id) ON DELETE CASCADE;
+
+ALTER TABLE template_members
+    ADD CONSTRAINT template_member_template_id_fk FOREIGN KEY (template_id) REFERENCES project_templates (template_id) ON DELETE CASCADE,
+    ADD CONSTRAINT template_member_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+ALTER TABLE task_dependencies
+    ADD CONSTRAINT task_dependency_task_id_fk FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE,
+    ADD CONSTRAINT task_dependency_blocked_by_fk FOREIGN KEY (blocked_by) REFERENCES tasks (task_id) ON DELETE CASCADE;
+
+ALTER TABLE milestones
+    ADD CONSTRAINT milestone_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE CASCADE,
+    ADD CONSTRAINT milestone_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+ALTER TABLE milestone_tasks
+    ADD CONSTRAINT milestone_task_task_id_fk FOREIGN KEY (task_id) REFERENCES tasks (task_id) ON DELETE CASCADE,
+    ADD CONSTRAINT milestone_task_milestone_id_fk FOREIGN KEY (milestone_id) REFERENCES milestones (milestone_id) ON DELETE CASCADE;
+
+ALTER TABLE group_members
+    ADD CONSTRAINT group_member_group_id_fk FOREIGN KEY (group_id) REFERENCES groups (group_id) ON DELETE CASCADE,
+    ADD CONSTRAINT group_member_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+ALTER TABLE project_groups
+    ADD CONSTRAINT project_group_pid_fk FOREIGN KEY (pid) REFERENCES projects (project_id) ON DELETE CASCADE,
+    ADD CONSTRAINT project_group_group_id_fk FOREIGN KEY (group_id) REFERENCES groups (group_id) ON DELETE CASCADE,
+    ADD CONSTRAINT project_group_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+-- Description: Index the reference columns
+CREATE INDEX workspace_uid_idx ON workspaces (uid);
+CREATE INDEX client_wid_idx ON clients (wid);
+CREATE INDEX client_uid_idx ON clients (uid);
+CREATE INDEX project_wid_idx ON projects (wid);
+CREATE INDEX project_cid_idx ON projects (cid);
+CREATE INDEX project_uid_idx ON projects (uid);
+CREATE INDEX task_pid_idx ON tasks (pid);
+CREATE INDEX task_wid_idx ON tasks (wid);
+CREATE INDEX task_uid_idx ON tasks (uid);
+CREATE INDEX task_parent_id_idx ON tasks (parent_id);
+CREATE INDEX time_entry_uid_idx ON time_entries (uid);
+CREATE INDEX time_entry_wid_idx ON time_entries (wid);
+CREATE INDEX time_entry_pid_idx ON time_entries (pid);
+CREATE INDEX time_entry_tid_idx ON time_entries (tid);
+CREATE INDEX group_wid_idx ON groups (wid);
+CREATE INDEX group_uid_idx ON groups (uid);
+CREATE INDEX team_pid_idx ON teams (pid);
+CREATE INDEX team_uid_idx ON teams (uid);
+CREATE INDEX team_wid_idx ON teams (wid);
+CREATE INDEX workspace_user_uid_idx ON workspace_users (uid);
+CREATE INDEX workspace_user_wid_idx ON workspace_users (wid);
+CREATE INDEX cost_rate_wid_idx ON cost_rates (wid);
+CREATE INDEX cost_rate_uid_idx ON cost_rates (uid);
+CREATE INDEX expense_wid_idx ON expenses (wid);
+CREATE INDEX expense_pid_idx ON expenses (pid);
+CREATE INDEX expense_tid_idx ON expenses (tid);
+CREATE INDEX expense_uid_idx ON expenses (uid);
+CREATE INDEX client_contact_cid_idx ON client_contacts (cid);
+CREATE INDEX account_code_pid_idx ON account_codes (pid);
+CREATE INDEX account_code_cid_idx ON account_codes (cid);
+CREATE INDEX project_template_wid_idx ON project_templates (wid);
+CREATE INDEX project_template_uid_idx ON project_templates (uid);
+CREATE INDEX template_task_template_id_idx ON template_tasks (template_id);
+CREATE INDEX template_member_template_id_idx ON template_members (template_id);
+CREATE INDEX template_member_uid_idx ON template_members (uid);
+CREATE INDEX task_dependency_blocked_by_idx ON task_dependencies (blocked_by);
+CREATE INDEX milestone_pid_idx ON milestones (pid);
+CREATE INDEX milestone_wid_idx ON milestones (wid);
+CREATE INDEX milestone_task_milestone_id_idx ON milestone_tasks (milestone_id);
+CREATE INDEX group_member_uid_idx ON group_members (uid);
+CREATE INDEX project_group_group_id_idx ON project_groups (group_id);
+CREATE INDEX project_group_wid_idx ON project_groups (wid);
+
+-- Version: 1.14
+-- Description: Create table custom_fields
+CREATE TABLE custom_fields
+(
+    field_id     uuid
+        constraint custom_field_pk primary key,
+    wid          uuid,
+    entity       text,
+    name         text,
+    kind         text,
+    options      text[]  DEFAULT '{}',
+    required     boolean DEFAULT false,
+    date_created timestamp,
+    date_updated timestamp,
+
+    constraint custom_field_name_uq unique (wid, entity, name)
+);
+
+-- Description: Create table custom_field_values
+CREATE TABLE custom_field_values
+(
+    field_id     uuid,
+    entity_id    uuid,
+    value        text[],
+    date_updated timestamp,
+    PRIMARY KEY (field_id, entity_id)
+);
+
+ALTER TABLE custom_fields
+    ADD CONSTRAINT custom_field_wid_fk FOREIGN KEY (wid) REFERENCES workspaces (workspace_id) ON DELETE CASCADE;
+
+ALTER TABLE custom_field_values
+    ADD CONSTRAINT custom_field_value_field_id_fk FOREIGN KEY (field_id) REFERENCES custom_fields (field_id) ON DELETE CASCADE;
+
+CREATE INDEX custom_field_value_entity_id_idx ON custom_field_values (entity_id);
+
+-- Version: 1.15
+-- Description: Create table password_resets
+CREATE TABLE password_resets
+(
+    token_hash   text
+        constraint password_reset_pk primary key,
+    uid          uuid,
+    date_expires timestamp,
+    date_created timestamp
+);
+
+-- Description: Create table session_revocations
+CREATE TABLE session_revocations
+(
+    uid          uuid
+        constraint session_revocation_pk primary key,
+    date_revoked timestamp
+);
+
+ALTER TABLE password_resets
+    ADD CONSTRAINT password_reset_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+ALTER TABLE session_revocations
+    ADD CONSTRAINT session_revocation_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX password_reset_uid_idx ON password_resets (uid);
+
+-- Version: 1.16
+-- Description: Add email verification
+ALTER TABLE users
+    ADD COLUMN verified boolean DEFAULT false;
+
+UPDATE users SET verified = true;
+
+ALTER TABLE workspaces
+    ADD COLUMN only_verified_members boolean DEFAULT false;
+
+-- Invitations add people without an account yet as workspace users, so their
+-- uid does not reference a user until they sign up.
+ALTER TABLE workspace_users
+    DROP CONSTRAINT workspace_user_uid_fk;
+
+-- Description: Create table email_verifications
+CREATE TABLE email_verifications
+(
+    token_hash   text
+        constraint email_verification_pk primary key,
+    uid          uuid,
+    email        text,
+    date_expires timestamp,
+    date_created timestamp
+);
+
+ALTER TABLE email_verifications
+    ADD CONSTRAINT email_verification_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX email_verification_uid_idx ON email_verifications (uid);
+
+-- Version: 1.17
+-- Description: Create table refresh_tokens
+CREATE TABLE refresh_tokens
+(
+    token_hash   text
+        constraint refresh_token_pk primary key,
+    uid          uuid,
+    session_id   uuid,
+    jti          uuid,
+    date_used    timestamp,
+    date_expires timestamp,
+    date_created timestamp
+);
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT refresh_token_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX refresh_token_uid_idx ON refresh_tokens (uid);
+CREATE INDEX refresh_token_session_id_idx ON refresh_tokens (session_id);
+CREATE INDEX refresh_token_jti_idx ON refresh_tokens (jti);
+
+-- Description: Create table revoked_tokens
+CREATE TABLE revoked_tokens
+(
+    jti          uuid
+        constraint revoked_token_pk primary key,
+    date_expires timestamp
+);
+
+-- Version: 1.18
+-- Description: Create table api_tokens
+CREATE TABLE api_tokens
+(
+    token_id       uuid
+        constraint api_token_pk primary key,
+    uid            uuid,
+    name           text,
+    token_hash     text UNIQUE,
+    scopes         text[],
+    date_expires   timestamp,
+    date_last_used timestamp,
+    date_created   timestamp
+);
+
+ALTER TABLE api_tokens
+    ADD CONSTRAINT api_token_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX api_token_uid_idx ON api_tokens (uid);
+
+-- Version: 1.19
+-- Description: Add two-factor authentication
+ALTER TABLE workspaces
+    ADD COLUMN require_two_factor boolean DEFAULT false;
+
+-- Description: Create table two_factors
+CREATE TABLE two_factors
+(
+    uid          uuid
+        constraint two_factor_pk primary key,
+    secret       text,
+    confirmed    boolean DEFAULT false,
+    last_step    bigint  DEFAULT 0,
+    date_created timestamp,
+    date_updated timestamp
+);
+
+ALTER TABLE two_factors
+    ADD CONSTRAINT two_factor_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+-- Description: Create table recovery_codes
+CREATE TABLE recovery_codes
+(
+    uid       uuid,
+    code_hash text,
+    PRIMARY KEY (uid, code_hash)
+);
+
+ALTER TABLE recovery_codes
+    ADD CONSTRAINT recovery_code_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+-- Description: Create table two_factor_challenges
+CREATE TABLE two_factor_challenges
+(
+    token_hash   text
+        constraint two_factor_challenge_pk primary key,
+    uid          uuid,
+    date_expires timestamp,
+    date_created timestamp
+);
+
+ALTER TABLE two_factor_challenges
+    ADD CONSTRAINT two_factor_challenge_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX two_factor_challenge_uid_idx ON two_factor_challenges (uid);
+
+-- Version: 1.20
+-- Description: Create table oidc_logins
+CREATE TABLE oidc_logins
+(
+    state_hash   text
+        constraint oidc_login_pk primary key,
+    nonce        text,
+    verifier     text,
+    date_expires timestamp,
+    date_created timestamp
+);
+
+-- Version: 1.21
+-- Description: Create table login_failures
+CREATE TABLE login_failures
+(
+    kind              text,
+    subject           text,
+    failures          int DEFAULT 0,
+    date_locked_until timestamp,
+    date_last_failed  timestamp,
+    PRIMARY KEY (kind, subject)
+);
+
+-- Description: Create table account_unlocks
+CREATE TABLE account_unlocks
+(
+    token_hash   text
+        constraint account_unlock_pk primary key,
+    uid          uuid,
+    date_expires timestamp,
+    date_created timestamp
+);
+
+ALTER TABLE account_unlocks
+    ADD CONSTRAINT account_unlock_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX account_unlock_uid_idx ON account_unlocks (uid);
+
+-- Description: Create table security_events
+CREATE TABLE security_events
+(
+    event_id     uuid
+        constraint security_event_pk primary key,
+    uid          uuid,
+    email        text,
+    ip           text,
+    type         text,
+    date_created timestamp
+);
+
+ALTER TABLE security_events
+    ADD CONSTRAINT security_event_uid_fk FOREIGN KEY (uid) REFERENCES users (user_id) ON DELETE CASCADE;
+
+CREATE INDEX security_event_uid_idx ON security_events (uid, date_created);
+
+-- Version: 1.22
+-- Description: Add attempts to two_factor_challenges
+ALTER TABLE two_factor_challenges
+    ADD COLUMN attempts int DEFAULT 0;
+
+-- Version: 1.23
+-- Description: Add previous_status to projects archived with their client
+ALTER TABLE projects
+    ADD COLUMN previous_status text;
@@ -75,4 +75,37 @@ values ('32c1494f-1c1f-4981-857f-b0526cb654ec', '5cf37266-3473-4006-984f-9325122
         '7da3ca14-6366-47cf-b953-f706226567d8', 'true', 'false', '', '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
        ('604125e7-f368-4ff0-8170-dfd2f428510a', '5cf37266-3473-4006-984f-9325122678b7',
         '7da3ca14-6366-47cf-b953-f706226567d8', 'false', 'true', '', '2019-03-24 00:00:00', '2019-03-24 00:00:00')
-ON CONFLICT DO NOTHING;
\ No newline at end of file
+ON CONFLICT DO NOTHING;
+INSERT INTO cost_rates (cost_rate_id, wid, uid, rate, effective_date, date_created, date_updated)
+values ('b1a6c3c0-2f4e-4d0b-9a55-5f3f2f6a9c11', '7da3ca14-6366-47cf-b953-f706226567d8',
+        '5cf37266-3473-4006-984f-9325122678b7', '20.0', '2019-01-01 00:00:00', '2019-03-24 00:00:00',
+        '2019-03-24 00:00:00'),
+       ('0e9f5d1e-8d8e-4f57-b0a4-3c1b4b7d2e42', '7da3ca14-6366-47cf-b953-f706226567d8',
+        '45b5fbd3-755f-4379-8f07-a58d4a30fa2f', '15.0', '2019-01-01 00:00:00', '2019-03-24 00:00:00',
+        '2019-03-24 00:00:00')
+ON CONFLICT DO NOTHING;
+INSERT INTO expenses (expense_id, wid, pid, tid, uid, description, category, amount, currency, date, billable, invoiced,
+                      receipt_url, date_created, date_updated)
+values ('d4a1c5a8-7b8e-4f0e-9f3c-2a6b1e5c7d90', '7da3ca14-6366-47cf-b953-f706226567d8',
+        '45cf87a3-5915-4079-a9af-6c559239ddbf', NULL,
+        '5cf37266-3473-4006-984f-9325122678b7', 'Default Expense', 'travel', '120.0', 'USD', '2019-03-24 00:00:00',
+        'true', 'false', '', '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
+       ('8f2b6e3d-1c4a-4b7e-a5d9-6e0f3b2c1a47', '7da3ca14-6366-47cf-b953-f706226567d8',
+        '45cf87a3-5915-4079-a9af-6c559239ddbf', '346efd40-6d6e-46d5-b60b-5db9fc171779',
+        '45b5fbd3-755f-4379-8f07-a58d4a30fa2f', 'User Expense', 'meals', '30.0', 'USD', '2019-03-24 00:00:00',
+        'false', 'false', '', '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO client_contacts (client_contact_id, cid, name, email, phone, is_primary, date_created, date_updated)
+VALUES ('0b4c9e7a-6d2f-4f0e-8f3b-5a1d2c7e9b40', 'c78db68e-e004-44f5-895b-ba562dc53d9d', 'Billing Contact',
+        'billing@example.com', '', 'true', '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO account_codes (account_code_id, wid, pid, cid, code, date_created, date_updated)
+VALUES ('6a3e1f7c-2b9d-4c5e-8a0f-4d7b1e3c9f52', '7da3ca14-6366-47cf-b953-f706226567d8',
+        NULL, 'c78db68e-e004-44f5-895b-ba562dc53d9d', '4000',
+        '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
+       ('9c5d2a8e-7f1b-4e3a-b6c4-0e2f8d5a1b73', '7da3ca14-6366-47cf-b953-f706226567d8',
+        '45cf87a3-5915-4079-a9af-6c559239ddbf', NULL, '4100',
+        '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+ON CONFLICT DO NOTHING;
@@ -1,4 +1,32 @@
 TRUNCATE
+    security_events,
+    account_unlocks,
+    login_failures,
+    oidc_logins,
+    two_factor_challenges,
+    recovery_codes,
+    two_factors,
+    api_tokens,
+    refresh_tokens,
+    revoked_tokens,
+    email_verifications,
+    password_resets,
+    session_revocations,
+    custom_field_values,
+    custom_fields,
+    project_groups,
+    group_members,
+    milestone_tasks,
+    milestones,
+    task_dependencies,
+    template_members,
+    template_tasks,
+    project_templates,
+    export_formats,
+    account_codes,
+    client_contacts,
+    expenses,
+    cost_rates,
     workspace_users,
     teams,
     tags,
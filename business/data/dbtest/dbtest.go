@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
@@ -147,7 +148,7 @@ func NewIntegration(t *testing.T, c *docker.Container, dbName string) *Test {
 	}
 
 	// Build an authenticator using this private key and id for the key store.
-	authN, err := auth.New(keyID, keystore.NewMap(map[string]*rsa.PrivateKey{keyID: privateKey}))
+	authN, err := auth.New(keyID, keystore.NewMap(map[string]crypto.PrivateKey{keyID: privateKey}))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -217,3 +218,10 @@ func BoolPointer(b bool) *bool {
 func TimePointer(t time.Time) *time.Time {
 	return &t
 }
+
+// Float64Pointer is a helper to get a *float64 from a float64. It is in the tests
+// package because we normally don't want to deal with pointers to basic types,
+// but it's useful in some tests.
+func Float64Pointer(f float64) *float64 {
+	return &f
+}
@@ -2,9 +2,15 @@
 package auth
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
@@ -12,19 +18,31 @@ import (
 // ErrForbidden is returned when a auth issue is identified.
 var ErrForbidden = errors.New("attempted action is not allowed")
 
+// TokenTTL is the lifetime of the access tokens. A retired key keeps
+// validating tokens for that long, every token it signed has expired by then.
+const TokenTTL = time.Hour
+
 // KeyLookup declares a method set of behavior for looking up
-// private and public keys for JWT use.
+// private and public keys for JWT use. RSA, ECDSA P-256 and Ed25519 keys
+// are supported.
 type KeyLookup interface {
-	PrivateKey(kid string) (*rsa.PrivateKey, error)
-	PublicKey(kid string) (*rsa.PublicKey, error)
+	PrivateKey(kid string) (crypto.PrivateKey, error)
+	PublicKey(kid string) (crypto.PublicKey, error)
+}
+
+// KeyLister declares the behavior of a key store that can list the ids of
+// the keys it holds, which is needed to publish all the public keys.
+type KeyLister interface {
+	KIDs() []string
 }
 
 // Auth is used to authenticate clients. It can generate a token for a
 // set of user claims and recreate the claims by parsing the token.
 type Auth struct {
+	mu        sync.RWMutex
 	activeKID string
+	retired   map[string]time.Time
 	keyLookup KeyLookup
-	method    jwt.SigningMethod
 	keyFunc   func(t *jwt.Token) (interface{}, error)
 	parser    *jwt.Parser
 }
@@ -33,17 +51,19 @@ type Auth struct {
 func New(activeKID string, keyLookup KeyLookup) (*Auth, error) {
 
 	// The activeKID represents the private key used to signed new tokens.
-	_, err := keyLookup.PrivateKey(activeKID)
-	if err != nil {
-		return nil, errors.New("active KID does not exist in store")
+	if _, err := lookupSigningKey(keyLookup, activeKID); err != nil {
+		return nil, err
 	}
 
-	method := jwt.GetSigningMethod("RS256")
-	if method == nil {
-		return nil, errors.New("configuring algorithm RS256")
+	a := Auth{
+		activeKID: activeKID,
+		keyLookup: keyLookup,
 	}
 
-	keyFunc := func(t *jwt.Token) (interface{}, error) {
+	// The algorithm of a token is decided by the type of the key of its kid,
+	// not by the token itself. A token claiming any other algorithm than the
+	// one of its key is rejected to avoid algorithm confusion.
+	a.keyFunc = func(t *jwt.Token) (interface{}, error) {
 		kid, ok := t.Header["kid"]
 		if !ok {
 			return nil, errors.New("missing key id (kid) in token header")
@@ -52,36 +72,98 @@ func New(activeKID string, keyLookup KeyLookup) (*Auth, error) {
 		if !ok {
 			return nil, errors.New("user token key id (kid) must be string")
 		}
-		return keyLookup.PublicKey(kidID)
+
+		publicKey, err := keyLookup.PublicKey(kidID)
+		if err != nil {
+			return nil, err
+		}
+
+		if a.expired(kidID, time.Now()) {
+			return nil, fmt.Errorf("key (kid) %q was retired", kidID)
+		}
+
+		method, err := SigningMethod(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != method.Alg() {
+			return nil, fmt.Errorf("algorithm %q does not match the key (kid) %q", t.Method.Alg(), kidID)
+		}
+
+		return publicKey, nil
 	}
 
 	// Create the token parser to use. The algorithm used to sign the JWT must be
 	// validated to avoid a critical vulnerability:
 	// https://auth0.com/blog/critical-vulnerabilities-in-json-web-token-libraries/
-	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256"}))
+	a.parser = jwt.NewParser(jwt.WithValidMethods([]string{
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodES256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}))
 
-	a := Auth{
-		activeKID: activeKID,
-		keyLookup: keyLookup,
-		method:    method,
-		keyFunc:   keyFunc,
-		parser:    parser,
+	return &a, nil
+}
+
+// SigningMethod returns the method tokens are signed with for the type of
+// the public key: RS256 for RSA, ES256 for ECDSA P-256 and EdDSA for Ed25519
+// keys.
+func SigningMethod(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("curve %s is not supported", key.Curve.Params().Name)
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("key type %T is not supported", publicKey)
 	}
+}
 
-	return &a, nil
+// signingKey is a private key along with the method it signs tokens with.
+type signingKey struct {
+	privateKey crypto.PrivateKey
+	method     jwt.SigningMethod
+}
+
+// lookupSigningKey looks up the private key of the kid along with the
+// method to sign tokens with it.
+func lookupSigningKey(keyLookup KeyLookup, kid string) (signingKey, error) {
+	privateKey, err := keyLookup.PrivateKey(kid)
+	if err != nil {
+		return signingKey{}, errors.New("active KID does not exist in store")
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return signingKey{}, fmt.Errorf("key type %T is not supported", privateKey)
+	}
+
+	method, err := SigningMethod(signer.Public())
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	return signingKey{privateKey: privateKey, method: method}, nil
 }
 
 // GenerateToken generates a signed JWT token string representing the user Claims.
 func (a *Auth) GenerateToken(claims Claims) (string, error) {
-	token := jwt.NewWithClaims(a.method, claims)
-	token.Header["kid"] = a.activeKID
+	activeKID := a.ActiveKID()
 
-	privateKey, err := a.keyLookup.PrivateKey(a.activeKID)
+	key, err := lookupSigningKey(a.keyLookup, activeKID)
 	if err != nil {
-		return "", errors.New("kid lookup failed")
+		return "", fmt.Errorf("kid lookup failed: %w", err)
 	}
 
-	str, err := token.SignedString(privateKey)
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = activeKID
+
+	str, err := token.SignedString(key.privateKey)
 	if err != nil {
 		return "", fmt.Errorf("signing token: %w", err)
 	}
@@ -104,3 +186,77 @@ func (a *Auth) ValidateToken(tokenStr string) (Claims, error) {
 
 	return claims, nil
 }
+
+// ActiveKID returns the id of the key new tokens are signed with.
+func (a *Auth) ActiveKID() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.activeKID
+}
+
+// SetActiveKID promotes the key to sign new tokens with. Tokens signed with
+// the previous key keep validating as long as it stays in the key store, or
+// until TokenTTL passed since it was retired.
+func (a *Auth) SetActiveKID(kid string) error {
+	if _, err := lookupSigningKey(a.keyLookup, kid); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.activeKID = kid
+	return nil
+}
+
+// SetRetired records when keys were retired from signing tokens by their
+// kid. A retired key stops validating tokens once TokenTTL passed since, even
+// when it is still in the key store.
+func (a *Auth) SetRetired(retired map[string]time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.retired = retired
+}
+
+// expired reports whether the key was retired longer than TokenTTL ago. The
+// active key never is, it may have been promoted again.
+func (a *Auth) expired(kid string, now time.Time) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if kid == a.activeKID {
+		return false
+	}
+
+	retired, ok := a.retired[kid]
+	return ok && !now.Before(retired.Add(TokenTTL))
+}
+
+// PublicKeys returns the public keys tokens can be validated with by their
+// kid. Only the active key is returned when the key store can't list its
+// keys. Retired keys are left out once they expired.
+func (a *Auth) PublicKeys() map[string]crypto.PublicKey {
+	kids := []string{a.ActiveKID()}
+	if kl, ok := a.keyLookup.(KeyLister); ok {
+		kids = kl.KIDs()
+	}
+
+	now := time.Now()
+	keys := make(map[string]crypto.PublicKey, len(kids))
+	for _, kid := range kids {
+
+		// A key can be removed by a reload of the store in the meantime.
+		publicKey, err := a.keyLookup.PublicKey(kid)
+		if err != nil {
+			continue
+		}
+		if a.expired(kid, now) {
+			continue
+		}
+		keys[kid] = publicKey
+	}
+
+	return keys
+}
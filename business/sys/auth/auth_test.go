@@ -1,12 +1,19 @@
 package auth_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/foundation/keystore"
 	"github.com/golang-jwt/jwt/v4"
 )
 
@@ -74,16 +81,268 @@ func TestAuth(t *testing.T) {
 	}
 }
 
+func TestRotate(t *testing.T) {
+	t.Log("Given the need to rotate the key tokens are signed with.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen promoting a new key.", testID)
+		{
+			oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create a private key: %v", failed, testID, err)
+			}
+			newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create a private key: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create the private keys.", success, testID)
+
+			ks := keystore.NewMap(map[string]crypto.PrivateKey{"old": oldKey})
+
+			a, err := auth.New("old", ks)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create an authenticator: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create an authenticator.", success, testID)
+
+			claims := auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    "Wakt project",
+					Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+					ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+				},
+			}
+
+			oldToken, err := a.GenerateToken(claims)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to generate a JWT: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to generate a JWT.", success, testID)
+
+			if err := a.SetActiveKID("new"); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to promote a missing key.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to promote a missing key.", success, testID)
+
+			ks.Add(newKey, "new")
+			if err := a.SetActiveKID("new"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to promote the new key: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to promote the new key.", success, testID)
+
+			if got := len(a.PublicKeys()); got != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould publish both keys: got %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould publish both keys.", success, testID)
+
+			newToken, err := a.GenerateToken(claims)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to generate a JWT: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to generate a JWT.", success, testID)
+
+			if _, err := a.ValidateToken(oldToken); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould validate a token of the old key: %v", failed, testID, err)
+			}
+			if _, err := a.ValidateToken(newToken); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould validate a token of the new key: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould validate tokens of both keys.", success, testID)
+
+			a.SetRetired(map[string]time.Time{"old": time.Now().Add(-time.Minute)})
+			if _, err := a.ValidateToken(oldToken); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould validate a token of a recently retired key: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould validate a token of a recently retired key.", success, testID)
+
+			a.SetRetired(map[string]time.Time{"old": time.Now().Add(-auth.TokenTTL)})
+			if _, err := a.ValidateToken(oldToken); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not validate a token of an expired retired key.", failed, testID)
+			}
+			if got := len(a.PublicKeys()); got != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould only publish the new key: got %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould stop using the old key once its tokens expired.", success, testID)
+
+			a.SetRetired(nil)
+			ks.Remove("old")
+			if _, err := a.ValidateToken(oldToken); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not validate a token of a removed key.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not validate a token of a removed key.", success, testID)
+		}
+	}
+}
+
+func TestKeyTypes(t *testing.T) {
+	t.Log("Given the need to sign tokens with RSA, ECDSA and Ed25519 keys.")
+	{
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("\t%s\tShould be able to create an RSA key: %v", failed, err)
+		}
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("\t%s\tShould be able to create an ECDSA key: %v", failed, err)
+		}
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("\t%s\tShould be able to create an Ed25519 key: %v", failed, err)
+		}
+		t.Logf("\t%s\tShould be able to create the private keys.", success)
+
+		ks := keystore.NewMap(map[string]crypto.PrivateKey{
+			"rsa":     rsaKey,
+			"ecdsa":   ecKey,
+			"ed25519": edKey,
+		})
+
+		claims := auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "Wakt project",
+				Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			},
+		}
+
+		tests := []struct {
+			kid string
+			alg string
+		}{
+			{"rsa", "RS256"},
+			{"ecdsa", "ES256"},
+			{"ed25519", "EdDSA"},
+		}
+
+		for testID, tt := range tests {
+			t.Logf("\tTest %d:\tWhen signing with the %s key.", testID, tt.kid)
+			{
+				a, err := auth.New(tt.kid, ks)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to create an authenticator: %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to create an authenticator.", success, testID)
+
+				token, err := a.GenerateToken(claims)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to generate a JWT: %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to generate a JWT.", success, testID)
+
+				parsed, _, err := jwt.NewParser().ParseUnverified(token, &auth.Claims{})
+				if err != nil || parsed.Method.Alg() != tt.alg {
+					t.Fatalf("\t%s\tTest %d:\tShould be signed with %s: %v", failed, testID, tt.alg, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be signed with %s.", success, testID, tt.alg)
+
+				if _, err := a.ValidateToken(token); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to validate the JWT: %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to validate the JWT.", success, testID)
+			}
+		}
+
+		testID := len(tests)
+		t.Logf("\tTest %d:\tWhen a token claims the algorithm of another key.", testID)
+		{
+			a, err := auth.New("rsa", ks)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create an authenticator: %v", failed, testID, err)
+			}
+
+			// Signed with the ECDSA key but pointing at the RSA key.
+			token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+			token.Header["kid"] = "rsa"
+			str, err := token.SignedString(ecKey)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to sign the JWT: %v", failed, testID, err)
+			}
+
+			if _, err := a.ValidateToken(str); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject a mismatched algorithm.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject a mismatched algorithm.", success, testID)
+
+			// Signed with HMAC using the public key as the secret.
+			token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+			token.Header["kid"] = "ed25519"
+			str, err = token.SignedString([]byte(edKey.Public().(ed25519.PublicKey)))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to sign the JWT: %v", failed, testID, err)
+			}
+
+			if _, err := a.ValidateToken(str); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject an HMAC signed token.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject an HMAC signed token.", success, testID)
+		}
+	}
+}
+
 // =============================================================================
 
 type keyStore struct {
 	pk *rsa.PrivateKey
 }
 
-func (ks *keyStore) PrivateKey(kid string) (*rsa.PrivateKey, error) {
+func (ks *keyStore) PrivateKey(kid string) (crypto.PrivateKey, error) {
 	return ks.pk, nil
 }
 
-func (ks *keyStore) PublicKey(kid string) (*rsa.PublicKey, error) {
+func (ks *keyStore) PublicKey(kid string) (crypto.PublicKey, error) {
 	return &ks.pk.PublicKey, nil
 }
+
+func TestJWK(t *testing.T) {
+	t.Log("Given the need to publish keys of different types.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen converting keys to and from JSON Web Keys.", testID)
+		{
+			ks, err := keystore.NewFS(os.DirFS("../../../foundation/keystore"))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the keys : %s.", failed, testID, err)
+			}
+			rsaKey, err := ks.PublicKey("test")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to find the RSA key : %s.", failed, testID, err)
+			}
+
+			ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create an ECDSA key : %s.", failed, testID, err)
+			}
+			edKey, _, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create an Ed25519 key : %s.", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create the keys.", success, testID)
+
+			keys := map[string]crypto.PublicKey{
+				"RS256": rsaKey,
+				"ES256": &ecKey.PublicKey,
+				"EdDSA": edKey,
+			}
+			for alg, key := range keys {
+				jwk, err := auth.NewJWK(alg, key)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to convert the %s key : %s.", failed, testID, alg, err)
+				}
+				if jwk.Alg != alg {
+					t.Fatalf("\t%s\tTest %d:\tShould have algorithm %s : got %s.", failed, testID, alg, jwk.Alg)
+				}
+
+				got, err := jwk.PublicKey()
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to convert the %s key back : %s.", failed, testID, alg, err)
+				}
+				if !reflect.DeepEqual(got, key) {
+					t.Fatalf("\t%s\tTest %d:\tShould get the same %s key back.", failed, testID, alg)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the same %s key back.", success, testID, alg)
+			}
+		}
+	}
+}
@@ -18,12 +18,17 @@ import (
 
 // lib/pq errorCodeNames
 // https://github.com/lib/pq/blob/master/error.go#L178
-const uniqueViolation = "23505"
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
 
 // Set of error variables for CRUD operations.
 var (
-	ErrDBNotFound        = errors.New("not found")
-	ErrDBDuplicatedEntry = errors.New("duplicated entry")
+	ErrDBNotFound         = errors.New("not found")
+	ErrDBDuplicatedEntry  = errors.New("duplicated entry")
+	ErrDBMissingReference = errors.New("referenced entry not found")
+	ErrDBReferenced       = errors.New("entry is still referenced")
 )
 
 // Config is the required properties to use the database.
@@ -130,9 +135,10 @@ func WithinTran(ctx context.Context, log *zap.SugaredLogger, db Transactor, fn f
 	// fails, return the error and the defer function will roll back.
 	if err := fn(tx); err != nil {
 
-		// Checks if the error is of code 23505 (unique_violation).
-		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code == uniqueViolation {
-			return ErrDBDuplicatedEntry
+		if pqerr, ok := err.(*pq.Error); ok {
+			if err := toDBError(pqerr); err != nil {
+				return err
+			}
 		}
 		return fmt.Errorf("exec tran: %w", err)
 	}
@@ -157,9 +163,10 @@ func NamedExecContext(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtCo
 
 	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
 
-		// Checks if the error is of code 23505 (unique_violation).
-		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code == uniqueViolation {
-			return ErrDBDuplicatedEntry
+		if pqerr, ok := err.(*pq.Error); ok {
+			if err := toDBError(pqerr); err != nil {
+				return err
+			}
 		}
 		return err
 	}
@@ -167,6 +174,22 @@ func NamedExecContext(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtCo
 	return nil
 }
 
+// toDBError translates the constraint violations the business layer knows
+// how to handle. A foreign key violation is raised either when a row points
+// to a missing entry or when an entry that is still pointed to is removed.
+func toDBError(pqerr *pq.Error) error {
+	switch pqerr.Code {
+	case uniqueViolation:
+		return ErrDBDuplicatedEntry
+	case foreignKeyViolation:
+		if strings.HasPrefix(pqerr.Message, "update or delete") {
+			return ErrDBReferenced
+		}
+		return ErrDBMissingReference
+	}
+	return nil
+}
+
 // NamedQuerySlice is a helper function for executing queries that return a
 // collection of data to be unmarshalled into a slice.
 func NamedQuerySlice(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data interface{}, dest interface{}) error {
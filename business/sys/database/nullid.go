@@ -0,0 +1,38 @@
+package database
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// NoID is the reference used by the business layer for an optional relation
+// that is not set.
+const NoID = "00000000-0000-0000-0000-000000000000"
+
+// NullID is an optional reference to another entry. The business layer uses
+// NoID for a missing reference, which is stored as NULL so the column can
+// carry a foreign key.
+type NullID string
+
+// Scan implements the sql.Scanner interface.
+func (id *NullID) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*id = NoID
+	case string:
+		*id = NullID(v)
+	case []byte:
+		*id = NullID(v)
+	default:
+		return fmt.Errorf("unsupported type %T for an id", value)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (id NullID) Value() (driver.Value, error) {
+	if id == "" || id == NoID {
+		return nil, nil
+	}
+	return string(id), nil
+}
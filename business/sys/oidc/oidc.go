@@ -0,0 +1,255 @@
+// Package oidc provides support for signing in with an OpenID Connect
+// provider using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrInvalidIDToken is returned when the ID token of a provider can't be
+// trusted.
+var ErrInvalidIDToken = errors.New("id token is not valid")
+
+// Provider represents the settings of an OpenID Connect provider. Users can
+// only sign in with an email of one of the allowed domains, any domain is
+// allowed when none is set.
+type Provider struct {
+	Issuer         string
+	ClientID       string
+	ClientSecret   string
+	RedirectURL    string
+	AllowedDomains []string
+}
+
+// Endpoints represents the endpoints of a provider found by discovery.
+type Endpoints struct {
+	Issuer   string `json:"issuer"`
+	AuthURL  string `json:"authorization_endpoint"`
+	TokenURL string `json:"token_endpoint"`
+	JWKSURL  string `json:"jwks_uri"`
+}
+
+// Claims represents the claims of an ID token used to link a user.
+type Claims struct {
+	jwt.RegisteredClaims
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Nonce         string `json:"nonce"`
+}
+
+// Client talks to the provider on behalf of wakt.
+type Client struct {
+	provider Provider
+	http     *http.Client
+	parser   *jwt.Parser
+}
+
+// NewClient constructs a client for the provider.
+func NewClient(provider Provider) *Client {
+	return &Client{
+		provider: provider,
+		http:     &http.Client{Timeout: 10 * time.Second},
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodES256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+		})),
+	}
+}
+
+// Discover looks up the endpoints of the provider.
+func (c *Client) Discover(ctx context.Context) (Endpoints, error) {
+	var endpoints Endpoints
+	if err := c.get(ctx, strings.TrimSuffix(c.provider.Issuer, "/")+"/.well-known/openid-configuration", &endpoints); err != nil {
+		return Endpoints{}, fmt.Errorf("discovery: %w", err)
+	}
+
+	if endpoints.Issuer != c.provider.Issuer {
+		return Endpoints{}, fmt.Errorf("discovery: issuer %q does not match %q", endpoints.Issuer, c.provider.Issuer)
+	}
+
+	return endpoints, nil
+}
+
+// AuthCodeURL returns the URL to send the user to for signing in with the
+// provider.
+func (c *Client) AuthCodeURL(endpoints Endpoints, state, nonce, verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", c.provider.ClientID)
+	v.Set("redirect_uri", c.provider.RedirectURL)
+	v.Set("scope", "openid email")
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(sum[:]))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(endpoints.AuthURL, "?") {
+		sep = "&"
+	}
+	return endpoints.AuthURL + sep + v.Encode()
+}
+
+// Exchange trades the authorization code for the ID token of the user and
+// returns its claims once it is verified.
+func (c *Client) Exchange(ctx context.Context, endpoints Endpoints, code, verifier, nonce string) (Claims, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.provider.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fmt.Errorf("exchange: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.provider.ClientID), url.QueryEscape(c.provider.ClientSecret))
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := c.do(req, &token); err != nil {
+		return Claims{}, fmt.Errorf("exchange: %w", err)
+	}
+
+	if token.IDToken == "" {
+		return Claims{}, ErrInvalidIDToken
+	}
+
+	return c.Verify(ctx, endpoints, token.IDToken, nonce)
+}
+
+// Verify checks the ID token is signed by the provider for this client and
+// belongs to the login with the nonce.
+func (c *Client) Verify(ctx context.Context, endpoints Endpoints, idToken, nonce string) (Claims, error) {
+	var jwks auth.JWKS
+	if err := c.get(ctx, endpoints.JWKSURL, &jwks); err != nil {
+		return Claims{}, fmt.Errorf("jwks: %w", err)
+	}
+
+	// The algorithm must match the type of the key of the kid to avoid
+	// algorithm confusion.
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, jwk := range jwks.Keys {
+			if jwk.Kid != kid {
+				continue
+			}
+
+			key, err := jwk.PublicKey()
+			if err != nil {
+				return nil, err
+			}
+
+			method, err := auth.SigningMethod(key)
+			if err != nil {
+				return nil, err
+			}
+			if t.Method.Alg() != method.Alg() {
+				return nil, fmt.Errorf("algorithm %q does not match the key (kid) %q", t.Method.Alg(), kid)
+			}
+
+			return key, nil
+		}
+		return nil, fmt.Errorf("key id (kid) %q not found", kid)
+	}
+
+	var claims Claims
+	if _, err := c.parser.ParseWithClaims(idToken, &claims, keyFunc); err != nil {
+		return Claims{}, fmt.Errorf("%w: %s", ErrInvalidIDToken, err)
+	}
+
+	switch {
+	case claims.Issuer != c.provider.Issuer:
+		return Claims{}, fmt.Errorf("%w: issuer %q", ErrInvalidIDToken, claims.Issuer)
+	case !claims.VerifyAudience(c.provider.ClientID, true):
+		return Claims{}, fmt.Errorf("%w: audience %v", ErrInvalidIDToken, claims.Audience)
+	case claims.ExpiresAt == nil:
+		return Claims{}, fmt.Errorf("%w: missing expiry", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return Claims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// AllowedEmail reports whether the email is of one of the allowed domains.
+func (c *Client) AllowedEmail(email string) bool {
+	if len(c.provider.AllowedDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range c.provider.AllowedDomains {
+		if domain == strings.ToLower(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// RandomString returns a random URL safe string, used for the state, nonce
+// and PKCE verifier of a login.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// get fetches a JSON document.
+func (c *Client) get(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, v)
+}
+
+// do sends the request and decodes its JSON response.
+func (c *Client) do(req *http.Request, v interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Redacted(), resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
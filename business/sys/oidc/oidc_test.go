@@ -0,0 +1,93 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
+	"github.com/AhmedShaef/wakt/business/sys/oidc/oidctest"
+	"github.com/AhmedShaef/wakt/foundation/keystore"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestOIDC(t *testing.T) {
+	t.Log("Given the need to sign in with an OpenID Connect provider.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling an authorization code with PKCE.", testID)
+		{
+			ctx := context.Background()
+
+			ks, err := keystore.NewFS(os.DirFS("../../../foundation/keystore"))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the keys : %s.", failed, testID, err)
+			}
+
+			idp, err := oidctest.New(ks, "test", "wakt", "s3cr3t")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to start the provider : %s.", failed, testID, err)
+			}
+			defer idp.Close()
+			t.Logf("\t%s\tTest %d:\tShould be able to start the provider.", success, testID)
+
+			client := oidc.NewClient(idp.Provider("http://localhost:3000/v1/oidc/callback", "example.com"))
+
+			endpoints, err := client.Discover(ctx)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to discover the endpoints : %s.", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to discover the endpoints.", success, testID)
+
+			nonce, _ := oidc.RandomString()
+			verifier, _ := oidc.RandomString()
+			authURL := client.AuthCodeURL(endpoints, "state", nonce, verifier)
+
+			code, state, err := idp.Authorize(authURL, "user@example.com", true)
+			if err != nil || state != "state" {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authorize : %v.", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to authorize.", success, testID)
+
+			if _, err := client.Exchange(ctx, endpoints, code, "wrong-verifier", nonce); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not exchange the code without its verifier.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not exchange the code without its verifier.", success, testID)
+
+			code, _, err = idp.Authorize(authURL, "user@example.com", true)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authorize : %v.", failed, testID, err)
+			}
+
+			if _, err := client.Exchange(ctx, endpoints, code, verifier, "other-nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept the ID token of another login : %v.", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept the ID token of another login.", success, testID)
+
+			code, _, err = idp.Authorize(authURL, "user@example.com", true)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to authorize : %v.", failed, testID, err)
+			}
+
+			claims, err := client.Exchange(ctx, endpoints, code, verifier, nonce)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to exchange the code : %s.", failed, testID, err)
+			}
+			if claims.Email != "user@example.com" || !claims.EmailVerified {
+				t.Fatalf("\t%s\tTest %d:\tShould get the email of the user : %+v.", failed, testID, claims)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to exchange the code.", success, testID)
+
+			if !client.AllowedEmail("user@example.com") || client.AllowedEmail("user@other.com") {
+				t.Fatalf("\t%s\tTest %d:\tShould only allow the configured domains.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only allow the configured domains.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package oidctest provides a stand-in OpenID Connect provider to test signing
+// in against.
+package oidctest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/auth"
+	"github.com/AhmedShaef/wakt/business/sys/oidc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// IdP is a stand-in provider that signs in whoever it is told to.
+type IdP struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	keys auth.KeyLookup
+	kid  string
+
+	mu    sync.Mutex
+	codes map[string]grant
+}
+
+// grant is what a provider remembers of an authorization code.
+type grant struct {
+	redirect  string
+	challenge string
+	nonce     string
+	email     string
+	verified  bool
+}
+
+// New starts a stand-in provider that signs ID tokens with the key of the kid,
+// which a keystore.KeyStore can provide. Close must be called once the
+// provider is no longer needed.
+func New(keys auth.KeyLookup, kid, clientID, clientSecret string) (*IdP, error) {
+	if _, err := keys.PrivateKey(kid); err != nil {
+		return nil, fmt.Errorf("key lookup: %w", err)
+	}
+
+	idp := IdP{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		keys:         keys,
+		kid:          kid,
+		codes:        make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+
+	return &idp, nil
+}
+
+// Close shuts the provider down.
+func (idp *IdP) Close() {
+	idp.Server.Close()
+}
+
+// Issuer returns the issuer of the provider.
+func (idp *IdP) Issuer() string {
+	return idp.Server.URL
+}
+
+// Provider returns the settings to sign in with the provider.
+func (idp *IdP) Provider(redirectURL string, allowedDomains ...string) oidc.Provider {
+	return oidc.Provider{
+		Issuer:         idp.Issuer(),
+		ClientID:       idp.ClientID,
+		ClientSecret:   idp.ClientSecret,
+		RedirectURL:    redirectURL,
+		AllowedDomains: allowedDomains,
+	}
+}
+
+// Authorize signs in the user with the email on the authorization URL, like
+// a user would in the browser. It returns the code and the state the provider
+// redirects back with.
+func (idp *IdP) Authorize(authURL, email string, verified bool) (string, string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	q := u.Query()
+
+	if q.Get("client_id") != idp.ClientID {
+		return "", "", errors.New("unknown client")
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		return "", "", errors.New("missing PKCE challenge")
+	}
+
+	code, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	idp.codes[code] = grant{
+		redirect:  q.Get("redirect_uri"),
+		challenge: q.Get("code_challenge"),
+		nonce:     q.Get("nonce"),
+		email:     email,
+		verified:  verified,
+	}
+
+	return code, q.Get("state"), nil
+}
+
+func (idp *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	endpoints := oidc.Endpoints{
+		Issuer:   idp.Issuer(),
+		AuthURL:  idp.Issuer() + "/authorize",
+		TokenURL: idp.Issuer() + "/token",
+		JWKSURL:  idp.Issuer() + "/jwks",
+	}
+	respond(w, endpoints)
+}
+
+func (idp *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	key, err := idp.keys.PublicKey(idp.kid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jwk, err := auth.NewJWK(idp.kid, key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respond(w, auth.JWKS{Keys: []auth.JWK{jwk}})
+}
+
+func (idp *IdP) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	id, _ = url.QueryUnescape(id)
+	secret, _ = url.QueryUnescape(secret)
+	if !ok || id != idp.ClientID || secret != idp.ClientSecret {
+		http.Error(w, "invalid client", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Codes can only be used once.
+	idp.mu.Lock()
+	g, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != g.redirect {
+		http.Error(w, "invalid grant", http.StatusBadRequest)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		http.Error(w, "invalid code verifier", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	claims := oidc.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    idp.Issuer(),
+			Subject:   g.email,
+			Audience:  jwt.ClaimStrings{idp.ClientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Email:         g.email,
+		EmailVerified: g.verified,
+		Nonce:         g.nonce,
+	}
+
+	key, err := idp.keys.PrivateKey(idp.kid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	publicKey, err := idp.keys.PublicKey(idp.kid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	method, err := auth.SigningMethod(publicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = idp.kid
+
+	idToken, err := token.SignedString(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respond(w, struct {
+		IDToken   string `json:"id_token"`
+		TokenType string `json:"token_type"`
+	}{
+		IDToken:   idToken,
+		TokenType: "Bearer",
+	})
+}
+
+// respond sends the value as JSON.
+func respond(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
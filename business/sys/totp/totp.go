@@ -0,0 +1,115 @@
+// Package totp provides support for time-based one-time passwords (RFC 6238)
+// as used by authenticator apps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Set of parameters of the codes, these are the defaults every authenticator
+// app supports.
+const (
+	digits = 6
+	modulo = 1000000
+	period = 30
+	skew   = 1
+)
+
+// encoding is the base32 encoding secrets are shared with, without padding as
+// authenticator apps expect.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret encoded in base32.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generating secret: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// URI returns the provisioning URI of the secret for the account, usually
+// shown as a QR code to enroll an authenticator app.
+func URI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(digits))
+	v.Set("period", fmt.Sprint(period))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// Code returns the code of the secret for the time.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decode(secret)
+	if err != nil {
+		return "", err
+	}
+	return code(key, step(t)), nil
+}
+
+// Validate checks the code against the secret for the time, accepting the
+// codes of the periods right before and after to allow for clock drift. It
+// returns the time step of the code, so it can be kept from being used again.
+func Validate(secret, passcode string, t time.Time) (int64, bool) {
+	key, err := decode(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	passcode = strings.TrimSpace(passcode)
+	if len(passcode) != digits {
+		return 0, false
+	}
+
+	current := step(t)
+	for i := -skew; i <= skew; i++ {
+		s := current + int64(i)
+		if subtle.ConstantTimeCompare([]byte(code(key, s)), []byte(passcode)) == 1 {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
+
+// step returns the time step of the time.
+func step(t time.Time) int64 {
+	return t.Unix() / period
+}
+
+// decode returns the key of a base32 secret.
+func decode(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, fmt.Errorf("decoding secret: %w", err)
+	}
+	return key, nil
+}
+
+// code computes the HOTP value (RFC 4226) of the key for the time step.
+func code(key []byte, s int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(s))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
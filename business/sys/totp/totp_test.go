@@ -0,0 +1,80 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AhmedShaef/wakt/business/sys/totp"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestTOTP(t *testing.T) {
+	t.Log("Given the need to generate and validate one-time passwords.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling the RFC 6238 test vectors.", testID)
+		{
+			// The base32 encoding of the "12345678901234567890" key of the RFC.
+			const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+			tests := []struct {
+				unix int64
+				code string
+			}{
+				{59, "287082"},
+				{1111111109, "081804"},
+				{1234567890, "005924"},
+				{2000000000, "279037"},
+			}
+
+			for _, tt := range tests {
+				got, err := totp.Code(secret, time.Unix(tt.unix, 0))
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to compute the code : %s.", failed, testID, err)
+				}
+				if got != tt.code {
+					t.Fatalf("\t%s\tTest %d:\tShould get code %s at %d : got %s.", failed, testID, tt.code, tt.unix, got)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the codes of the RFC.", success, testID)
+		}
+
+		testID = 1
+		t.Logf("\tTest %d:\tWhen validating a code.", testID)
+		{
+			secret, err := totp.GenerateSecret()
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to generate a secret : %s.", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to generate a secret.", success, testID)
+
+			now := time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+			code, err := totp.Code(secret, now)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to compute the code : %s.", failed, testID, err)
+			}
+
+			if _, ok := totp.Validate(secret, code, now.Add(30*time.Second)); !ok {
+				t.Fatalf("\t%s\tTest %d:\tShould accept the code of the previous period.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould accept the code of the previous period.", success, testID)
+
+			if _, ok := totp.Validate(secret, code, now.Add(2*time.Minute)); ok {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept an old code.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept an old code.", success, testID)
+
+			uri := totp.URI("WAKT", "user@example.com", secret)
+			if !strings.HasPrefix(uri, "otpauth://totp/WAKT:user@example.com?") || !strings.Contains(uri, "secret="+secret) {
+				t.Fatalf("\t%s\tTest %d:\tShould get a provisioning URI : %s.", failed, testID, uri)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a provisioning URI.", success, testID)
+		}
+	}
+}
@@ -1,23 +1,36 @@
 // Package util provides some common functions used in the system.
 package util
 
-// Add bulk tags to a tagSet.
+// Add bulk tags to a tagSet. Tags already in the set are not added twice.
 func Add(oldTag, tags []string) []string {
-	var newTags []string
+	return Unique(append(append([]string{}, oldTag...), tags...))
+}
+
+// Remove bulk tags from a tagSet.
+func Remove(oldTag, tags []string) []string {
+	remove := make(map[string]bool, len(tags))
 	for _, tag := range tags {
-		newTags = append(oldTag, tag)
+		remove[tag] = true
+	}
+
+	newTags := []string{}
+	for _, tag := range oldTag {
+		if !remove[tag] {
+			newTags = append(newTags, tag)
+		}
 	}
 	return newTags
 }
 
-// Remove bulk tags from a tagSet.
-func Remove(oldTag, tags []string) []string {
-	var newTags []string
+// Unique removes the duplicated tags of a tagSet, keeping the first one.
+func Unique(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+
+	newTags := []string{}
 	for _, tag := range tags {
-		for _, oldTag := range oldTag {
-			if oldTag != tag {
-				newTags = append(newTags, tag)
-			}
+		if !seen[tag] {
+			seen[tag] = true
+			newTags = append(newTags, tag)
 		}
 	}
 	return newTags
@@ -0,0 +1,39 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/AhmedShaef/wakt/business/sys/util"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestTags(t *testing.T) {
+	t.Log("Given the need to add and remove tags of a tag set.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen adding tags.", testID)
+		{
+			got := util.Add([]string{"a", "b"}, []string{"b", "c", "c"})
+			if diff := cmp.Diff([]string{"a", "b", "c"}, got); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould add every missing tag once. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould add every missing tag once.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen removing tags.", testID)
+		{
+			got := util.Remove([]string{"a", "b", "c"}, []string{"a", "c", "d"})
+			if diff := cmp.Diff([]string{"b"}, got); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould remove the given tags. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould remove the given tags.", success, testID)
+		}
+	}
+}
@@ -76,6 +76,31 @@ func Check(val interface{}) error {
 	return nil
 }
 
+// CheckVar validates a single value against the declared tag, reporting the
+// failure under the provided field name.
+func CheckVar(field string, val interface{}, tag string) error {
+	if err := validate.Var(val, tag); err != nil {
+
+		// Use a type assertion to get the real error value.
+		verrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
+		}
+
+		var fields FieldErrors
+		for _, verror := range verrors {
+			fields = append(fields, FieldError{
+				Field: field,
+				Error: field + verror.Translate(translator),
+			})
+		}
+
+		return fields
+	}
+
+	return nil
+}
+
 // GenerateID generate a unique id for entities.
 func GenerateID() string {
 	return uuid.NewString()
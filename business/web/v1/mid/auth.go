@@ -6,14 +6,40 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/AhmedShaef/wakt/business/sys/auth"
 	v1Web "github.com/AhmedShaef/wakt/business/web/v1"
 	"github.com/AhmedShaef/wakt/foundation/web"
 )
 
-// Authenticate validates a JWT from the `Authorization` header.
-func Authenticate(a *auth.Auth) web.Middleware {
+// SessionCheck reports an error when the session the claims belong to is no
+// longer valid, like after a password reset.
+type SessionCheck func(ctx context.Context, claims auth.Claims) error
+
+// TokenLookup finds the claims of the user a personal API token belongs to
+// along with the scopes the token was granted.
+type TokenLookup func(ctx context.Context, token string, now time.Time) (auth.Claims, []string, error)
+
+// scopedToken holds the claims of a personal API token until Scope finds the
+// token was granted access to the endpoint.
+type scopedToken struct {
+	claims auth.Claims
+	scopes []string
+}
+
+// ctxKey represents the type of value for the context key.
+type ctxKey int
+
+// scopedKey is used to store/retrieve a scopedToken value from a
+// context.Context.
+const scopedKey ctxKey = 1
+
+// Authenticate validates a JWT from the `Authorization` header. The session
+// checks must pass as well for the token to be accepted. Personal API tokens
+// are found with the lookup instead and go through the same checks, they only
+// reach the endpoints that are wrapped by Scope.
+func Authenticate(a *auth.Auth, lookup TokenLookup, checks ...SessionCheck) web.Middleware {
 
 	// This is the actual middleware function to be executed.
 	m := func(handler web.Handler) web.Handler {
@@ -31,12 +57,46 @@ func Authenticate(a *auth.Auth) web.Middleware {
 				return v1Web.NewRequestError(err, http.StatusUnauthorized)
 			}
 
+			// A JWT always has three parts, anything else is taken to be a
+			// personal API token.
+			if strings.Count(parts[1], ".") != 2 && lookup != nil {
+				v, err := web.GetValues(ctx)
+				if err != nil {
+					return web.NewShutdownError("web value missing from context")
+				}
+
+				claims, scopes, err := lookup(ctx, parts[1], v.Now)
+				if err != nil {
+					return v1Web.NewRequestError(err, http.StatusUnauthorized)
+				}
+
+				// Make sure the user did not revoke every session and token
+				// since the token was created.
+				for _, check := range checks {
+					if err := check(ctx, claims); err != nil {
+						return v1Web.NewRequestError(err, http.StatusUnauthorized)
+					}
+				}
+
+				// Hold the claims back, Scope adds them to the context.
+				ctx = context.WithValue(ctx, scopedKey, scopedToken{claims: claims, scopes: scopes})
+
+				return handler(ctx, w, r)
+			}
+
 			// Validate the token is signed by us.
 			claims, err := a.ValidateToken(parts[1])
 			if err != nil {
 				return v1Web.NewRequestError(err, http.StatusUnauthorized)
 			}
 
+			// Make sure the session was not revoked since the token was issued.
+			for _, check := range checks {
+				if err := check(ctx, claims); err != nil {
+					return v1Web.NewRequestError(err, http.StatusUnauthorized)
+				}
+			}
+
 			// Add claims to the context, so they can be retrieved later.
 			ctx = auth.SetClaims(ctx, claims)
 
@@ -50,6 +110,49 @@ func Authenticate(a *auth.Auth) web.Middleware {
 	return m
 }
 
+// Scope lets personal API tokens that were granted at least one of the
+// specified scopes through to the endpoint. Sessions have access to every
+// endpoint and pass as they are.
+func Scope(scopes ...string) web.Middleware {
+
+	// This is the actual middleware function to be executed.
+	m := func(handler web.Handler) web.Handler {
+
+		// Create the handler that will be attached in the middleware chain.
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+
+			// Sessions already have their claims in the context.
+			if _, err := auth.GetClaims(ctx); err == nil {
+				return handler(ctx, w, r)
+			}
+
+			token, ok := ctx.Value(scopedKey).(scopedToken)
+			if !ok {
+				err := errors.New("authentication is required for that action")
+				return v1Web.NewRequestError(err, http.StatusUnauthorized)
+			}
+
+			for _, has := range token.scopes {
+				for _, want := range scopes {
+					if has == want {
+						ctx = auth.SetClaims(ctx, token.claims)
+						return handler(ctx, w, r)
+					}
+				}
+			}
+
+			return v1Web.NewRequestError(
+				fmt.Errorf("you are not authorized for that action, token scopes[%v] scopes[%v]", token.scopes, scopes),
+				http.StatusForbidden,
+			)
+		}
+
+		return h
+	}
+
+	return m
+}
+
 // Authorize validates that an authenticated user has at least one role from a
 // specified list. This method constructs the actual function that is used.
 func Authorize(roles ...string) web.Middleware {
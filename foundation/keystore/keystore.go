@@ -3,45 +3,123 @@
 package keystore
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
 	"sync"
-
-	"github.com/golang-jwt/jwt/v4"
+	"time"
 )
 
 // KeyStore represents an in memory store implementation of the
-// KeyLookup interface for use with the auth package.
+// KeyLookup interface for use with the auth package. It holds RSA, ECDSA
+// and Ed25519 private keys.
 type KeyStore struct {
 	mu    sync.RWMutex
-	store map[string]*rsa.PrivateKey
+	store map[string]crypto.PrivateKey
 }
 
 // New constructs an empty KeyStore ready for use.
 func New() *KeyStore {
 	return &KeyStore{
-		store: make(map[string]*rsa.PrivateKey),
+		store: make(map[string]crypto.PrivateKey),
 	}
 }
 
 // NewMap constructs a KeyStore with an initial set of keys.
-func NewMap(store map[string]*rsa.PrivateKey) *KeyStore {
+func NewMap(store map[string]crypto.PrivateKey) *KeyStore {
 	return &KeyStore{
 		store: store,
 	}
 }
 
+// ActiveFile is the name of the file in a key directory holding the kid of
+// the key new tokens are signed with. It overrides the configured kid so the
+// active key can be changed without a restart.
+const ActiveFile = "active.kid"
+
+// RetiredFile is the name of the file in a key directory recording when each
+// previous active key was retired, as a JSON object of kid to time. A retired
+// key stops validating tokens once the tokens it signed have expired.
+const RetiredFile = "retired.json"
+
 // NewFS constructs a KeyStore based on a set of PEM files rooted inside
-// a directory. The name of each PEM file will be used as the key id.
+// a directory. The name of each PEM file will be used as the key id. The
+// type of each key is taken from its PEM block.
 // Example: keystore.NewFS(os.DirFS("/zarf/keys/"))
 // Example: /zarf/keys/54bb2165-71e1-41a6-af3e-7da4a0e1e2c1.pem
 func NewFS(fsys fs.FS) (*KeyStore, error) {
-	ks := New()
+	store, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMap(store), nil
+}
+
+// Reload replaces the keys in the store with the set of PEM files rooted
+// inside the directory. Keys whose file was removed stop validating tokens.
+// The store is left untouched when any of the files can't be read.
+func (ks *KeyStore) Reload(fsys fs.FS) error {
+	store, err := load(fsys)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.store = store
+	return nil
+}
+
+// ReadActive returns the kid held by the ActiveFile of the directory, or an
+// empty string when there is no such file.
+func ReadActive(fsys fs.FS) (string, error) {
+	data, err := fs.ReadFile(fsys, ActiveFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading active kid: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ReadRetired returns when the keys of the RetiredFile of the directory were
+// retired by their kid, or an empty set when there is no such file.
+func ReadRetired(fsys fs.FS) (map[string]time.Time, error) {
+	retired := make(map[string]time.Time)
+
+	data, err := fs.ReadFile(fsys, RetiredFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return retired, nil
+		}
+		return nil, fmt.Errorf("reading retired kids: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &retired); err != nil {
+		return nil, fmt.Errorf("decoding retired kids: %w", err)
+	}
+
+	return retired, nil
+}
+
+// load parses the set of PEM files rooted inside the directory.
+func load(fsys fs.FS) (map[string]crypto.PrivateKey, error) {
+	store := make(map[string]crypto.PrivateKey)
 
 	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
@@ -70,12 +148,12 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 			return fmt.Errorf("reading auth private key: %w", err)
 		}
 
-		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		privateKey, err := parsePrivateKey(privatePEM)
 		if err != nil {
-			return fmt.Errorf("parsing auth private key: %w", err)
+			return fmt.Errorf("parsing auth private key %s: %w", fileName, err)
 		}
 
-		ks.store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = privateKey
+		store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = privateKey
 		return nil
 	}
 
@@ -83,11 +161,44 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 		return nil, fmt.Errorf("walking directory: %w", err)
 	}
 
-	return ks, nil
+	return store, nil
+}
+
+// parsePrivateKey parses a PEM encoded private key. RSA keys can be in the
+// PKCS #1 or PKCS #8 form, ECDSA keys in the SEC 1 or PKCS #8 form and
+// Ed25519 keys in the PKCS #8 form.
+func parsePrivateKey(privatePEM []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(privatePEM)
+	if block == nil {
+		return nil, errors.New("key must be PEM encoded")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("PEM type %q is not supported", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("key type %T is not supported", key)
+	}
 }
 
 // Add adds a private key and combination kid to the store.
-func (ks *KeyStore) Add(privateKey *rsa.PrivateKey, kid string) {
+func (ks *KeyStore) Add(privateKey crypto.PrivateKey, kid string) {
 	ks.mu.Lock()
 	defer ks.mu.Unlock()
 
@@ -104,7 +215,7 @@ func (ks *KeyStore) Remove(kid string) {
 
 // PrivateKey searches the key store for a given kid and returns
 // the private key.
-func (ks *KeyStore) PrivateKey(kid string) (*rsa.PrivateKey, error) {
+func (ks *KeyStore) PrivateKey(kid string) (crypto.PrivateKey, error) {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
 
@@ -117,7 +228,7 @@ func (ks *KeyStore) PrivateKey(kid string) (*rsa.PrivateKey, error) {
 
 // PublicKey searches the key store for a given kid and returns
 // the public key.
-func (ks *KeyStore) PublicKey(kid string) (*rsa.PublicKey, error) {
+func (ks *KeyStore) PublicKey(kid string) (crypto.PublicKey, error) {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
 
@@ -125,5 +236,24 @@ func (ks *KeyStore) PublicKey(kid string) (*rsa.PublicKey, error) {
 	if !found {
 		return nil, errors.New("kid lookup failed")
 	}
-	return &privateKey.PublicKey, nil
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key type %T is not supported", privateKey)
+	}
+	return signer.Public(), nil
+}
+
+// KIDs returns the sorted set of key ids in the store.
+func (ks *KeyStore) KIDs() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kids := make([]string, 0, len(ks.store))
+	for kid := range ks.store {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	return kids
 }
@@ -1,8 +1,17 @@
 package keystore_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"embed" // Calls init function.
+	"encoding/pem"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/AhmedShaef/wakt/foundation/keystore"
 )
@@ -35,10 +44,148 @@ func TestRead(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest %d:\tShould be able to find key in store.", success, testID)
 
-			if err := pk.Validate(); err != nil {
+			rsaKey, ok := pk.(*rsa.PrivateKey)
+			if !ok {
+				t.Fatalf("\t%s\tTest %d:\tShould be an RSA key: %T", failed, testID, pk)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be an RSA key.", success, testID)
+
+			if err := rsaKey.Validate(); err != nil {
 				t.Fatalf("\t%s\tTest %d:\tShould be able to validate the key: %v", failed, testID, err)
 			}
 			t.Logf("\t%s\tTest %d:\tShould be able to validate the key.", success, testID)
 		}
 	}
 }
+
+func TestReload(t *testing.T) {
+	t.Log("Given the need to rotate the keys of a key store.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen reloading a directory of keyfile(s).", testID)
+		{
+			pem, err := keyDocs.ReadFile("test.pem")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the key file: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to read the key file.", success, testID)
+
+			ks, err := keystore.NewFS(fstest.MapFS{"old.pem": {Data: pem}})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to construct key store: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to construct key store.", success, testID)
+
+			fsys := fstest.MapFS{
+				"old.pem":           {Data: pem},
+				"new.pem":           {Data: pem},
+				keystore.ActiveFile: {Data: []byte("new\n")},
+			}
+			if err := ks.Reload(fsys); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to reload the key store: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to reload the key store.", success, testID)
+
+			if exp, got := []string{"new", "old"}, ks.KIDs(); len(got) != len(exp) || got[0] != exp[0] || got[1] != exp[1] {
+				t.Logf("\t\tTest %d:\texp: %v", testID, exp)
+				t.Logf("\t\tTest %d:\tgot: %v", testID, got)
+				t.Fatalf("\t%s\tTest %d:\tShould list the new and the old key.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould list the new and the old key.", success, testID)
+
+			kid, err := keystore.ReadActive(fsys)
+			if err != nil || kid != "new" {
+				t.Fatalf("\t%s\tTest %d:\tShould read the active kid: %q %v", failed, testID, kid, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould read the active kid.", success, testID)
+
+			fsys[keystore.RetiredFile] = &fstest.MapFile{Data: []byte(`{"old":"2022-01-02T03:04:05Z"}`)}
+			retired, err := keystore.ReadRetired(fsys)
+			if err != nil || !retired["old"].Equal(time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)) {
+				t.Fatalf("\t%s\tTest %d:\tShould read when the old key was retired: %v %v", failed, testID, retired, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould read when the old key was retired.", success, testID)
+
+			if err := ks.Reload(fstest.MapFS{"new.pem": {Data: []byte("garbage")}}); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould fail to reload an invalid key file.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail to reload an invalid key file.", success, testID)
+
+			if _, err := ks.PrivateKey("old"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the keys after a failed reload: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the keys after a failed reload.", success, testID)
+		}
+	}
+}
+
+func TestKeyTypes(t *testing.T) {
+	t.Log("Given the need to hold keys of different types.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen handling ECDSA and Ed25519 keyfile(s).", testID)
+		{
+			ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create an ECDSA key: %v", failed, testID, err)
+			}
+			ecDER, err := x509.MarshalECPrivateKey(ecKey)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to marshal the ECDSA key: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create an ECDSA key.", success, testID)
+
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create an Ed25519 key: %v", failed, testID, err)
+			}
+			edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to marshal the Ed25519 key: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to create an Ed25519 key.", success, testID)
+
+			rsaPEM, err := keyDocs.ReadFile("test.pem")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the key file: %v", failed, testID, err)
+			}
+
+			ks, err := keystore.NewFS(fstest.MapFS{
+				"rsa.pem":     {Data: rsaPEM},
+				"ecdsa.pem":   {Data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})},
+				"ed25519.pem": {Data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER})},
+			})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to construct key store: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to construct key store.", success, testID)
+
+			if pk, err := ks.PublicKey("rsa"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould find the RSA key: %v", failed, testID, err)
+			} else if _, ok := pk.(*rsa.PublicKey); !ok {
+				t.Fatalf("\t%s\tTest %d:\tShould find the RSA key: %T", failed, testID, pk)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the RSA key.", success, testID)
+
+			if pk, err := ks.PublicKey("ecdsa"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould find the ECDSA key: %v", failed, testID, err)
+			} else if _, ok := pk.(*ecdsa.PublicKey); !ok {
+				t.Fatalf("\t%s\tTest %d:\tShould find the ECDSA key: %T", failed, testID, pk)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the ECDSA key.", success, testID)
+
+			if pk, err := ks.PublicKey("ed25519"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould find the Ed25519 key: %v", failed, testID, err)
+			} else if _, ok := pk.(ed25519.PublicKey); !ok {
+				t.Fatalf("\t%s\tTest %d:\tShould find the Ed25519 key: %T", failed, testID, pk)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the Ed25519 key.", success, testID)
+
+			pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("public")})
+			if _, err := keystore.NewFS(fstest.MapFS{"public.pem": {Data: pub}}); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not accept a public key file.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not accept a public key file.", success, testID)
+		}
+	}
+}
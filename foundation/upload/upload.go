@@ -58,3 +58,29 @@ func Logo(file io.Reader) (name string, err error) {
 
 	return tempFile.Name(), nil
 }
+
+//Receipt upload expense receipt to the server.
+func Receipt(file io.Reader, ext string) (name string, err error) {
+	tempFile, err := ioutil.TempFile("assets", "receipt-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer func(tempFile *os.File) {
+		err := tempFile.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(tempFile)
+
+	fileBytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = tempFile.Write(fileBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return tempFile.Name(), nil
+}
@@ -1,8 +1,11 @@
 package web
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"go.opentelemetry.io/otel"
@@ -43,3 +46,34 @@ func Respond(ctx context.Context, w http.ResponseWriter, data interface{}, statu
 
 	return nil
 }
+
+// RespondCSV converts the records to CSV and sends them to the client as a
+// file attachment with the given name.
+func RespondCSV(ctx context.Context, w http.ResponseWriter, name string, records [][]string, statusCode int) error {
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "foundation.web.respondcsv")
+	span.SetAttributes(attribute.Int("statusCode", statusCode))
+	defer span.End()
+
+	// Set the status code for the request logger middleware.
+	SetStatusCode(ctx, statusCode)
+
+	// Convert the records to CSV.
+	var b bytes.Buffer
+	if err := csv.NewWriter(&b).WriteAll(records); err != nil {
+		return err
+	}
+
+	// Set the content type and headers once we know writing has succeeded.
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+	// Write the status code to the response.
+	w.WriteHeader(statusCode)
+
+	// Send the result back to the client.
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return err
+	}
+
+	return nil
+}